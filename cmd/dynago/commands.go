@@ -0,0 +1,708 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aaronlmathis/dynago/configs"
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/aaronlmathis/dynago/internal/schema"
+	"github.com/aaronlmathis/dynago/internal/service"
+	"github.com/aaronlmathis/dynago/internal/store"
+	"github.com/aaronlmathis/dynago/internal/utils"
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// command is a dynago subcommand invoked as `dynago <name> [flags]`.
+type command struct {
+	usage string                    // One-line description shown in help output
+	run   func(args []string) error // Parses the subcommand flags and executes it
+}
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]command{
+	"check":           {usage: "Verify provider credentials and connectivity without changing DNS", run: runCheck},
+	"delete":          {usage: "Delete the DNS records managed by each enabled provider", run: runDelete},
+	"generate-schema": {usage: "Print a JSON Schema of the config file for editor completion", run: runGenerateSchema},
+	"history":         {usage: "List the IP changes recorded in the history_db database", run: runHistory},
+	"init":            {usage: "Write an annotated sample config file to start from", run: runInit},
+	"list-records":    {usage: "List the DNS records managed by each enabled provider", run: runListRecords},
+	"status":          {usage: "Show whether each provider's DNS record matches the current public IP", run: runStatus},
+}
+
+// usage prints the top-level help text, including the available subcommands.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags]\n       %s <command> [flags]\n\nFlags:\n", os.Args[0], os.Args[0])
+	flag.PrintDefaults()
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(out, "\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-16s %s\n", name, commands[name].usage)
+	}
+}
+
+// loadProviders loads the config at path (see loadConfig) and returns its enabled providers.
+//
+// Logging is limited to errors so subcommand output stays readable.
+func loadProviders(path string) ([]providers.DNSProvider, error) {
+	_, providersList, err := loadConfigAndProviders(path)
+	return providersList, err
+}
+
+// loadConfigAndProviders is like loadProviders but also returns the config.
+func loadConfigAndProviders(path string) (*config.Config, []providers.DNSProvider, error) {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := logger.InitLogger("", "error", "", "", logger.Rotation{}); err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	providersList := service.EnabledProviders(cfg)
+	if len(providersList) == 0 {
+		return nil, nil, fmt.Errorf("no DNS providers enabled in config")
+	}
+	return cfg, providersList, nil
+}
+
+// runListRecords implements `dynago list-records`.
+//
+// It prints the records managed by each enabled provider as a table, or as JSON with --output=json.
+func runListRecords(args []string) error {
+	fs := flag.NewFlagSet("list-records", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path to the configuration file")
+	output := fs.String("output", "table", "Output format: table or json")
+	fs.Parse(args)
+
+	providersList, err := loadProviders(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	records := []providers.DNSRecord{}
+	for _, p := range providersList {
+		list, err := p.ListManagedRecords(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: failed to list records: %w", p.ProviderName(), err)
+		}
+		records = append(records, list...)
+	}
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "table":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PROVIDER\tNAME\tTYPE\tVALUE\tTTL")
+		for _, r := range records {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", r.Provider, r.Name, r.Type, r.Content, r.TTL)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q (expected table or json)", *output)
+	}
+}
+
+// runCheck implements `dynago check`.
+//
+// It calls SelfTest on every enabled provider, or only those named with --provider, and prints a
+// table of the results, then fetches the public IP from the configured IP sources. Each SelfTest
+// and the IP lookup are bounded by service.SelfTestTimeout. No DNS records are changed. Returns an
+// error if any check fails so the process exits non-zero.
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path to the configuration file")
+	provider := fs.String("provider", "", "Comma-separated list of providers to check (default: all enabled)")
+	fs.Parse(args)
+
+	cfg, providersList, err := loadConfigAndProviders(*configPath)
+	if err != nil {
+		return err
+	}
+	if providersList, err = service.FilterProviders(providersList, splitList(*provider)); err != nil {
+		return err
+	}
+
+	failed := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tSTATUS\tERROR")
+	for _, p := range providersList {
+		ctx, cancel := context.WithTimeout(context.Background(), service.SelfTestTimeout)
+		err := p.SelfTest(ctx)
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Fprintf(tw, "%s\tFAIL\t%v\n", p.ProviderName(), err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\tOK\t-\n", p.ProviderName())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	ipCtx, cancel := context.WithTimeout(context.Background(), service.SelfTestTimeout)
+	defer cancel()
+	ip, ipErr := utils.GetCurrentIP(ipCtx, cfg.AllIPSources()...)
+	if ipErr != nil {
+		fmt.Printf("\nIP source: FAIL (%v)\n", ipErr)
+		return fmt.Errorf("failed to get current public IP: %w", ipErr)
+	}
+	fmt.Printf("\nIP source: OK (public IP %s)\n", ip)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d providers failed the check", failed, len(providersList))
+	}
+	return nil
+}
+
+// runDelete implements `dynago delete`.
+//
+// It removes every record managed by the enabled providers, e.g. when decommissioning a host.
+// Without --yes it only prints the records that would be deleted.
+func runDelete(args []string) error {
+	fs := flag.NewFlagSet("delete", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path to the configuration file")
+	provider := fs.String("provider", "", "Comma-separated list of providers to clean up (default: all enabled)")
+	yes := fs.Bool("yes", false, "Delete the records instead of only listing them")
+	fs.Parse(args)
+
+	providersList, err := loadProviders(*configPath)
+	if err != nil {
+		return err
+	}
+	providersList, err = service.FilterProviders(providersList, splitList(*provider))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	return deleteRecords(ctx, os.Stdout, providersList, *yes)
+}
+
+// deleteRecords deletes the managed records of each provider if yes is true, or only lists them
+// otherwise, reporting progress to w.
+//
+// Returns an error if any provider's records cannot be listed or any record cannot be deleted.
+func deleteRecords(ctx context.Context, w io.Writer, providersList []providers.DNSProvider, yes bool) error {
+	failed := 0
+	for _, p := range providersList {
+		records, err := p.ListManagedRecords(ctx)
+		if err != nil {
+			failed++
+			fmt.Fprintf(w, "%s: FAIL (failed to list records: %v)\n", p.ProviderName(), err)
+			continue
+		}
+		if len(records) == 0 {
+			fmt.Fprintf(w, "%s: no managed records\n", p.ProviderName())
+		}
+		for _, r := range records {
+			if !yes {
+				fmt.Fprintf(w, "%s: would delete %s %s (%s)\n", p.ProviderName(), r.Type, r.Name, r.Content)
+				continue
+			}
+			if err := p.DeleteRecord(ctx, r.Name, r.Type); err != nil {
+				failed++
+				fmt.Fprintf(w, "%s: FAIL deleting %s %s (%v)\n", p.ProviderName(), r.Type, r.Name, err)
+				continue
+			}
+			fmt.Fprintf(w, "%s: deleted %s %s\n", p.ProviderName(), r.Type, r.Name)
+		}
+	}
+	if !yes {
+		fmt.Fprintln(w, "Re-run with --yes to delete these records.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d deletion(s) failed", failed)
+	}
+	return nil
+}
+
+// statusTimeout bounds the public IP lookups and each provider's record lookups in `dynago status`.
+const statusTimeout = 10 * time.Second
+
+// recordStatus is one row of `dynago status`: the state of one of a provider's records.
+type recordStatus struct {
+	Provider   string    `json:"provider"`
+	Record     string    `json:"record"`
+	DNSIP      string    `json:"dns_ip"`
+	TTL        int64     `json:"ttl,omitempty"` // Seconds; 0 if unknown
+	PublicIP   string    `json:"public_ip"`
+	Match      bool      `json:"match"`
+	LastUpdate time.Time `json:"last_update,omitzero"`
+	// ConsecutiveErrors is the running service's count, or nil if it could not be asked.
+	ConsecutiveErrors *int   `json:"consecutive_errors,omitempty"`
+	Error             string `json:"error,omitempty"`
+}
+
+// recordView is a provider, or one record type of a provider managing several, as the service
+// reconciles it.
+type recordView struct {
+	provider   providers.DNSProvider
+	recordType string
+}
+
+// runStatus implements `dynago status`.
+//
+// It fetches the public IP, reads the records of each enabled provider concurrently with
+// GetRecordIP (see checkRecords), and reports whether each record matches, one row per record. The lookups are each bounded by
+// statusTimeout. The table follows each provider's (redacted) configuration; --output=json prints
+// a JSON array instead. AAAA records are compared with the ip_source_v6 address (or the ip_source
+// address if it is IPv6 and ip_source_v6 is not set), and records with a static target, such as
+// Route53 ALIAS records, with that target.
+//
+// The last update comes from history_db when it is set, and otherwise from the running service or
+// the provider. With probes.addr set, consecutive errors and the IP source latency are read from
+// the running service's /status; a service that cannot be reached only leaves them out.
+//
+// Returns an error, so the command exits non-zero, if the public IP cannot be fetched or any
+// record is stale or cannot be read.
+func runStatus(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path to the configuration file")
+	output := fs.String("output", "table", "Output format: table or json")
+	fs.Parse(args)
+
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q (expected table or json)", *output)
+	}
+	cfg, providersList, err := loadConfigAndProviders(*configPath)
+	if err != nil {
+		return err
+	}
+
+	ipCtx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	publicIP, ipErr := utils.GetCurrentIP(ipCtx, cfg.AllIPSources()...)
+	var publicIPv6 string
+	if cfg.IPSourceV6 != "" {
+		publicIPv6, _ = utils.GetCurrentIP(ipCtx, cfg.IPSourceV6) // AAAA records are reported stale without it
+	} else if ip := net.ParseIP(publicIP); ip != nil && ip.To4() == nil {
+		publicIPv6 = publicIP
+	}
+	cancel()
+	var svc *service.Status
+	var svcErr error
+	if cfg.Probes.Addr != "" {
+		svc, svcErr = fetchStatus(context.Background(), cfg.Probes.Addr)
+	}
+	var history *store.HistoryStore
+	if _, err := os.Stat(cfg.HistoryDB); cfg.HistoryDB != "" && err == nil {
+		if history, err = store.NewHistoryStore(cfg.HistoryDB); err != nil {
+			return fmt.Errorf("failed to open history_db: %w", err)
+		}
+		defer history.Close()
+	}
+
+	views := recordViews(providersList)
+	rowsByView := make([][]recordStatus, len(views))
+	var wg sync.WaitGroup
+	for i, v := range views {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ip := publicIP
+			if v.recordType == "AAAA" {
+				ip = publicIPv6
+			}
+			rowsByView[i] = checkRecords(v.provider, ip)
+		}()
+	}
+	wg.Wait()
+	var rows []recordStatus
+	for _, r := range rowsByView {
+		rows = append(rows, r...)
+	}
+
+	stale := 0
+	for i := range rows {
+		annotateStatus(&rows[i], history, svc)
+		if !rows[i].Match {
+			stale++
+		}
+	}
+
+	if *output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rows); err != nil {
+			return err
+		}
+	} else {
+		if err := printStatusTable(providersList, rows); err != nil {
+			return err
+		}
+		if cfg.Probes.Addr != "" {
+			fmt.Println()
+			printIPSourceLatency(svc, svcErr)
+		}
+	}
+
+	if ipErr != nil {
+		return fmt.Errorf("failed to get current public IP: %w", ipErr)
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d of %d records are stale or could not be read", stale, len(rows))
+	}
+	return nil
+}
+
+// recordViews returns a view of each provider, split into one view per record type for providers
+// managing several (see providers.AddressFamilies), like the service reconciles them.
+func recordViews(providersList []providers.DNSProvider) []recordView {
+	var views []recordView
+	for _, p := range providersList {
+		af, ok := p.(providers.AddressFamilies)
+		if !ok {
+			views = append(views, recordView{provider: p, recordType: "A"})
+			continue
+		}
+		types := af.RecordTypes()
+		if len(types) == 1 {
+			views = append(views, recordView{provider: p, recordType: types[0]})
+			continue
+		}
+		for _, recordType := range types {
+			views = append(views, recordView{provider: af.ForRecordType(recordType), recordType: recordType})
+		}
+	}
+	return views
+}
+
+// checkRecords reads p's record with GetRecordIP, bounded by statusTimeout, and compares it with
+// publicIP, or with p's static target if it has one. Providers naming several records (see
+// providers.RecordNamer) also list them, so each gets its own row. If a lookup fails, a row reports
+// the error.
+func checkRecords(p providers.DNSProvider, publicIP string) []recordStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+	want := publicIP
+	if st, ok := p.(providers.StaticTarget); ok {
+		if target, ok := st.StaticTarget(); ok {
+			want = target
+		}
+	}
+	row := func(record providers.DNSRecord) recordStatus {
+		return recordStatus{
+			Provider:   p.ProviderName(),
+			Record:     record.Name,
+			DNSIP:      record.Content,
+			TTL:        record.TTL,
+			PublicIP:   publicIP,
+			Match:      want != "" && record.Content == want,
+			LastUpdate: record.LastUpdated,
+		}
+	}
+	failed := func(err error) recordStatus {
+		return recordStatus{Provider: p.ProviderName(), PublicIP: publicIP, Error: err.Error()}
+	}
+
+	first, err := p.GetRecordIP(ctx)
+	if err != nil {
+		return []recordStatus{failed(err)}
+	}
+	rows := []recordStatus{row(*first)}
+	if namer, ok := p.(providers.RecordNamer); !ok || len(namer.RecordNames()) < 2 {
+		return rows
+	}
+	records, err := p.ListManagedRecords(ctx)
+	if err != nil {
+		return append(rows, failed(err))
+	}
+	for _, record := range records {
+		if record.Name == first.Name && record.Type == first.Type {
+			continue
+		}
+		rows = append(rows, row(record))
+	}
+	return rows
+}
+
+// annotateStatus sets row's last update from history, if it has a change for the provider, or else
+// from the running service's svc, and its consecutive errors from svc. Either may be nil.
+func annotateStatus(row *recordStatus, history *store.HistoryStore, svc *service.Status) {
+	var fromHistory bool
+	if history != nil {
+		if e, ok, err := history.Latest(row.Provider); err == nil && ok {
+			row.LastUpdate, fromHistory = e.Time, true
+		}
+	}
+	if svc == nil {
+		return
+	}
+	st := svc.Providers[row.Provider]
+	if !fromHistory && !st.LastUpdate.IsZero() {
+		row.LastUpdate = st.LastUpdate
+	}
+	row.ConsecutiveErrors = &st.Errors
+}
+
+// printStatusTable prints the configuration of each provider, then rows as a table.
+func printStatusTable(providersList []providers.DNSProvider, rows []recordStatus) error {
+	for _, p := range providersList {
+		fmt.Printf("%s: %s\n", p.ProviderName(), formatProviderConfig(p.ProviderConfig()))
+	}
+	fmt.Println()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tRECORD\tDNS IP\tTTL\tPUBLIC IP\tMATCH\tLAST UPDATE\tERRORS")
+	for _, r := range rows {
+		dnsIP, ttl, match, updated, errs := r.DNSIP, "-", "no", "-", "-"
+		if r.Error != "" {
+			dnsIP = "ERROR: " + r.Error
+		}
+		if r.TTL > 0 {
+			ttl = strconv.FormatInt(r.TTL, 10)
+		}
+		if r.Match {
+			match = "yes"
+		}
+		if !r.LastUpdate.IsZero() {
+			updated = r.LastUpdate.Local().Format(time.RFC3339)
+		}
+		if r.ConsecutiveErrors != nil {
+			errs = strconv.Itoa(*r.ConsecutiveErrors)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Provider, orDash(r.Record), dnsIP, ttl, orDash(r.PublicIP), match, updated, errs)
+	}
+	return tw.Flush()
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// printIPSourceLatency prints the IP source latency in status, as fetched from the running
+// service's /status, or why it is unavailable if err is set.
+func printIPSourceLatency(status *service.Status, err error) {
+	if err != nil {
+		fmt.Printf("IP source latency: unavailable (%v)\n", err)
+		return
+	}
+	l := status.IPSourceLatency
+	if l.Samples == 0 {
+		fmt.Println("IP source latency: no lookups yet")
+		return
+	}
+	fmt.Printf("IP source latency (last %d lookups): mean %.0fms, p50 %.0fms, p95 %.0fms, p99 %.0fms\n",
+		l.Samples, l.Mean, l.P50, l.P95, l.P99)
+}
+
+// fetchStatus gets /status from the probe server listening on addr. A listen address without a
+// host, or with an unspecified one such as 0.0.0.0, is reached on localhost.
+func fetchStatus(ctx context.Context, addr string) (*service.Status, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+net.JoinHostPort(host, port)+"/status", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", req.URL, resp.Status)
+	}
+	var status service.Status
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", req.URL, err)
+	}
+	return &status, nil
+}
+
+// runHistory implements `dynago history`.
+//
+// It prints the IP changes recorded in the history_db database, oldest first, as a table or as
+// JSON with --output=json. --provider limits them to one provider and --since to recent changes.
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	configPath := fs.String("config", defaultConfigPath, "Path to the configuration file")
+	provider := fs.String("provider", "", "Only show changes to this provider's records")
+	sinceFlag := fs.String("since", "", "Only show changes in this period, e.g. 7d or 12h, or since a date such as 2025-06-01 (default: all)")
+	output := fs.String("output", "table", "Output format: table or json")
+	fs.Parse(args)
+
+	since, err := parseSince(*sinceFlag, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unknown output format %q (expected table or json)", *output)
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.HistoryDB == "" {
+		return fmt.Errorf("history_db is not set in the configuration")
+	}
+	if _, err := os.Stat(cfg.HistoryDB); err != nil {
+		return fmt.Errorf("no history recorded yet: %w", err)
+	}
+	history, err := store.NewHistoryStore(cfg.HistoryDB)
+	if err != nil {
+		return fmt.Errorf("failed to open history_db: %w", err)
+	}
+	defer history.Close()
+
+	entries, err := history.QueryHistory(*provider, since)
+	if err != nil {
+		return fmt.Errorf("failed to query history: %w", err)
+	}
+	if *output == "json" {
+		if entries == nil {
+			entries = []store.HistoryEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tPROVIDER\tRECORD\tOLD IP\tNEW IP")
+	for _, e := range entries {
+		oldIP := e.OldIP
+		if oldIP == "" {
+			oldIP = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", e.Time.Local().Format(time.RFC3339), e.Provider, e.Record, oldIP, e.NewIP)
+	}
+	return tw.Flush()
+}
+
+// parseSince converts a --since value into the time to list changes from: a Go duration or a number
+// of days such as "7d" before now, or a date (2006-01-02, local time) or RFC 3339 time.
+// An empty value returns the zero time, which matches every change.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("%q is not a number of days", value)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("%q is a negative duration", value)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration such as 7d or 12h nor a date", value)
+}
+
+// runGenerateSchema implements `dynago generate-schema`.
+//
+// It writes a JSON Schema of the config file to stdout, or to the file given with --output.
+func runGenerateSchema(args []string) error {
+	fs := flag.NewFlagSet("generate-schema", flag.ExitOnError)
+	output := fs.String("output", "", "File to write the schema to (default: stdout)")
+	fs.Parse(args)
+
+	doc, err := schema.Generate()
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %w", err)
+	}
+	doc = append(doc, '\n')
+	if *output == "" {
+		_, err = os.Stdout.Write(doc)
+		return err
+	}
+	return os.WriteFile(*output, doc, 0o644)
+}
+
+// runInit implements `dynago init`.
+//
+// It writes the annotated sample config to dynago.yml, or to --output. An existing file is only
+// replaced with --force.
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	output := fs.String("output", "dynago.yml", "File to write the sample config to")
+	force := fs.Bool("force", false, "Overwrite the file if it already exists")
+	fs.Parse(args)
+
+	if err := configs.WriteExample(*output, *force); err != nil {
+		return fmt.Errorf("failed to write sample config: %w", err)
+	}
+	fmt.Printf("Wrote sample config to %s; edit the provider settings, then run: dynago -config=%s\n", *output, *output)
+	return nil
+}
+
+// formatProviderConfig renders a ProviderConfig map as space-separated key=value pairs sorted by key.
+// Empty values are omitted.
+func formatProviderConfig(cfg map[string]string) string {
+	keys := make([]string, 0, len(cfg))
+	for k, v := range cfg {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + cfg[k]
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,240 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// mockProvider is a DNSProvider whose managed records are held in memory.
+type mockProvider struct {
+	name      string
+	names     []string // Returned by RecordNames
+	records   []providers.DNSRecord
+	listErr   error
+	deleteErr error
+	deleted   []string // "TYPE name" of each deleted record
+}
+
+func (m *mockProvider) GetRecordIP(ctx context.Context) (*providers.DNSRecord, error) {
+	if len(m.records) == 0 {
+		return nil, errors.New("not found")
+	}
+	return &m.records[0], nil
+}
+func (m *mockProvider) UpdateRecordIP(ctx context.Context, ip string) error { return nil }
+func (m *mockProvider) GetRecordTTL(ctx context.Context) (int64, error)     { return 300, nil }
+func (m *mockProvider) DeleteRecord(ctx context.Context, name, recordType string) error {
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	m.deleted = append(m.deleted, recordType+" "+name)
+	return nil
+}
+func (m *mockProvider) ListManagedRecords(ctx context.Context) ([]providers.DNSRecord, error) {
+	return m.records, m.listErr
+}
+func (m *mockProvider) RecordNames() []string                 { return m.names }
+func (m *mockProvider) SelfTest(ctx context.Context) error    { return nil }
+func (m *mockProvider) HealthCheck(ctx context.Context) error { return nil }
+func (m *mockProvider) ProviderName() string                  { return m.name }
+func (m *mockProvider) Close() error                          { return nil }
+func (m *mockProvider) ProviderConfig() map[string]string     { return nil }
+
+// TestCheckRecords checks that every managed record gets its own row, so a stale second record is
+// reported even when the first one matches.
+func TestCheckRecords(t *testing.T) {
+	p := &mockProvider{name: "mock", names: []string{"a.example.com", "b.example.com"}, records: []providers.DNSRecord{
+		{Name: "a.example.com", Type: "A", Content: "5.6.7.8", TTL: 300},
+		{Name: "b.example.com", Type: "A", Content: "1.2.3.4"},
+	}}
+	rows := checkRecords(p, "5.6.7.8")
+	if len(rows) != 2 {
+		t.Fatalf("expected a row per record, got %+v", rows)
+	}
+	if !rows[0].Match || rows[1].Match || rows[1].Record != "b.example.com" || rows[1].DNSIP != "1.2.3.4" {
+		t.Errorf("expected only a.example.com to match, got %+v", rows)
+	}
+	if rows[0].TTL != 300 {
+		t.Errorf("expected the record's TTL in its row, got %d", rows[0].TTL)
+	}
+
+	p.listErr = errors.New("boom")
+	rows = checkRecords(p, "5.6.7.8")
+	if len(rows) != 2 || !rows[0].Match || rows[1].Error == "" {
+		t.Errorf("expected the first record and a failed row, got %+v", rows)
+	}
+
+	for _, p := range []*mockProvider{{name: "empty"}, {name: "failing", listErr: errors.New("boom")}} {
+		rows := checkRecords(p, "5.6.7.8")
+		if len(rows) != 1 || rows[0].Match || rows[0].Error == "" {
+			t.Errorf("%s: expected a single failed row, got %+v", p.name, rows)
+		}
+	}
+}
+
+// TestCheckRecords_SingleRecord checks that a provider managing one record is only read with
+// GetRecordIP.
+func TestCheckRecords_SingleRecord(t *testing.T) {
+	p := &mockProvider{name: "mock", names: []string{"a.example.com"}, listErr: errors.New("not called"),
+		records: []providers.DNSRecord{{Name: "a.example.com", Type: "A", Content: "5.6.7.8"}}}
+	rows := checkRecords(p, "5.6.7.8")
+	if len(rows) != 1 || !rows[0].Match || rows[0].Error != "" {
+		t.Errorf("expected a single matching row, got %+v", rows)
+	}
+}
+
+// deleteTestProviders returns two providers managing a record each.
+func deleteTestProviders() (*mockProvider, *mockProvider) {
+	return &mockProvider{name: "first", records: []providers.DNSRecord{{Name: "a.example.com", Type: "A", Content: "1.2.3.4"}}},
+		&mockProvider{name: "second", records: []providers.DNSRecord{
+			{Name: "b.example.com", Type: "A", Content: "1.2.3.4"},
+			{Name: "b.example.com", Type: "AAAA", Content: "2001:db8::1"},
+		}}
+}
+
+func TestDeleteRecords_PreviewDeletesNothing(t *testing.T) {
+	first, second := deleteTestProviders()
+	var out bytes.Buffer
+	if err := deleteRecords(context.Background(), &out, []providers.DNSProvider{first, second}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.deleted) != 0 || len(second.deleted) != 0 {
+		t.Errorf("expected nothing to be deleted without --yes, got %v and %v", first.deleted, second.deleted)
+	}
+	if got := strings.Count(out.String(), "would delete"); got != 3 {
+		t.Errorf("expected 3 records to be listed, got %d:\n%s", got, out.String())
+	}
+}
+
+func TestDeleteRecords_DeletesEveryRecord(t *testing.T) {
+	first, second := deleteTestProviders()
+	if err := deleteRecords(context.Background(), io.Discard, []providers.DNSProvider{first, second}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(first.deleted, ",") != "A a.example.com" {
+		t.Errorf("unexpected deletions for first: %v", first.deleted)
+	}
+	if strings.Join(second.deleted, ",") != "A b.example.com,AAAA b.example.com" {
+		t.Errorf("unexpected deletions for second: %v", second.deleted)
+	}
+}
+
+func TestDeleteRecords_ProviderErrors(t *testing.T) {
+	first, second := deleteTestProviders()
+	first.listErr = errors.New("boom")
+	second.deleteErr = errors.New("denied")
+	err := deleteRecords(context.Background(), io.Discard, []providers.DNSProvider{first, second}, true)
+	if err == nil || !strings.Contains(err.Error(), "3 deletion(s) failed") {
+		t.Errorf("expected an error for the failed listing and both failed deletions, got %v", err)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"0d", now},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2025-06-01", time.Date(2025, 6, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-06-01T08:30:00Z", time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)},
+	} {
+		got, err := parseSince(tc.value, now)
+		if err != nil {
+			t.Errorf("parseSince(%q): unexpected error: %v", tc.value, err)
+		} else if !got.Equal(tc.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestParseSince_Rejects(t *testing.T) {
+	now := time.Now()
+	for _, value := range []string{"-1h", "-3d", "xd", "7 days", "yesterday", "2025-13-01"} {
+		if got, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q): expected an error, got %v", value, got)
+		}
+	}
+}
+
+// clearDynagoEnv unsets every DYNAGO_* variable for the duration of the test.
+func clearDynagoEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "DYNAGO_") {
+			t.Setenv(name, "") // Restores the value after the test
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestLoadConfig_FallsBackToEnvironment(t *testing.T) {
+	clearDynagoEnv(t)
+	t.Chdir(t.TempDir()) // No configs/dynago.yml here
+	for name, value := range map[string]string{
+		"DYNAGO_INTERVAL":               "5m",
+		"DYNAGO_IP_SOURCE":              "https://api.ipify.org",
+		"DYNAGO_CLOUDFLARE_API_TOKEN":   "cf-token",
+		"DYNAGO_CLOUDFLARE_ZONE_ID":     "cf-zone",
+		"DYNAGO_CLOUDFLARE_RECORD_NAME": "home.example.com",
+		"DYNAGO_CLOUDFLARE_RECORD_TYPE": "A",
+	} {
+		t.Setenv(name, value)
+	}
+
+	cfg, err := loadConfig(defaultConfigPath)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.Path != "" {
+		t.Errorf("expected a config from the environment, got one from %q", cfg.Path)
+	}
+}
+
+func TestLoadConfig_MissingFile(t *testing.T) {
+	clearDynagoEnv(t)
+	t.Chdir(t.TempDir())
+
+	// The default path falls back to the environment only when DYNAGO_* variables are set.
+	if _, err := loadConfig(defaultConfigPath); err == nil || !strings.Contains(err.Error(), `pass -config=""`) {
+		t.Errorf("expected a missing file error without DYNAGO_* variables, got %v", err)
+	}
+
+	// A path given with -config never falls back.
+	t.Setenv("DYNAGO_INTERVAL", "5m")
+	t.Setenv("DYNAGO_IP_SOURCE", "https://api.ipify.org")
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "dynago.yml")); err == nil || !strings.Contains(err.Error(), `pass -config=""`) {
+		t.Errorf("expected a missing file error for an explicit path, got %v", err)
+	}
+}
@@ -25,10 +25,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/aaronlmathis/dynago/internal/config"
@@ -42,27 +45,52 @@ var (
 	GitCommit  = "none"    // Git commit hash (set at build time)
 	ConfigPath string      // Path to the configuration file
 	LogFile    string      // Path to the log file (optional)
+	LogFormat  string      // Console log format, overriding log_format when set
+	DryRun     bool        // Log planned updates without writing to DNS
+	Once       bool        // Run a single update cycle and exit
+	Force      bool        // Update records even if they already match the current IP
+	Provider   string      // Comma-separated provider names to run (empty runs all)
+
+	allowShortInterval bool // Accept intervals below config.MinInterval (for testing)
 )
 
 // run loads configuration, initializes logging, and starts the DNS update service.
 //
 // Returns an error if configuration or logger initialization fails, or if the service fails to start.
 func run() error {
-	// Load the configuration from the config file.
-	cfg, err := config.LoadConfig(ConfigPath)
+	// Load the configuration from the config file, or from the environment without one.
+	cfg, err := loadConfig(ConfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if DryRun {
+		cfg.DryRun = true
+	}
+	cfg.Once = Once
+	cfg.ForceUpdate = Force
+	cfg.OnlyProviders = splitList(Provider)
 
-	// Initialize the logger with the configured log level
+	// Initialize the logger with the configured log level and format
 	// and log file path from the configuration.
-	if err := logger.InitLogger(LogFile, cfg.LogLevel); err != nil {
+	if LogFormat != "" {
+		cfg.LogFormat = LogFormat
+	}
+	rotation := logger.Rotation{
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		Compress:   cfg.LogCompressBackups,
+	}
+	if err := logger.InitLogger(LogFile, cfg.LogLevel, cfg.LogFormat, cfg.LogTarget, rotation); err != nil {
 		return fmt.Errorf("failed to initialize logger: %w", err)
 
 	}
 
 	logger.Debug("Starting dynago version %s (built at %s, commit %s)", Version, BuildTime, GitCommit)
-	logger.Debug("Configuration loaded from %s", ConfigPath)
+	if cfg.Path != "" {
+		logger.Debug("Configuration loaded from %s", cfg.Path)
+	} else {
+		logger.Debug("Configuration loaded from DYNAGO_* environment variables")
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -84,18 +112,82 @@ func run() error {
 
 }
 
+// defaultConfigPath is the config file used when -config is not given.
+const defaultConfigPath = "configs/dynago.yml"
+
+// loadConfig loads the config file at path, or builds the config from the environment with
+// config.LoadFromEnv if path is empty. It also falls back to the environment when path is the
+// default, no file exists there, and a DYNAGO_* variable is set; a missing file that was asked for
+// is an error. -allow-short-interval applies to either.
+func loadConfig(path string) (*config.Config, error) {
+	opt := config.WithAllowShortInterval(allowShortInterval)
+	if path == "" {
+		return config.LoadFromEnv(opt)
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if path != defaultConfigPath || !hasDynagoEnv() {
+			return nil, fmt.Errorf("config file %s does not exist (pass -config=\"\" to configure dynago from DYNAGO_* environment variables)", path)
+		}
+		cfg, envErr := config.LoadFromEnv(opt)
+		if envErr != nil {
+			return nil, fmt.Errorf("config file %s does not exist, and the environment does not configure dynago: %w", path, envErr)
+		}
+		return cfg, nil
+	}
+	return config.LoadConfig(path, opt)
+}
+
+// hasDynagoEnv reports whether any DYNAGO_* environment variable is set.
+func hasDynagoEnv() bool {
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "DYNAGO_") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated flag value, trimming spaces and dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // main is the entry point for the dynago application.
 //
-// It parses command-line flags, then calls run(). If an error occurs, it prints the error and exits with status 1.
+// If the first argument names a subcommand, that subcommand is run instead of the service.
+// Otherwise it parses command-line flags, then calls run(). If an error occurs, it prints the error and exits with status 1.
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	versionFlag := flag.Bool("version", false, "print version information and exit")
 	if *versionFlag {
 		fmt.Printf("dynago %s (commit %s, built %s)\n", Version, GitCommit, BuildTime)
 		os.Exit(0)
 	}
 
-	flag.StringVar(&ConfigPath, "config", "configs/dynago.yml", "Path to the configuration file")
+	flag.Usage = usage
+	flag.StringVar(&ConfigPath, "config", defaultConfigPath, "Path to the configuration file (\"\" to configure from DYNAGO_* environment variables)")
 	flag.StringVar(&LogFile, "log", "", "Path to the log file (optional, defaults to stdout)")
+	flag.StringVar(&LogFormat, "log-format", "", "Console log format: pretty or json (default: log_format from the config)")
+	flag.BoolVar(&DryRun, "dry-run", false, "Log planned DNS updates without applying them")
+	flag.BoolVar(&Once, "once", false, "Run a single update cycle and exit (for cron)")
+	flag.StringVar(&Provider, "provider", "", "Comma-separated list of providers to run (default: all enabled)")
+	flag.BoolVar(&Force, "force", false, "Push the current IP to every record without checking it first (best with -once)")
+	flag.BoolVar(&allowShortInterval, "allow-short-interval", false, "Accept intervals shorter than 60s (for testing only)")
 	flag.Parse()
 	if err := run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
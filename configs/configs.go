@@ -0,0 +1,55 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package configs holds the annotated sample config file, example-dynago.yml, written by `dynago init`.
+package configs
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Example is the content of example-dynago.yml: every config setting with its default value and
+// a comment, an enabled Cloudflare provider, and a disabled Route53 provider.
+//
+//go:embed example-dynago.yml
+var Example []byte
+
+// WriteExample writes Example to path. It refuses to replace an existing file unless force is true.
+func WriteExample(path string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	// The sample holds credential placeholders that users replace with secrets, so keep it private.
+	f, err := os.OpenFile(path, flags, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(Example); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,82 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package configs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	cfprovider "github.com/aaronlmathis/dynago/providers/cloudflare"
+	r53provider "github.com/aaronlmathis/dynago/providers/route53"
+)
+
+func TestWriteExample_LoadsWithLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := WriteExample(path, false); err != nil {
+		t.Fatalf("WriteExample: %v", err)
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig rejected the sample config: %v", err)
+	}
+	cf, err := cfprovider.New(cfg.Providers["cloudflare"])
+	if err != nil {
+		t.Fatalf("cloudflare section: %v", err)
+	}
+	if !cf.Cfg.Enabled {
+		t.Error("expected the sample to enable cloudflare")
+	}
+	r53, err := r53provider.New(cfg.Providers["route53"])
+	if err != nil {
+		t.Fatalf("route53 section: %v", err)
+	}
+	if r53.Cfg.Enabled {
+		t.Error("expected the sample to disable route53")
+	}
+	if cfg.ProviderTimeout != config.DefaultProviderTimeout || cfg.CircuitBreakerThreshold != config.DefaultCircuitBreakerThreshold {
+		t.Errorf("expected the sample to use the defaults, got provider_timeout=%s circuit_breaker_threshold=%d", cfg.ProviderTimeout, cfg.CircuitBreakerThreshold)
+	}
+}
+
+func TestWriteExample_RefusesToOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte("interval: 1h\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteExample(path, false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected an existing file to be refused, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "interval: 1h\n" {
+		t.Errorf("existing file was modified: %q", data)
+	}
+
+	if err := WriteExample(path, true); err != nil {
+		t.Fatalf("WriteExample with force: %v", err)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, Example) {
+		t.Error("expected --force to replace the file with the sample")
+	}
+}
@@ -0,0 +1,202 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package breaker implements a three-state circuit breaker used to stop calling a failing DNS provider.
+//
+// A breaker starts Closed and lets every call through. After FailureThreshold consecutive failures it
+// opens and rejects calls until RecoveryTimeout has elapsed, then moves to HalfOpen to let a single trial
+// call through. SuccessThreshold consecutive successes close it again; any failure while HalfOpen reopens it.
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// State is the state of a CircuitBreaker.
+type State int
+
+const (
+	Closed   State = iota // Calls are allowed; failures are counted
+	Open                  // Calls are rejected until the recovery timeout elapses
+	HalfOpen              // One trial call at a time probes whether the provider has recovered
+)
+
+// String returns the state name.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "Closed"
+	case Open:
+		return "Open"
+	case HalfOpen:
+		return "HalfOpen"
+	default:
+		return "Unknown"
+	}
+}
+
+// Default settings used when a CircuitBreaker field is left at zero.
+const (
+	DefaultFailureThreshold = 5
+	DefaultSuccessThreshold = 1
+	DefaultRecoveryTimeout  = 5 * time.Minute
+)
+
+// CircuitBreaker tracks consecutive failures for a single provider.
+//
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	Name             string        // Provider name used in log messages
+	FailureThreshold int           // Consecutive failures that open the breaker
+	SuccessThreshold int           // Consecutive HalfOpen successes that close the breaker
+	RecoveryTimeout  time.Duration // How long the breaker stays Open before allowing a trial
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	successes int
+	openedAt  time.Time
+	trial     bool             // A HalfOpen trial call is in flight
+	now       func() time.Time // Clock, replaceable in tests
+}
+
+// New creates a Closed CircuitBreaker for the named provider with the default settings.
+func New(name string) *CircuitBreaker {
+	return &CircuitBreaker{
+		Name:             name,
+		FailureThreshold: DefaultFailureThreshold,
+		SuccessThreshold: DefaultSuccessThreshold,
+		RecoveryTimeout:  DefaultRecoveryTimeout,
+	}
+}
+
+// State returns the current state, moving Open to HalfOpen if the recovery timeout has elapsed.
+func (b *CircuitBreaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkRecovery()
+	return b.state
+}
+
+// Allow reports whether a call to the provider should be attempted.
+//
+// It returns false while the breaker is Open. While HalfOpen it admits one trial call and rejects
+// others until that call's result is recorded.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.checkRecovery()
+	switch b.state {
+	case Open:
+		return false
+	case HalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+	}
+	return true
+}
+
+// RecordSuccess records a successful call.
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trial = false
+	if b.state != HalfOpen {
+		return
+	}
+	b.successes++
+	if b.successes >= b.successThreshold() {
+		b.transition(Closed)
+	}
+}
+
+// RecordFailure records a failed call.
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	switch b.state {
+	case HalfOpen:
+		b.transition(Open)
+	case Closed:
+		b.failures++
+		if b.failures >= b.failureThreshold() {
+			b.transition(Open)
+		}
+	}
+}
+
+// checkRecovery moves an Open breaker to HalfOpen once the recovery timeout has elapsed.
+// The caller must hold b.mu.
+func (b *CircuitBreaker) checkRecovery() {
+	if b.state == Open && b.clock().Sub(b.openedAt) >= b.recoveryTimeout() {
+		b.transition(HalfOpen)
+	}
+}
+
+// transition switches to the given state, resets the counters, and logs the change.
+// The caller must hold b.mu.
+func (b *CircuitBreaker) transition(to State) {
+	from := b.state
+	b.state = to
+	b.failures = 0
+	b.successes = 0
+	switch to {
+	case Open:
+		b.openedAt = b.clock()
+		logger.Warn("%s: circuit breaker %s→%s, pausing calls for %s", b.Name, from, to, b.recoveryTimeout())
+	case HalfOpen:
+		logger.Info("%s: circuit breaker %s→%s, allowing a trial call", b.Name, from, to)
+	case Closed:
+		logger.Info("%s: circuit breaker %s→%s, provider recovered", b.Name, from, to)
+	}
+}
+
+func (b *CircuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold > 0 {
+		return b.FailureThreshold
+	}
+	return DefaultFailureThreshold
+}
+
+func (b *CircuitBreaker) successThreshold() int {
+	if b.SuccessThreshold > 0 {
+		return b.SuccessThreshold
+	}
+	return DefaultSuccessThreshold
+}
+
+func (b *CircuitBreaker) recoveryTimeout() time.Duration {
+	if b.RecoveryTimeout > 0 {
+		return b.RecoveryTimeout
+	}
+	return DefaultRecoveryTimeout
+}
@@ -0,0 +1,187 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package breaker
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for driving recovery timeouts.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(failures, successes int, timeout time.Duration) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	b := &CircuitBreaker{
+		Name:             "test",
+		FailureThreshold: failures,
+		SuccessThreshold: successes,
+		RecoveryTimeout:  timeout,
+		now:              clock.now,
+	}
+	return b, clock
+}
+
+func TestCircuitBreaker_StartsClosed(t *testing.T) {
+	b := New("test")
+	if b.State() != Closed {
+		t.Fatalf("expected Closed, got %s", b.State())
+	}
+	if !b.Allow() {
+		t.Errorf("expected Closed breaker to allow calls")
+	}
+}
+
+func TestCircuitBreaker_OpensAfterFailureThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, 1, time.Minute)
+
+	b.RecordFailure()
+	b.RecordFailure()
+	if b.State() != Closed {
+		t.Fatalf("expected Closed below threshold, got %s", b.State())
+	}
+	b.RecordFailure()
+	if b.State() != Open {
+		t.Fatalf("expected Open at threshold, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Errorf("expected Open breaker to reject calls")
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(3, 1, time.Minute)
+
+	b.RecordFailure()
+	b.RecordFailure()
+	b.RecordSuccess()
+	b.RecordFailure()
+	b.RecordFailure()
+	if b.State() != Closed {
+		t.Errorf("expected non-consecutive failures to keep the breaker Closed, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAfterRecoveryTimeout(t *testing.T) {
+	b, clock := newTestBreaker(1, 1, time.Minute)
+	b.RecordFailure()
+
+	clock.advance(59 * time.Second)
+	if b.Allow() {
+		t.Fatalf("expected breaker to stay Open before the recovery timeout")
+	}
+	clock.advance(time.Second)
+	if !b.Allow() {
+		t.Fatalf("expected breaker to allow a trial after the recovery timeout")
+	}
+	if b.State() != HalfOpen {
+		t.Errorf("expected HalfOpen, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAllowsOneTrial(t *testing.T) {
+	b, clock := newTestBreaker(1, 1, time.Minute)
+	b.RecordFailure()
+	clock.advance(time.Minute)
+
+	if !b.Allow() {
+		t.Fatalf("expected the first HalfOpen call to be allowed")
+	}
+	if b.Allow() {
+		t.Fatalf("expected further calls to be rejected while the trial is in flight")
+	}
+	b.RecordSuccess()
+	if b.State() != Closed {
+		t.Fatalf("expected a successful trial to close the breaker, got %s", b.State())
+	}
+	if !b.Allow() || !b.Allow() {
+		t.Errorf("expected Closed breaker to allow every call")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenClosesAfterSuccessThreshold(t *testing.T) {
+	b, clock := newTestBreaker(1, 2, time.Minute)
+	b.RecordFailure()
+	clock.advance(time.Minute)
+	b.Allow()
+
+	b.RecordSuccess()
+	if b.State() != HalfOpen {
+		t.Fatalf("expected HalfOpen below success threshold, got %s", b.State())
+	}
+	if !b.Allow() {
+		t.Fatalf("expected a new trial once the previous one was recorded")
+	}
+	b.RecordSuccess()
+	if b.State() != Closed {
+		t.Errorf("expected Closed at success threshold, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_HalfOpenReopensOnFailure(t *testing.T) {
+	b, clock := newTestBreaker(3, 2, time.Minute)
+	for i := 0; i < 3; i++ {
+		b.RecordFailure()
+	}
+	clock.advance(time.Minute)
+	b.Allow()
+
+	b.RecordSuccess()
+	b.RecordFailure()
+	if b.State() != Open {
+		t.Fatalf("expected a HalfOpen failure to reopen the breaker, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Errorf("expected reopened breaker to reject calls until the timeout elapses again")
+	}
+	clock.advance(time.Minute)
+	if b.State() != HalfOpen {
+		t.Errorf("expected HalfOpen after a second recovery timeout, got %s", b.State())
+	}
+}
+
+func TestCircuitBreaker_ZeroValueUsesDefaults(t *testing.T) {
+	b, clock := newTestBreaker(0, 0, 0)
+	for i := 0; i < DefaultFailureThreshold-1; i++ {
+		b.RecordFailure()
+	}
+	if b.State() != Closed {
+		t.Fatalf("expected Closed below default threshold, got %s", b.State())
+	}
+	b.RecordFailure()
+	if b.State() != Open {
+		t.Fatalf("expected Open at default threshold, got %s", b.State())
+	}
+	clock.advance(DefaultRecoveryTimeout)
+	if b.State() != HalfOpen {
+		t.Errorf("expected HalfOpen after default recovery timeout, got %s", b.State())
+	}
+}
+
+func TestState_String(t *testing.T) {
+	tests := map[State]string{Closed: "Closed", Open: "Open", HalfOpen: "HalfOpen", State(9): "Unknown"}
+	for s, want := range tests {
+		if got := s.String(); got != want {
+			t.Errorf("State(%d).String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
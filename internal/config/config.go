@@ -16,7 +16,7 @@
 // You should have received a copy of the GNU General Public License
 // along with dynago.  If not, see <https://www.gnu.org/licenses/>.
 
-// Package config provides configuration loading for dynago from YAML files.
+// Package config provides configuration loading for dynago from YAML or TOML files.
 //
 // The configuration supports multiple DNS providers, logging options, and update intervals.
 package config
@@ -24,54 +24,390 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
+// Defaults applied by LoadConfig when the corresponding setting is omitted.
+const (
+	DefaultProviderTimeout         = 30 * time.Second // provider_timeout
+	DefaultCircuitBreakerThreshold = 5                // circuit_breaker_threshold
+	DefaultCircuitBreakerTimeout   = 5 * time.Minute  // circuit_breaker_timeout
+	DefaultPushgatewayJob          = "dynago"         // metrics.pushgateway.job
+	DefaultStatsDAddr              = "127.0.0.1:8125" // metrics.statsd.addr
+	DefaultStatsDPrefix            = "dynago"         // metrics.statsd.prefix
+	DefaultDataDogAddr             = "127.0.0.1:8125" // metrics.datadog.addr
+	DefaultInfluxDBFlushInterval   = 10 * time.Second // metrics.influxdb.flush_interval
+	DefaultProbesAddr              = ":8080"          // probes.addr, with probes.enabled
+	DefaultLogFormat               = "pretty"         // log_format
+	DefaultLogTarget               = "console"        // log_target
+	DefaultOtelServiceName         = "dynago"         // otel.service_name
+	DefaultOtelMetricsInterval     = time.Minute      // otel.metrics_interval
+)
+
+// MinInterval is the shortest update interval LoadConfig accepts, to avoid exhausting provider API quotas.
+const MinInterval = 60 * time.Second
+
+// providerEnvOverrides lists the provider settings that DYNAGO_<PROVIDER>_<SETTING> environment
+// variables override, e.g. DYNAGO_CLOUDFLARE_API_TOKEN for providers.cloudflare.api_token.
+var providerEnvOverrides = map[string][]string{
+	"cloudflare": {"api_token", "api_key", "email", "api_email"},
+	"route53":    {"access_key_id", "secret_access_key", "session_token", "assume_role_arn", "external_id"},
+}
+
+// LoadOption sets a command-line setting on the Config before LoadConfig or LoadFromEnv validates it.
+type LoadOption func(*Config)
+
+// WithAllowShortInterval returns a LoadOption that sets Config.AllowShortInterval to allow.
+func WithAllowShortInterval(allow bool) LoadOption {
+	return func(cfg *Config) { cfg.AllowShortInterval = allow }
+}
+
 // Config represents the root configuration for dynago loaded from YAML.
 //
 // Providers is a map of provider name to arbitrary config (for extensibility).
 type Config struct {
-	Interval  time.Duration  `yaml:"interval"`
-	IPSource  string         `yaml:"ip_source"`
-	LogLevel  string         `yaml:"log_level"`
-	Providers map[string]any `yaml:"providers"`
+	Path        string        `yaml:"-" toml:"-"` // File the config was loaded from (empty if not loaded from a file)
+	Interval    time.Duration `yaml:"interval" toml:"interval"`
+	IPSource    string        `yaml:"ip_source" toml:"ip_source"`
+	IPSources   []string      `yaml:"ip_sources" toml:"ip_sources"`     // Fallback IP sources tried in order after IPSource
+	IPSourceV6  string        `yaml:"ip_source_v6" toml:"ip_source_v6"` // Source of the public IPv6 address for AAAA records
+	LogLevel    string        `yaml:"log_level" toml:"log_level"`
+	LogFormat   string        `yaml:"log_format" toml:"log_format"` // Console log format, "pretty" (default) or "json"
+	LogTarget   string        `yaml:"log_target" toml:"log_target"` // "console" (default), "syslog" (console and syslog), or "syslog_only"
+	DryRun      bool          `yaml:"dry_run" toml:"dry_run"`       // Log planned updates without writing to DNS
+	Once        bool          `yaml:"-" toml:"-"`                   // Run a single update cycle and exit (set by --once)
+	ForceUpdate bool          `yaml:"-" toml:"-"`                   // Update records without comparing them first (set by --force)
+	// AllowShortInterval accepts an interval below MinInterval (set by --allow-short-interval for testing).
+	AllowShortInterval bool              `yaml:"-" toml:"-"`
+	RetryPolicy        RetryPolicyConfig `yaml:"retry_policy" toml:"retry_policy"` // Retries for failed provider updates
+	// LogMaxSizeMB, LogMaxBackups, and LogCompressBackups rotate the -log file; all zero disables rotation.
+	LogMaxSizeMB       int  `yaml:"log_max_size_mb" toml:"log_max_size_mb"`           // Size at which the file is rotated (100 if 0)
+	LogMaxBackups      int  `yaml:"log_max_backups" toml:"log_max_backups"`           // Rotated files to keep (all if 0)
+	LogCompressBackups bool `yaml:"log_compress_backups" toml:"log_compress_backups"` // Gzip rotated files
+	// ValidateCredentials runs each provider's SelfTest at startup and refuses to start on failure.
+	ValidateCredentials bool `yaml:"validate_credentials" toml:"validate_credentials"`
+	// RunOnStart runs each provider's HealthCheck at startup, refusing to start on failure, and runs
+	// the first update cycle immediately instead of after one interval.
+	RunOnStart bool `yaml:"run_on_start" toml:"run_on_start"`
+	// DebounceCount is how many consecutive cycles must report the same new IP before updating (0 or 1 disables).
+	DebounceCount int `yaml:"debounce_count" toml:"debounce_count"`
+	// OnlyProviders limits updates to these provider names (set by --provider; empty runs all).
+	OnlyProviders []string `yaml:"-" toml:"-"`
+	// ProviderTimeout bounds each provider's check-and-update within a cycle (default 30s).
+	ProviderTimeout time.Duration `yaml:"provider_timeout" toml:"provider_timeout"`
+	// CircuitBreakerThreshold is how many consecutive failures pause a provider (default 5).
+	CircuitBreakerThreshold int `yaml:"circuit_breaker_threshold" toml:"circuit_breaker_threshold"`
+	// CircuitBreakerTimeout is how long a paused provider is skipped before a trial call (default 5m).
+	CircuitBreakerTimeout time.Duration `yaml:"circuit_breaker_timeout" toml:"circuit_breaker_timeout"`
+	// PreUpdateHook and PostUpdateHook are shell command lines run around each DNS update.
+	// {provider}, {old_ip}, and {new_ip} are replaced before the command runs.
+	PreUpdateHook  string         `yaml:"pre_update_hook" toml:"pre_update_hook"`
+	PostUpdateHook string         `yaml:"post_update_hook" toml:"post_update_hook"`
+	Providers      map[string]any `yaml:"providers" toml:"providers"`
+	// Metrics configures the Prometheus metrics endpoint (disabled unless prometheus_addr is set).
+	Metrics MetricsConfig `yaml:"metrics" toml:"metrics"`
+	// MetricsAddr is an alias for metrics.prometheus_addr. When only metrics_addr is set, LoadConfig
+	// copies it to Metrics.PrometheusAddr.
+	MetricsAddr string `yaml:"metrics_addr" toml:"metrics_addr"`
+	// Probes configures the /healthz, /readyz, and /livez endpoints (disabled by default).
+	Probes ProbesConfig `yaml:"probes" toml:"probes"`
+	// HealthAddr is an alias for probes.addr. When only health_addr is set, LoadConfig copies it to
+	// Probes.Addr, which also enables the probes unless probes.enabled is false.
+	HealthAddr string `yaml:"health_addr" toml:"health_addr"`
+	// Otel configures OpenTelemetry tracing and metrics export (requires a build with the otel tag).
+	Otel OtelConfig `yaml:"otel" toml:"otel"`
+	// Debug configures debugging endpoints, all disabled by default.
+	Debug DebugConfig `yaml:"debug" toml:"debug"`
+	// StrictPermissions makes LoadConfig reject a world-readable config file instead of warning.
+	StrictPermissions bool `yaml:"strict_permissions" toml:"strict_permissions"`
+	// AuditLog is a CSV file every applied IP change is appended to, whatever the log level (empty disables).
+	AuditLog string `yaml:"audit_log" toml:"audit_log"`
+	// HistoryDB is a SQLite database every applied IP change is recorded in, for `dynago history` (empty disables).
+	HistoryDB string `yaml:"history_db" toml:"history_db"`
 }
 
-// LoadConfig loads the configuration from the given YAML file path.
+// MetricsConfig holds the metrics section of the config.
+type MetricsConfig struct {
+	PrometheusAddr string            `yaml:"prometheus_addr" toml:"prometheus_addr"` // Listen address for /metrics, e.g. ":9090" (empty disables)
+	TelegrafAddr   string            `yaml:"telegraf_addr" toml:"telegraf_addr"`     // Listen address for /metrics/telegraf; may equal PrometheusAddr (empty disables)
+	Pushgateway    PushgatewayConfig `yaml:"pushgateway" toml:"pushgateway"`         // Pushes metrics after each cycle, e.g. for --once runs from cron
+	StatsD         StatsDConfig      `yaml:"statsd" toml:"statsd"`                   // Sends metrics to a StatsD daemon, e.g. for Graphite or InfluxDB
+	DataDog        DataDogConfig     `yaml:"datadog" toml:"datadog"`                 // Sends tagged metrics and events to a DataDog agent over DogStatsD
+	InfluxDB       InfluxDBConfig    `yaml:"influxdb" toml:"influxdb"`               // Writes the history of DNS checks and updates to InfluxDB v2
+}
+
+// PushgatewayConfig holds the metrics.pushgateway section of the config.
+type PushgatewayConfig struct {
+	URL              string `yaml:"url" toml:"url"`                               // Pushgateway base URL, e.g. "http://pushgateway:9091" (empty disables)
+	Job              string `yaml:"job" toml:"job"`                               // Job label of the pushed metrics (default "dynago")
+	Instance         string `yaml:"instance" toml:"instance"`                     // Instance grouping label (default the host name)
+	DeleteOnShutdown bool   `yaml:"delete_on_shutdown" toml:"delete_on_shutdown"` // Delete the pushed metrics when the service stops (not with --once)
+}
+
+// StatsDConfig holds the metrics.statsd section of the config.
+type StatsDConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"` // Send metrics to the StatsD daemon at Addr
+	Addr    string `yaml:"addr" toml:"addr"`       // UDP address of the StatsD daemon (default "127.0.0.1:8125")
+	Prefix  string `yaml:"prefix" toml:"prefix"`   // Prefix of every stat name (default "dynago")
+}
+
+// DataDogConfig holds the metrics.datadog section of the config.
+type DataDogConfig struct {
+	Enabled    bool     `yaml:"enabled" toml:"enabled"`         // Send metrics and events to the DogStatsD agent at Addr
+	Addr       string   `yaml:"addr" toml:"addr"`               // Agent address, host:port or unix:///path/to/dsd.socket (default "127.0.0.1:8125")
+	GlobalTags []string `yaml:"global_tags" toml:"global_tags"` // Tags added to every metric and event, e.g. "env:home"
+}
+
+// InfluxDBConfig holds the metrics.influxdb section of the config.
+type InfluxDBConfig struct {
+	URL           string        `yaml:"url" toml:"url"`                       // InfluxDB v2 server URL, e.g. "http://influxdb:8086" (empty disables)
+	Token         string        `yaml:"token" toml:"token"`                   // API token with write access to Bucket
+	Org           string        `yaml:"org" toml:"org"`                       // Organization owning Bucket
+	Bucket        string        `yaml:"bucket" toml:"bucket"`                 // Bucket the points are written to
+	FlushInterval time.Duration `yaml:"flush_interval" toml:"flush_interval"` // How often buffered points are written (default 10s)
+}
+
+// ProbesConfig holds the probes section of the config.
+//
+// The probes are served when enabled is true or addr is set, unless enabled is explicitly false.
+// After loading, Enabled is true exactly when Addr is non-empty.
+type ProbesConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"` // Serve the probe endpoints, on DefaultProbesAddr unless Addr is set
+	Addr    string `yaml:"addr" toml:"addr"`       // Listen address for the probe endpoints, e.g. ":8080"
+}
+
+// OtelConfig holds the otel section of the config.
+type OtelConfig struct {
+	TraceEndpoint   string        `yaml:"trace_endpoint" toml:"trace_endpoint"`     // OTLP gRPC collector URL, e.g. "http://localhost:4317" (empty disables tracing)
+	MetricsEndpoint string        `yaml:"metrics_endpoint" toml:"metrics_endpoint"` // OTLP gRPC collector URL for metrics (empty disables OTLP metrics)
+	MetricsInterval time.Duration `yaml:"metrics_interval" toml:"metrics_interval"` // How often metrics are exported (default 60s)
+	ServiceName     string        `yaml:"service_name" toml:"service_name"`         // service.name of the exported spans and metrics (default "dynago")
+}
+
+// DebugConfig holds the debug section of the config.
+//
+// The endpoints reveal runtime state that can include credentials held in memory, so they must
+// never be exposed on a public interface.
+type DebugConfig struct {
+	ExpvarAddr string `yaml:"expvar_addr" toml:"expvar_addr"` // Listen address for /debug/vars, e.g. "127.0.0.1:6060" (empty disables)
+	PprofAddr  string `yaml:"pprof_addr" toml:"pprof_addr"`   // Listen address for /debug/pprof/, e.g. "127.0.0.1:6061" (empty disables)
+}
+
+// RetryPolicyConfig holds the retry_policy section of the config.
+//
+// Delays are parsed from duration strings such as "2s" or "1m".
+type RetryPolicyConfig struct {
+	MaxAttempts int           `yaml:"max_attempts" toml:"max_attempts"` // Total attempts including the first
+	BaseDelay   time.Duration `yaml:"base_delay" toml:"base_delay"`     // Delay before the first retry
+	MaxDelay    time.Duration `yaml:"max_delay" toml:"max_delay"`       // Upper bound for any single delay
+}
+
+// LoadConfig loads the configuration from the given YAML file path, or TOML if its extension is .toml.
 //
-// It parses the YAML file, converts the interval string to time.Duration,
-// and returns a Config struct or an error if parsing fails.
+// It parses the file, converts the interval string to time.Duration,
+// and returns a Config struct or an error if parsing fails or ValidateConfig reports problems.
+//
+// Non-empty DYNAGO_INTERVAL, DYNAGO_IP_SOURCE, DYNAGO_IP_SOURCE_V6, DYNAGO_LOG_LEVEL,
+// DYNAGO_LOG_FORMAT, DYNAGO_LOG_TARGET, and DYNAGO_INFLUXDB_TOKEN environment variables override
+// the file's settings, and the provider credentials listed in providerEnvOverrides can be
+// supplied the same way, so secrets need not be stored in the file.
+// Provider settings written as $VARNAME or ${VARNAME} are replaced by that environment variable
+// (see expandEnvRefs) before the DYNAGO_* overrides are applied. opts are applied before validation.
+//
+// A world-readable file is reported by checkPermissions: a warning is logged, or with
+// strict_permissions set, an error is returned.
 //
 // Provider configs are left as generic maps for each provider.
-func LoadConfig(path string) (*Config, error) {
+func LoadConfig(path string, opts ...LoadOption) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
 	}
-	var raw struct {
-		Interval  string         `yaml:"interval"`
-		IPSource  string         `yaml:"ip_source"`
-		LogLevel  string         `yaml:"log_level"`
-		Providers map[string]any `yaml:"providers"`
-	}
-	if err := yaml.Unmarshal(data, &raw); err != nil {
+	var raw rawConfig
+	if err := unmarshalConfig(path, data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
+	if err := expandEnvRefs(raw.Providers); err != nil {
+		return nil, fmt.Errorf("invalid config file %s:\n%w", path, err)
+	}
+	cfg, err := newConfig(&raw, path, "config file "+path, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPermissions(path, cfg.StrictPermissions); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// rawConfig is the layout of a config file, before durations are parsed and defaults applied.
+type rawConfig struct {
+	Interval                string            `yaml:"interval" toml:"interval"`
+	IPSource                string            `yaml:"ip_source" toml:"ip_source"`
+	IPSources               []string          `yaml:"ip_sources" toml:"ip_sources"`
+	IPSourceV6              string            `yaml:"ip_source_v6" toml:"ip_source_v6"`
+	LogLevel                string            `yaml:"log_level" toml:"log_level"`
+	LogFormat               string            `yaml:"log_format" toml:"log_format"`
+	LogTarget               string            `yaml:"log_target" toml:"log_target"`
+	LogMaxSizeMB            int               `yaml:"log_max_size_mb" toml:"log_max_size_mb"`
+	LogMaxBackups           int               `yaml:"log_max_backups" toml:"log_max_backups"`
+	LogCompressBackups      bool              `yaml:"log_compress_backups" toml:"log_compress_backups"`
+	DryRun                  bool              `yaml:"dry_run" toml:"dry_run"`
+	RetryPolicy             RetryPolicyConfig `yaml:"retry_policy" toml:"retry_policy"`
+	ValidateCredentials     bool              `yaml:"validate_credentials" toml:"validate_credentials"`
+	RunOnStart              bool              `yaml:"run_on_start" toml:"run_on_start"`
+	DebounceCount           int               `yaml:"debounce_count" toml:"debounce_count"`
+	ProviderTimeout         time.Duration     `yaml:"provider_timeout" toml:"provider_timeout"`
+	CircuitBreakerThreshold int               `yaml:"circuit_breaker_threshold" toml:"circuit_breaker_threshold"`
+	CircuitBreakerTimeout   time.Duration     `yaml:"circuit_breaker_timeout" toml:"circuit_breaker_timeout"`
+	PreUpdateHook           string            `yaml:"pre_update_hook" toml:"pre_update_hook"`
+	PostUpdateHook          string            `yaml:"post_update_hook" toml:"post_update_hook"`
+	AuditLog                string            `yaml:"audit_log" toml:"audit_log"`
+	HistoryDB               string            `yaml:"history_db" toml:"history_db"`
+	Metrics                 MetricsConfig     `yaml:"metrics" toml:"metrics"`
+	MetricsAddr             string            `yaml:"metrics_addr" toml:"metrics_addr"`
+	HealthAddr              string            `yaml:"health_addr" toml:"health_addr"`
+	Otel                    OtelConfig        `yaml:"otel" toml:"otel"`
+	Debug                   DebugConfig       `yaml:"debug" toml:"debug"`
+	StrictPermissions       bool              `yaml:"strict_permissions" toml:"strict_permissions"`
+	Providers               map[string]any    `yaml:"providers" toml:"providers"`
+	Probes                  struct {
+		Enabled *bool  `yaml:"enabled" toml:"enabled"` // nil when unset, so that setting addr alone enables the probes
+		Addr    string `yaml:"addr" toml:"addr"`
+	} `yaml:"probes" toml:"probes"`
+}
+
+// newConfig applies the DYNAGO_* environment overrides and defaults to raw and returns the
+// resulting Config, or an error if ValidateConfig reports problems.
+//
+// path is recorded as Config.Path, and source names where raw came from in error messages. opts
+// are applied before validation.
+func newConfig(raw *rawConfig, path, source string, opts []LoadOption) (*Config, error) {
+	for name, field := range map[string]*string{
+		"DYNAGO_INTERVAL":       &raw.Interval,
+		"DYNAGO_IP_SOURCE":      &raw.IPSource,
+		"DYNAGO_IP_SOURCE_V6":   &raw.IPSourceV6,
+		"DYNAGO_LOG_LEVEL":      &raw.LogLevel,
+		"DYNAGO_LOG_FORMAT":     &raw.LogFormat,
+		"DYNAGO_LOG_TARGET":     &raw.LogTarget,
+		"DYNAGO_AUDIT_LOG":      &raw.AuditLog,
+		"DYNAGO_HISTORY_DB":     &raw.HistoryDB,
+		"DYNAGO_INFLUXDB_TOKEN": &raw.Metrics.InfluxDB.Token,
+	} {
+		if value := os.Getenv(name); value != "" {
+			*field = value
+		}
+	}
+	applyProviderEnv(raw.Providers)
 	interval, err := time.ParseDuration(raw.Interval)
 	if err != nil {
-		return nil, fmt.Errorf("invalid interval %q in config file %s: %w", raw.Interval, path, err)
+		return nil, fmt.Errorf("invalid interval %q in %s: %w", raw.Interval, source, err)
+	}
+	if raw.ProviderTimeout <= 0 {
+		raw.ProviderTimeout = DefaultProviderTimeout
+	}
+	if raw.CircuitBreakerThreshold <= 0 {
+		raw.CircuitBreakerThreshold = DefaultCircuitBreakerThreshold
+	}
+	if raw.CircuitBreakerTimeout <= 0 {
+		raw.CircuitBreakerTimeout = DefaultCircuitBreakerTimeout
+	}
+	if raw.Otel.MetricsInterval <= 0 {
+		raw.Otel.MetricsInterval = DefaultOtelMetricsInterval
+	}
+	if raw.Metrics.InfluxDB.FlushInterval <= 0 {
+		raw.Metrics.InfluxDB.FlushInterval = DefaultInfluxDBFlushInterval
+	}
+	if raw.LogFormat == "" {
+		raw.LogFormat = DefaultLogFormat
+	}
+	if raw.LogTarget == "" {
+		raw.LogTarget = DefaultLogTarget
+	}
+	if raw.Metrics.PrometheusAddr == "" {
+		raw.Metrics.PrometheusAddr = raw.MetricsAddr
+	}
+	probesAddr := raw.Probes.Addr
+	if probesAddr == "" {
+		probesAddr = raw.HealthAddr
+	}
+	if enabled := raw.Probes.Enabled; enabled != nil && !*enabled {
+		probesAddr = ""
+	} else if enabled != nil && probesAddr == "" {
+		probesAddr = DefaultProbesAddr
 	}
 	cfg := &Config{
-		Interval:  interval,
-		IPSource:  raw.IPSource,
-		LogLevel:  raw.LogLevel,
-		Providers: raw.Providers,
+		Path:                    path,
+		Interval:                interval,
+		IPSource:                raw.IPSource,
+		IPSources:               raw.IPSources,
+		IPSourceV6:              raw.IPSourceV6,
+		LogLevel:                raw.LogLevel,
+		LogFormat:               raw.LogFormat,
+		LogTarget:               raw.LogTarget,
+		LogMaxSizeMB:            raw.LogMaxSizeMB,
+		LogMaxBackups:           raw.LogMaxBackups,
+		LogCompressBackups:      raw.LogCompressBackups,
+		DryRun:                  raw.DryRun,
+		RetryPolicy:             raw.RetryPolicy,
+		ValidateCredentials:     raw.ValidateCredentials,
+		RunOnStart:              raw.RunOnStart,
+		DebounceCount:           raw.DebounceCount,
+		ProviderTimeout:         raw.ProviderTimeout,
+		CircuitBreakerThreshold: raw.CircuitBreakerThreshold,
+		CircuitBreakerTimeout:   raw.CircuitBreakerTimeout,
+		PreUpdateHook:           raw.PreUpdateHook,
+		PostUpdateHook:          raw.PostUpdateHook,
+		AuditLog:                raw.AuditLog,
+		HistoryDB:               raw.HistoryDB,
+		Metrics:                 raw.Metrics,
+		MetricsAddr:             raw.MetricsAddr,
+		HealthAddr:              raw.HealthAddr,
+		Probes:                  ProbesConfig{Enabled: probesAddr != "", Addr: probesAddr},
+		Otel:                    raw.Otel,
+		Debug:                   raw.Debug,
+		StrictPermissions:       raw.StrictPermissions,
+		Providers:               raw.Providers,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid %s:\n%w", source, err)
 	}
 	return cfg, nil
 }
 
+// unmarshalConfig decodes data into out as TOML if path ends in .toml, and as YAML otherwise.
+func unmarshalConfig(path string, data []byte, out any) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return toml.Unmarshal(data, out)
+	}
+	return yaml.Unmarshal(data, out)
+}
+
+// applyProviderEnv sets the provider settings listed in providerEnvOverrides from their
+// DYNAGO_<PROVIDER>_<SETTING> environment variables, for providers present in the config.
+func applyProviderEnv(providers map[string]any) {
+	for provider, settings := range providerEnvOverrides {
+		section, ok := providers[provider].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, setting := range settings {
+			name := "DYNAGO_" + strings.ToUpper(provider+"_"+setting)
+			if value := os.Getenv(name); value != "" {
+				section[setting] = value
+			}
+		}
+	}
+}
+
 // ConfigFromMap parses a provider config from a generic map into a strongly-typed struct.
 //
 // This function is useful for converting provider-specific configuration
@@ -89,3 +425,17 @@ func ConfigFromMap(m any, out any) error {
 	}
 	return yaml.Unmarshal(data, out)
 }
+
+// AllIPSources returns IPSource followed by IPSources, skipping empty entries.
+func (c *Config) AllIPSources() []string {
+	var sources []string
+	if c.IPSource != "" {
+		sources = append(sources, c.IPSource)
+	}
+	for _, src := range c.IPSources {
+		if src != "" {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
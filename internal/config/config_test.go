@@ -24,13 +24,21 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 const sampleYAML = `
 interval: 5m
 ip_source: "https://api.ipify.org"
 log_level: "info"
+circuit_breaker_threshold: 3
+retry_policy:
+  max_attempts: 3
+  base_delay: 2s
+  max_delay: 30s
 providers:
   cloudflare:
     enabled: true
@@ -74,6 +82,33 @@ func TestLoadConfig(t *testing.T) {
 	if cfg.LogLevel != "info" {
 		t.Errorf("unexpected log_level: %s", cfg.LogLevel)
 	}
+	if cfg.RetryPolicy.MaxAttempts != 3 || cfg.RetryPolicy.BaseDelay != 2*time.Second || cfg.RetryPolicy.MaxDelay != 30*time.Second {
+		t.Errorf("unexpected retry_policy: %+v", cfg.RetryPolicy)
+	}
+	if cfg.ProviderTimeout != DefaultProviderTimeout {
+		t.Errorf("expected default provider_timeout %s, got %s", DefaultProviderTimeout, cfg.ProviderTimeout)
+	}
+	if cfg.CircuitBreakerThreshold != 3 {
+		t.Errorf("expected circuit_breaker_threshold 3, got %d", cfg.CircuitBreakerThreshold)
+	}
+	if cfg.CircuitBreakerTimeout != DefaultCircuitBreakerTimeout {
+		t.Errorf("expected default circuit_breaker_timeout %s, got %s", DefaultCircuitBreakerTimeout, cfg.CircuitBreakerTimeout)
+	}
+	if cfg.Probes.Enabled || cfg.Probes.Addr != "" {
+		t.Errorf("expected the probes to be disabled by default, got %+v", cfg.Probes)
+	}
+	if cfg.Metrics.InfluxDB.FlushInterval != DefaultInfluxDBFlushInterval {
+		t.Errorf("expected default metrics.influxdb.flush_interval %s, got %s", DefaultInfluxDBFlushInterval, cfg.Metrics.InfluxDB.FlushInterval)
+	}
+	if cfg.LogFormat != DefaultLogFormat {
+		t.Errorf("expected default log_format %q, got %q", DefaultLogFormat, cfg.LogFormat)
+	}
+	if cfg.LogTarget != DefaultLogTarget {
+		t.Errorf("expected default log_target %q, got %q", DefaultLogTarget, cfg.LogTarget)
+	}
+	if cfg.Otel.MetricsInterval != DefaultOtelMetricsInterval {
+		t.Errorf("expected default otel.metrics_interval %s, got %s", DefaultOtelMetricsInterval, cfg.Otel.MetricsInterval)
+	}
 
 	// Cloudflare provider assertions
 	cfRaw, ok := cfg.Providers["cloudflare"]
@@ -109,4 +144,180 @@ func TestLoadConfig(t *testing.T) {
 	if region, _ := r53Cfg["region"].(string); region != "us-east-1" {
 		t.Errorf("unexpected route53.region: %s", region)
 	}
+
+	// Intervals below MinInterval are rejected unless explicitly allowed.
+	shortPath := filepath.Join(t.TempDir(), "short.yml")
+	if err := os.WriteFile(shortPath, []byte(strings.Replace(sampleYAML, "interval: 5m", "interval: 1s", 1)), 0600); err != nil {
+		t.Fatalf("failed to write short-interval config: %v", err)
+	}
+	_, err = LoadConfig(shortPath)
+	want := "interval must be at least 60s to avoid provider rate limiting, got 1s"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+	if cfg, err := LoadConfig(shortPath, WithAllowShortInterval(true)); err != nil || cfg.Interval != time.Second || !cfg.AllowShortInterval {
+		t.Errorf("expected short interval to load with AllowShortInterval, got %v (err %v)", cfg, err)
+	}
+}
+
+// TestLoadConfig_TOML checks that a .toml file is parsed as TOML, using the equivalent of sampleYAML.
+func TestLoadConfig_TOML(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join("testdata", "dynago.toml"))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Interval != 5*time.Minute {
+		t.Errorf("expected interval 5m, got %v", cfg.Interval)
+	}
+	if cfg.IPSource != "https://api.ipify.org" || cfg.LogLevel != "info" {
+		t.Errorf("unexpected ip_source %q or log_level %q", cfg.IPSource, cfg.LogLevel)
+	}
+	if cfg.RetryPolicy.MaxAttempts != 3 || cfg.RetryPolicy.BaseDelay != 2*time.Second || cfg.RetryPolicy.MaxDelay != 30*time.Second {
+		t.Errorf("unexpected retry_policy: %+v", cfg.RetryPolicy)
+	}
+	if cfg.CircuitBreakerThreshold != 3 || cfg.ProviderTimeout != DefaultProviderTimeout || cfg.Probes.Addr != "" {
+		t.Errorf("unexpected circuit_breaker_threshold %d, provider_timeout %s, or probes.addr %q", cfg.CircuitBreakerThreshold, cfg.ProviderTimeout, cfg.Probes.Addr)
+	}
+
+	// Provider tables must decode the same way the providers decode YAML sections.
+	var cf struct {
+		Enabled  bool   `yaml:"enabled"`
+		APIToken string `yaml:"api_token"`
+		Proxied  bool   `yaml:"proxied"`
+		Records  []struct {
+			RecordName string `yaml:"record_name"`
+			TTL        int    `yaml:"ttl"`
+		} `yaml:"records"`
+	}
+	if err := ConfigFromMap(cfg.Providers["cloudflare"], &cf); err != nil {
+		t.Fatalf("ConfigFromMap(cloudflare): %v", err)
+	}
+	if !cf.Enabled || cf.APIToken != "cf-token" || !cf.Proxied {
+		t.Errorf("unexpected cloudflare config: %+v", cf)
+	}
+	if len(cf.Records) != 1 || cf.Records[0].RecordName != "vpn.example.com" || cf.Records[0].TTL != 120 {
+		t.Errorf("expected the [[providers.cloudflare.records]] table, got %+v", cf.Records)
+	}
+	r53Cfg, ok := cfg.Providers["route53"].(map[string]any)
+	if !ok {
+		t.Fatalf("route53 config is not a map[string]any")
+	}
+	if enabled, _ := r53Cfg["enabled"].(bool); enabled {
+		t.Errorf("route53.enabled should be false")
+	}
+	if region, _ := r53Cfg["region"].(string); region != "us-east-1" {
+		t.Errorf("unexpected route53.region: %s", region)
+	}
+}
+
+// TestLoadConfig_EnvOverrides checks that DYNAGO_* environment variables override the YAML values.
+func TestLoadConfig_EnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte(sampleYAML), 0600); err != nil {
+		t.Fatalf("failed to write sample YAML: %v", err)
+	}
+	t.Setenv("DYNAGO_INTERVAL", "10m")
+	t.Setenv("DYNAGO_IP_SOURCE", "https://icanhazip.com")
+	t.Setenv("DYNAGO_LOG_LEVEL", "")
+	t.Setenv("DYNAGO_CLOUDFLARE_API_TOKEN", "env-token")
+	t.Setenv("DYNAGO_ROUTE53_SECRET_ACCESS_KEY", "env-secret")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.Interval != 10*time.Minute {
+		t.Errorf("expected DYNAGO_INTERVAL to set interval 10m, got %s", cfg.Interval)
+	}
+	if cfg.IPSource != "https://icanhazip.com" {
+		t.Errorf("expected DYNAGO_IP_SOURCE to override ip_source, got %s", cfg.IPSource)
+	}
+	if cfg.LogLevel != "info" {
+		t.Errorf("expected an empty DYNAGO_LOG_LEVEL to keep log_level info, got %s", cfg.LogLevel)
+	}
+	cfCfg := cfg.Providers["cloudflare"].(map[string]any)
+	if cfCfg["api_token"] != "env-token" {
+		t.Errorf("expected DYNAGO_CLOUDFLARE_API_TOKEN to override api_token, got %v", cfCfg["api_token"])
+	}
+	r53Cfg := cfg.Providers["route53"].(map[string]any)
+	if r53Cfg["secret_access_key"] != "env-secret" || r53Cfg["access_key_id"] != "aws-key" {
+		t.Errorf("expected only secret_access_key to be overridden, got %v", r53Cfg)
+	}
+
+	t.Setenv("DYNAGO_INTERVAL", "soon")
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("expected an invalid DYNAGO_INTERVAL to be rejected")
+	}
+}
+
+// TestLoadConfig_Probes checks that the probes are served only when probes.enabled or probes.addr
+// (or its health_addr alias) is set, on DefaultProbesAddr unless addr is given, and that
+// enabled: false wins over addr.
+func TestLoadConfig_Probes(t *testing.T) {
+	tests := []struct {
+		name   string
+		probes string
+		want   ProbesConfig
+	}{
+		{"unset", "", ProbesConfig{}},
+		{"enabled", "probes:\n  enabled: true\n", ProbesConfig{Enabled: true, Addr: DefaultProbesAddr}},
+		{"addr", "probes:\n  addr: \"127.0.0.1:9000\"\n", ProbesConfig{Enabled: true, Addr: "127.0.0.1:9000"}},
+		{"disabled with addr", "probes:\n  enabled: false\n  addr: \":9000\"\n", ProbesConfig{}},
+		{"health_addr", "health_addr: \":9000\"\n", ProbesConfig{Enabled: true, Addr: ":9000"}},
+		{"disabled with health_addr", "health_addr: \":9000\"\nprobes:\n  enabled: false\n", ProbesConfig{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dynago.yml")
+			if err := os.WriteFile(path, []byte(sampleYAML+tt.probes), 0600); err != nil {
+				t.Fatalf("failed to write sample YAML: %v", err)
+			}
+			cfg, err := LoadConfig(path)
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if cfg.Probes != tt.want {
+				t.Errorf("probes = %+v, want %+v", cfg.Probes, tt.want)
+			}
+		})
+	}
+	if DefaultProbesAddr != ":8080" {
+		t.Errorf("expected the probes to default to :8080 when enabled, got %q", DefaultProbesAddr)
+	}
+}
+
+// TestLoadConfig_MetricsAddr checks that the top-level metrics_addr alias sets
+// metrics.prometheus_addr.
+func TestLoadConfig_MetricsAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    string
+		wantErr bool
+	}{
+		{"alias", "metrics_addr: \":9090\"\n", ":9090", false},
+		{"both agree", "metrics_addr: \":9090\"\nmetrics:\n  prometheus_addr: \":9090\"\n", ":9090", false},
+		{"both disagree", "metrics_addr: \":9090\"\nmetrics:\n  prometheus_addr: \":9091\"\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dynago.yml")
+			if err := os.WriteFile(path, []byte(sampleYAML+tt.yaml), 0600); err != nil {
+				t.Fatalf("failed to write sample YAML: %v", err)
+			}
+			cfg, err := LoadConfig(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected LoadConfig to reject metrics_addr disagreeing with metrics.prometheus_addr")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig failed: %v", err)
+			}
+			if cfg.Metrics.PrometheusAddr != tt.want {
+				t.Errorf("metrics.prometheus_addr = %q, want %q", cfg.Metrics.PrometheusAddr, tt.want)
+			}
+		})
+	}
 }
@@ -0,0 +1,148 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// providerEnvSettings lists, for each built-in provider, the settings LoadFromEnv reads from
+// DYNAGO_<PROVIDER>_<SETTING> in addition to the credentials in providerEnvOverrides.
+var providerEnvSettings = map[string][]string{
+	"cloudflare": {"enabled", "zone_id", "zone_name", "record_name", "record_names", "record_type", "proxied", "dual_stack"},
+	"route53":    {"enabled", "hosted_zone_id", "zone_name", "zone_private", "record_name", "record_names", "record_type", "region", "ttl", "use_instance_profile", "wait_for_propagation"},
+}
+
+// Provider settings read from the environment that are not strings. Lists are comma-separated.
+var (
+	envBoolSettings = []string{"enabled", "proxied", "dual_stack", "zone_private", "use_instance_profile", "wait_for_propagation"}
+	envIntSettings  = []string{"ttl"}
+	envListSettings = []string{"record_names"}
+)
+
+// LoadFromEnv builds a Config from DYNAGO_* environment variables alone, for running without a
+// config file, e.g. in a container.
+//
+// DYNAGO_INTERVAL and DYNAGO_IP_SOURCE are required. DYNAGO_IP_SOURCES (comma-separated),
+// DYNAGO_IP_SOURCE_V6, DYNAGO_LOG_LEVEL, DYNAGO_LOG_FORMAT, DYNAGO_LOG_TARGET, DYNAGO_AUDIT_LOG,
+// DYNAGO_HISTORY_DB, DYNAGO_DRY_RUN, DYNAGO_PROBES_ENABLED, DYNAGO_PROBES_ADDR,
+// DYNAGO_PROMETHEUS_ADDR, and DYNAGO_TELEGRAF_ADDR are optional. A provider is configured when any of its
+// DYNAGO_<PROVIDER>_<SETTING> variables is set, and enabled unless DYNAGO_<PROVIDER>_ENABLED is
+// false (see providerEnvSettings and providerEnvOverrides).
+//
+// Defaults, opts, and ValidateConfig apply as in LoadConfig; every problem found is reported.
+func LoadFromEnv(opts ...LoadOption) (*Config, error) {
+	var raw rawConfig
+	var errs []error
+	if os.Getenv("DYNAGO_INTERVAL") == "" {
+		errs = append(errs, errors.New("DYNAGO_INTERVAL is required"))
+	}
+	raw.IPSources = splitEnvList(os.Getenv("DYNAGO_IP_SOURCES"))
+	if value := os.Getenv("DYNAGO_DRY_RUN"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("DYNAGO_DRY_RUN: %q is not a boolean", value))
+		}
+		raw.DryRun = dryRun
+	}
+	raw.Probes.Addr = os.Getenv("DYNAGO_PROBES_ADDR")
+	if value := os.Getenv("DYNAGO_PROBES_ENABLED"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("DYNAGO_PROBES_ENABLED: %q is not a boolean", value))
+		}
+		raw.Probes.Enabled = &enabled
+	}
+	raw.Metrics.PrometheusAddr = os.Getenv("DYNAGO_PROMETHEUS_ADDR")
+	raw.Metrics.TelegrafAddr = os.Getenv("DYNAGO_TELEGRAF_ADDR")
+
+	raw.Providers = make(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(providerEnvSettings)) {
+		settings := append(slices.Clone(providerEnvOverrides[name]), providerEnvSettings[name]...)
+		section, err := providerFromEnv(name, settings)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(section) > 0 {
+			raw.Providers[name] = section
+		}
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid environment config:\n%w", errors.Join(errs...))
+	}
+	return newConfig(&raw, "", "environment config", opts)
+}
+
+// providerFromEnv returns the section of the named provider built from its
+// DYNAGO_<PROVIDER>_<SETTING> variables, or nil if none of settings is set.
+func providerFromEnv(name string, settings []string) (map[string]any, error) {
+	section := make(map[string]any)
+	var errs []error
+	for _, key := range settings {
+		envName := "DYNAGO_" + strings.ToUpper(name+"_"+key)
+		value := os.Getenv(envName)
+		if value == "" {
+			continue
+		}
+		switch {
+		case slices.Contains(envBoolSettings, key):
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", envName, value))
+				continue
+			}
+			section[key] = b
+		case slices.Contains(envIntSettings, key):
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an integer", envName, value))
+				continue
+			}
+			section[key] = n
+		case slices.Contains(envListSettings, key):
+			section[key] = splitEnvList(value)
+		default:
+			section[key] = value
+		}
+	}
+	if len(section) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	if _, ok := section["enabled"]; !ok {
+		section["enabled"] = true
+	}
+	return section, errors.Join(errs...)
+}
+
+// splitEnvList splits a comma-separated environment value, trimming spaces and dropping empty items.
+func splitEnvList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
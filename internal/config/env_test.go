@@ -0,0 +1,188 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearDynagoEnv unsets every DYNAGO_* variable for the duration of the test.
+func clearDynagoEnv(t *testing.T) {
+	t.Helper()
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "DYNAGO_") {
+			t.Setenv(name, "") // Restores the value after the test
+			os.Unsetenv(name)
+		}
+	}
+}
+
+// setEnv sets each name=value pair for the duration of the test.
+func setEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for name, value := range vars {
+		t.Setenv(name, value)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	clearDynagoEnv(t)
+	setEnv(t, map[string]string{
+		"DYNAGO_INTERVAL":                "10m",
+		"DYNAGO_IP_SOURCE":               "https://api.ipify.org",
+		"DYNAGO_IP_SOURCES":              "https://icanhazip.com, https://ifconfig.me/ip",
+		"DYNAGO_IP_SOURCE_V6":            "https://api6.ipify.org",
+		"DYNAGO_LOG_LEVEL":               "debug",
+		"DYNAGO_DRY_RUN":                 "true",
+		"DYNAGO_PROMETHEUS_ADDR":         ":9090",
+		"DYNAGO_CLOUDFLARE_API_TOKEN":    "cf-token",
+		"DYNAGO_CLOUDFLARE_ZONE_ID":      "cf-zone",
+		"DYNAGO_CLOUDFLARE_RECORD_NAMES": "home.example.com,vpn.example.com",
+		"DYNAGO_CLOUDFLARE_RECORD_TYPE":  "A",
+		"DYNAGO_CLOUDFLARE_PROXIED":      "true",
+		"DYNAGO_CLOUDFLARE_DUAL_STACK":   "false",
+	})
+
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.Path != "" {
+		t.Errorf("expected no config path, got %q", cfg.Path)
+	}
+	if cfg.Interval != 10*time.Minute || cfg.IPSource != "https://api.ipify.org" || cfg.IPSourceV6 != "https://api6.ipify.org" || cfg.LogLevel != "debug" {
+		t.Errorf("unexpected top-level settings: %+v", cfg)
+	}
+	if want := []string{"https://icanhazip.com", "https://ifconfig.me/ip"}; !reflect.DeepEqual(cfg.IPSources, want) {
+		t.Errorf("ip_sources = %v, want %v", cfg.IPSources, want)
+	}
+	if !cfg.DryRun || cfg.Metrics.PrometheusAddr != ":9090" {
+		t.Errorf("expected dry_run and metrics.prometheus_addr to be set, got %v and %q", cfg.DryRun, cfg.Metrics.PrometheusAddr)
+	}
+	if cfg.ProviderTimeout != DefaultProviderTimeout || cfg.Probes.Addr != "" {
+		t.Errorf("expected defaults, got provider_timeout %s and probes.addr %q", cfg.ProviderTimeout, cfg.Probes.Addr)
+	}
+	want := map[string]any{
+		"enabled":      true,
+		"api_token":    "cf-token",
+		"zone_id":      "cf-zone",
+		"record_names": []string{"home.example.com", "vpn.example.com"},
+		"record_type":  "A",
+		"proxied":      true,
+		"dual_stack":   false,
+	}
+	if got := cfg.Providers["cloudflare"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("cloudflare section = %v, want %v", got, want)
+	}
+	if _, ok := cfg.Providers["route53"]; ok {
+		t.Error("expected no route53 section without DYNAGO_ROUTE53_* variables")
+	}
+}
+
+func TestLoadFromEnv_Route53(t *testing.T) {
+	clearDynagoEnv(t)
+	setEnv(t, map[string]string{
+		"DYNAGO_INTERVAL":                     "5m",
+		"DYNAGO_IP_SOURCE":                    "https://api.ipify.org",
+		"DYNAGO_PROBES_ENABLED":               "true",
+		"DYNAGO_ROUTE53_ACCESS_KEY_ID":        "aws-key",
+		"DYNAGO_ROUTE53_SECRET_ACCESS_KEY":    "aws-secret",
+		"DYNAGO_ROUTE53_HOSTED_ZONE_ID":       "aws-zone",
+		"DYNAGO_ROUTE53_RECORD_NAME":          "home.example.com",
+		"DYNAGO_ROUTE53_REGION":               "us-east-1",
+		"DYNAGO_ROUTE53_TTL":                  "60",
+		"DYNAGO_ROUTE53_WAIT_FOR_PROPAGATION": "1",
+		"DYNAGO_CLOUDFLARE_ENABLED":           "false",
+	})
+
+	cfg, err := LoadFromEnv()
+	if err != nil {
+		t.Fatalf("LoadFromEnv: %v", err)
+	}
+	if cfg.Probes.Addr != DefaultProbesAddr {
+		t.Errorf("expected DYNAGO_PROBES_ENABLED to serve the probes on %q, got %q", DefaultProbesAddr, cfg.Probes.Addr)
+	}
+	r53 := cfg.Providers["route53"].(map[string]any)
+	if r53["enabled"] != true || r53["ttl"] != 60 || r53["wait_for_propagation"] != true || r53["region"] != "us-east-1" {
+		t.Errorf("unexpected route53 section: %v", r53)
+	}
+	if cf := cfg.Providers["cloudflare"].(map[string]any); cf["enabled"] != false {
+		t.Errorf("expected DYNAGO_CLOUDFLARE_ENABLED=false to disable cloudflare, got %v", cf)
+	}
+}
+
+func TestLoadFromEnv_Errors(t *testing.T) {
+	withProviderValidator(t, "cloudflare", requireAPIToken)
+	tests := []struct {
+		name string
+		vars map[string]string
+		want []string // Expected substrings of the error
+	}{
+		{
+			name: "nothing set",
+			vars: nil,
+			want: []string{"DYNAGO_INTERVAL is required"},
+		},
+		{
+			name: "invalid values",
+			vars: map[string]string{
+				"DYNAGO_INTERVAL":             "5m",
+				"DYNAGO_DRY_RUN":              "maybe",
+				"DYNAGO_CLOUDFLARE_API_TOKEN": "token",
+				"DYNAGO_CLOUDFLARE_PROXIED":   "yes please",
+				"DYNAGO_ROUTE53_TTL":          "5m",
+			},
+			want: []string{`DYNAGO_DRY_RUN: "maybe" is not a boolean`, `DYNAGO_CLOUDFLARE_PROXIED: "yes please" is not a boolean`, `DYNAGO_ROUTE53_TTL: "5m" is not an integer`},
+		},
+		{
+			name: "invalid interval",
+			vars: map[string]string{"DYNAGO_INTERVAL": "often", "DYNAGO_IP_SOURCE": "https://api.ipify.org", "DYNAGO_CLOUDFLARE_API_TOKEN": "token"},
+			want: []string{`invalid interval "often" in environment config`},
+		},
+		{
+			name: "validation",
+			vars: map[string]string{"DYNAGO_INTERVAL": "5m", "DYNAGO_CLOUDFLARE_ZONE_ID": "zone"},
+			want: []string{"invalid environment config", "ip_source is required", "cloudflare: api_token is required"},
+		},
+		{
+			name: "no provider",
+			vars: map[string]string{"DYNAGO_INTERVAL": "5m", "DYNAGO_IP_SOURCE": "https://api.ipify.org"},
+			want: []string{"no provider is enabled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearDynagoEnv(t)
+			setEnv(t, tt.vars)
+			_, err := LoadFromEnv()
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error to contain %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
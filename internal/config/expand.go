@@ -0,0 +1,92 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// envRefPattern matches a setting that consists solely of $VARNAME or ${VARNAME}.
+var envRefPattern = regexp.MustCompile(`^\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))$`)
+
+// expandEnvRefs replaces every string in the provider settings that is exactly $VARNAME or
+// ${VARNAME} with the value of that environment variable, descending into nested sections and
+// lists. Strings merely containing a $ are left alone.
+//
+// Returns an error naming each referencing setting, e.g. providers.cloudflare.api_token, whose
+// variable is not set.
+func expandEnvRefs(providers map[string]any) error {
+	return expandEnvValue("providers", providers)
+}
+
+// expandEnvValue expands the environment references in value, found at the dotted field path.
+func expandEnvValue(path string, value any) error {
+	var errs []error
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if s, ok := v[key].(string); ok {
+				expanded, err := expandEnvRef(path+"."+key, s)
+				errs = append(errs, err)
+				v[key] = expanded
+				continue
+			}
+			errs = append(errs, expandEnvValue(path+"."+key, v[key]))
+		}
+	case []any:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				expanded, err := expandEnvRef(path+"["+strconv.Itoa(i)+"]", s)
+				errs = append(errs, err)
+				v[i] = expanded
+				continue
+			}
+			errs = append(errs, expandEnvValue(path+"["+strconv.Itoa(i)+"]", item))
+		}
+	case []map[string]any: // TOML arrays of tables
+		for i, item := range v {
+			errs = append(errs, expandEnvValue(path+"["+strconv.Itoa(i)+"]", item))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// expandEnvRef returns the environment variable s refers to, or s itself if it is not a reference.
+func expandEnvRef(path, s string) (string, error) {
+	m := envRefPattern.FindStringSubmatch(s)
+	if m == nil {
+		return s, nil
+	}
+	name := m[1] + m[2]
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return s, fmt.Errorf("%s refers to environment variable %s, which is not set", path, name)
+	}
+	return value, nil
+}
@@ -0,0 +1,107 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnvRefs(t *testing.T) {
+	t.Setenv("CF_TOKEN", "env-token")
+	t.Setenv("CF_ZONE", "env-zone")
+	t.Setenv("HOME_NAME", "home.example.com")
+	t.Setenv("EMPTY", "")
+	providers := map[string]any{
+		"cloudflare": map[string]any{
+			"api_token":    "$CF_TOKEN",
+			"zone_id":      "${CF_ZONE}",
+			"record_names": []any{"$HOME_NAME", "vpn.example.com"},
+			"proxied":      true,
+			"comment":      "costs $5 a month",
+			"partial":      "prefix-$CF_TOKEN",
+			"empty":        "$EMPTY",
+		},
+	}
+	if err := expandEnvRefs(providers); err != nil {
+		t.Fatalf("expandEnvRefs: %v", err)
+	}
+	want := map[string]any{
+		"cloudflare": map[string]any{
+			"api_token":    "env-token",
+			"zone_id":      "env-zone",
+			"record_names": []any{"home.example.com", "vpn.example.com"},
+			"proxied":      true,
+			"comment":      "costs $5 a month",
+			"partial":      "prefix-$CF_TOKEN",
+			"empty":        "",
+		},
+	}
+	if !reflect.DeepEqual(providers, want) {
+		t.Errorf("expandEnvRefs =\n%v\nwant\n%v", providers, want)
+	}
+}
+
+func TestExpandEnvRefs_Unset(t *testing.T) {
+	os.Unsetenv("DYNAGO_TEST_UNSET_A")
+	os.Unsetenv("DYNAGO_TEST_UNSET_B")
+	providers := map[string]any{
+		"route53": map[string]any{
+			"secret_access_key": "${DYNAGO_TEST_UNSET_A}",
+			"records":           []map[string]any{{"name": "$DYNAGO_TEST_UNSET_B"}},
+		},
+	}
+	err := expandEnvRefs(providers)
+	if err == nil {
+		t.Fatal("expected unset variables to be rejected")
+	}
+	for _, want := range []string{
+		"providers.route53.secret_access_key refers to environment variable DYNAGO_TEST_UNSET_A",
+		"providers.route53.records[0].name refers to environment variable DYNAGO_TEST_UNSET_B",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+// TestLoadConfig_EnvRefs checks that LoadConfig substitutes $VARNAME provider settings.
+func TestLoadConfig_EnvRefs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	yml := strings.Replace(sampleYAML, `api_token: "cf-token"`, `api_token: $CF_TOKEN`, 1)
+	if err := os.WriteFile(path, []byte(yml), 0600); err != nil {
+		t.Fatalf("failed to write sample YAML: %v", err)
+	}
+	t.Setenv("CF_TOKEN", "env-token")
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if token := cfg.Providers["cloudflare"].(map[string]any)["api_token"]; token != "env-token" {
+		t.Errorf("expected api_token from $CF_TOKEN, got %v", token)
+	}
+
+	os.Unsetenv("CF_TOKEN")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "CF_TOKEN") {
+		t.Errorf("expected an unset $CF_TOKEN to be reported, got %v", err)
+	}
+}
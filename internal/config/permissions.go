@@ -0,0 +1,49 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// checkPermissions warns if the config file at path is readable by every user, since it usually
+// holds API credentials. With strict set, it returns an error instead.
+//
+// Windows does not report Unix permission bits, so the check is skipped there.
+func checkPermissions(path string, strict bool) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat config file %s: %w", path, err)
+	}
+	if info.Mode().Perm()&0o004 == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("config file %s is world-readable and strict_permissions is set; chmod 600 it", path)
+	}
+	logger.Warn("config file %s is world-readable; consider chmod 600", path)
+	return nil
+}
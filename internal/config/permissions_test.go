@@ -0,0 +1,93 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// writeConfigFile writes content to a new file in a temporary directory with the given mode.
+func writeConfigFile(t *testing.T, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := os.Chmod(path, mode); err != nil { // WriteFile's mode is subject to the umask
+		t.Fatalf("failed to chmod config: %v", err)
+	}
+	return path
+}
+
+// captureLog sends log output to a buffer for the rest of the test and returns a function reading it.
+func captureLog(t *testing.T) func() string {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.SetWriter(&buf)
+	t.Cleanup(func() { logger.SetWriter(nil) })
+	return buf.String
+}
+
+func TestLoadConfig_WorldReadableWarning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits are not reported on Windows")
+	}
+	readLog := captureLog(t)
+
+	if _, err := LoadConfig(writeConfigFile(t, sampleYAML, 0600)); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if strings.Contains(readLog(), "world-readable") {
+		t.Error("expected no warning for a 0600 config file")
+	}
+
+	path := writeConfigFile(t, sampleYAML, 0644)
+	if _, err := LoadConfig(path); err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if want := "config file " + path + " is world-readable; consider chmod 600"; !strings.Contains(readLog(), want) {
+		t.Errorf("expected log to contain %q, got:\n%s", want, readLog())
+	}
+}
+
+func TestLoadConfig_StrictPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits are not reported on Windows")
+	}
+	strict := sampleYAML + "strict_permissions: true\n"
+
+	_, err := LoadConfig(writeConfigFile(t, strict, 0644))
+	if err == nil || !strings.Contains(err.Error(), "world-readable") {
+		t.Errorf("expected a world-readable config to be rejected, got %v", err)
+	}
+	cfg, err := LoadConfig(writeConfigFile(t, strict, 0640))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if !cfg.StrictPermissions {
+		t.Error("expected strict_permissions to be loaded")
+	}
+}
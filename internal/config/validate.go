@@ -0,0 +1,200 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// ProviderValidator reports problems in the config section of an enabled provider.
+type ProviderValidator func(section map[string]any) error
+
+var (
+	providerValidatorsMu sync.RWMutex
+	providerValidators   = map[string]ProviderValidator{}
+)
+
+// RegisterProviderValidator makes ValidateConfig check the section of the named provider with
+// validate when it is enabled; a nil validate removes the check. The provider packages register
+// their own ValidateConfig from init, so the rules are defined once, next to the provider.
+func RegisterProviderValidator(name string, validate ProviderValidator) {
+	providerValidatorsMu.Lock()
+	defer providerValidatorsMu.Unlock()
+	if validate == nil {
+		delete(providerValidators, name)
+		return
+	}
+	providerValidators[name] = validate
+}
+
+// providerValidator returns the validator registered for the named provider, or nil.
+func providerValidator(name string) ProviderValidator {
+	providerValidatorsMu.RLock()
+	defer providerValidatorsMu.RUnlock()
+	return providerValidators[name]
+}
+
+// ValidateConfig checks cfg for problems that would stop dynago from working: an interval below
+// MinInterval (unless cfg.AllowShortInterval is set), missing or malformed IP source URLs, an unknown
+// log_format or log_target, negative log rotation limits, a malformed
+// metrics.prometheus_addr, metrics.telegraf_addr, metrics.pushgateway.url, metrics.statsd.addr,
+// metrics.datadog.addr, probes.addr, debug.expvar_addr, debug.pprof_addr, otel.trace_endpoint, or
+// otel.metrics_endpoint, an incomplete metrics.influxdb section, no enabled provider, and enabled
+// provider sections rejected by their registered validator (see RegisterProviderValidator).
+//
+// Every violation is reported, joined into a single error, rather than only the first.
+func ValidateConfig(cfg *Config) error {
+	var errs []error
+	if cfg.Interval < MinInterval && !cfg.AllowShortInterval {
+		errs = append(errs, fmt.Errorf("interval must be at least %ds to avoid provider rate limiting, got %s", int(MinInterval.Seconds()), cfg.Interval))
+	}
+	sources := cfg.AllIPSources()
+	if len(sources) == 0 {
+		errs = append(errs, errors.New("ip_source is required"))
+	}
+	if cfg.IPSourceV6 != "" {
+		sources = append(sources, cfg.IPSourceV6)
+	}
+	for _, source := range sources {
+		if err := validateIPSource(source); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.LogFormat != "" && cfg.LogFormat != logger.FormatPretty && cfg.LogFormat != logger.FormatJSON {
+		errs = append(errs, fmt.Errorf("log_format %q must be %q or %q", cfg.LogFormat, logger.FormatPretty, logger.FormatJSON))
+	}
+	switch cfg.LogTarget {
+	case "", logger.TargetConsole, logger.TargetSyslog, logger.TargetSyslogOnly:
+	default:
+		errs = append(errs, fmt.Errorf("log_target %q must be %q, %q, or %q", cfg.LogTarget, logger.TargetConsole, logger.TargetSyslog, logger.TargetSyslogOnly))
+	}
+	if cfg.LogMaxSizeMB < 0 || cfg.LogMaxBackups < 0 {
+		errs = append(errs, fmt.Errorf("log_max_size_mb and log_max_backups must not be negative, got %d and %d", cfg.LogMaxSizeMB, cfg.LogMaxBackups))
+	}
+	if cfg.MetricsAddr != "" && cfg.Metrics.PrometheusAddr != "" && cfg.MetricsAddr != cfg.Metrics.PrometheusAddr {
+		errs = append(errs, fmt.Errorf("metrics_addr %q and metrics.prometheus_addr %q disagree; set only one", cfg.MetricsAddr, cfg.Metrics.PrometheusAddr))
+	}
+	if cfg.HealthAddr != "" && cfg.Probes.Addr != "" && cfg.HealthAddr != cfg.Probes.Addr {
+		errs = append(errs, fmt.Errorf("health_addr %q and probes.addr %q disagree; set only one", cfg.HealthAddr, cfg.Probes.Addr))
+	}
+	if addr := cfg.Metrics.PrometheusAddr; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.prometheus_addr %q must be a host:port address such as \":9090\"", addr))
+		}
+	}
+	if addr := cfg.Metrics.TelegrafAddr; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.telegraf_addr %q must be a host:port address such as \":9273\"", addr))
+		} else if addr == cfg.Probes.Addr {
+			errs = append(errs, fmt.Errorf("probes.addr and metrics.telegraf_addr must differ, both are %q", addr))
+		}
+	}
+	if addr := cfg.Probes.Addr; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("probes.addr %q must be a host:port address such as \":8080\"", addr))
+		} else if addr == cfg.Metrics.PrometheusAddr {
+			errs = append(errs, fmt.Errorf("probes.addr and metrics.prometheus_addr must differ, both are %q", addr))
+		}
+	}
+	if addr := cfg.Debug.ExpvarAddr; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("debug.expvar_addr %q must be a host:port address such as \"127.0.0.1:6060\"", addr))
+		} else if addr == cfg.Probes.Addr || addr == cfg.Metrics.PrometheusAddr || addr == cfg.Metrics.TelegrafAddr {
+			errs = append(errs, fmt.Errorf("debug.expvar_addr %q must differ from probes.addr and the metrics addresses", addr))
+		}
+	}
+	if addr := cfg.Debug.PprofAddr; addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("debug.pprof_addr %q must be a host:port address such as \"127.0.0.1:6061\"", addr))
+		} else if addr == cfg.Probes.Addr || addr == cfg.Metrics.PrometheusAddr || addr == cfg.Metrics.TelegrafAddr || addr == cfg.Debug.ExpvarAddr {
+			errs = append(errs, fmt.Errorf("debug.pprof_addr %q must differ from probes.addr, debug.expvar_addr, and the metrics addresses", addr))
+		}
+	}
+	if addr := cfg.Metrics.StatsD.Addr; cfg.Metrics.StatsD.Enabled && addr != "" {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.statsd.addr %q must be a host:port address such as \"127.0.0.1:8125\"", addr))
+		}
+	}
+	if addr := cfg.Metrics.DataDog.Addr; cfg.Metrics.DataDog.Enabled && addr != "" && !strings.HasPrefix(addr, "unix://") {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errs = append(errs, fmt.Errorf("metrics.datadog.addr %q must be a host:port address or a unix:// socket path", addr))
+		}
+	}
+	if influx := cfg.Metrics.InfluxDB; influx.URL != "" {
+		if !isHTTPURL(influx.URL) {
+			errs = append(errs, fmt.Errorf("metrics.influxdb.url %q must be an http or https URL", influx.URL))
+		}
+		if influx.Org == "" || influx.Bucket == "" {
+			errs = append(errs, errors.New("metrics.influxdb: org and bucket are required when url is set"))
+		}
+	}
+	if gw := cfg.Metrics.Pushgateway.URL; gw != "" && !isHTTPURL(gw) {
+		errs = append(errs, fmt.Errorf("metrics.pushgateway.url %q must be an http or https URL", gw))
+	}
+	if endpoint := cfg.Otel.TraceEndpoint; endpoint != "" && !isHTTPURL(endpoint) {
+		errs = append(errs, fmt.Errorf("otel.trace_endpoint %q must be an http or https URL", endpoint))
+	}
+	if endpoint := cfg.Otel.MetricsEndpoint; endpoint != "" && !isHTTPURL(endpoint) {
+		errs = append(errs, fmt.Errorf("otel.metrics_endpoint %q must be an http or https URL", endpoint))
+	}
+	names := make([]string, 0, len(cfg.Providers))
+	for name := range cfg.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	enabled := 0
+	for _, name := range names {
+		section, ok := cfg.Providers[name].(map[string]any)
+		if !ok || section["enabled"] != true {
+			continue
+		}
+		enabled++
+		if validate := providerValidator(name); validate != nil {
+			if err := validate(section); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if enabled == 0 {
+		errs = append(errs, errors.New("no provider is enabled"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateIPSource checks that source is an absolute http or https URL.
+func validateIPSource(source string) error {
+	if !isHTTPURL(source) {
+		return fmt.Errorf("IP source %q must be an http or https URL", source)
+	}
+	return nil
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,161 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withProviderValidator registers validate for the named provider until the test ends. The
+// provider packages, which register the real validators, cannot be imported here.
+func withProviderValidator(t *testing.T, name string, validate ProviderValidator) {
+	t.Helper()
+	RegisterProviderValidator(name, validate)
+	t.Cleanup(func() { RegisterProviderValidator(name, nil) })
+}
+
+// requireAPIToken is a stand-in provider validator that rejects a section without api_token.
+func requireAPIToken(section map[string]any) error {
+	if section["api_token"] == nil {
+		return errors.New("cloudflare: api_token is required")
+	}
+	return nil
+}
+
+// validConfig returns a Config that passes ValidateConfig.
+func validConfig() *Config {
+	return &Config{
+		Interval: 5 * time.Minute,
+		IPSource: "https://api.ipify.org",
+		Providers: map[string]any{
+			"cloudflare": map[string]any{"enabled": true, "api_token": "token"},
+			"route53":    map[string]any{"enabled": false},
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   string // Expected substring of the error; empty for no error
+	}{
+		{"valid", func(*Config) {}, ""},
+		{"short interval", func(c *Config) { c.Interval = time.Second }, "interval must be at least 60s"},
+		{"no ip source", func(c *Config) { c.IPSource = "" }, "ip_source is required"},
+		{"ip source without scheme", func(c *Config) { c.IPSource = "api.ipify.org" }, `IP source "api.ipify.org"`},
+		{"bad fallback ip source", func(c *Config) { c.IPSources = []string{"ftp://example.com"} }, `IP source "ftp://example.com"`},
+		{"metrics addr", func(c *Config) { c.Metrics.PrometheusAddr = ":9090" }, ""},
+		{"metrics addr without port", func(c *Config) { c.Metrics.PrometheusAddr = "localhost" }, `metrics.prometheus_addr "localhost"`},
+		{"metrics_addr agrees", func(c *Config) { c.MetricsAddr, c.Metrics.PrometheusAddr = ":9090", ":9090" }, ""},
+		{"health_addr agrees", func(c *Config) { c.HealthAddr, c.Probes.Addr = ":8080", ":8080" }, ""},
+		{"health_addr disagrees", func(c *Config) { c.HealthAddr, c.Probes.Addr = ":8080", ":8081" }, "health_addr"},
+		{"metrics_addr disagrees", func(c *Config) { c.MetricsAddr, c.Metrics.PrometheusAddr = ":9090", ":9091" }, "metrics_addr"},
+		{"pushgateway url", func(c *Config) { c.Metrics.Pushgateway.URL = "http://pushgateway:9091" }, ""},
+		{"pushgateway url without scheme", func(c *Config) { c.Metrics.Pushgateway.URL = "pushgateway:9091" }, "metrics.pushgateway.url"},
+		{"probes addr without port", func(c *Config) { c.Probes.Addr = "8080" }, `probes.addr "8080"`},
+		{"telegraf addr", func(c *Config) { c.Metrics.TelegrafAddr = ":9273" }, ""},
+		{"telegraf addr without port", func(c *Config) { c.Metrics.TelegrafAddr = "localhost" }, `metrics.telegraf_addr "localhost"`},
+		{"telegraf and metrics share an address", func(c *Config) { c.Metrics.TelegrafAddr, c.Metrics.PrometheusAddr = ":9090", ":9090" }, ""},
+		{"probes and telegraf share an address", func(c *Config) { c.Probes.Addr, c.Metrics.TelegrafAddr = ":8080", ":8080" }, "must differ"},
+		{"expvar addr", func(c *Config) { c.Debug.ExpvarAddr = "127.0.0.1:6060" }, ""},
+		{"expvar addr without port", func(c *Config) { c.Debug.ExpvarAddr = "localhost" }, `debug.expvar_addr "localhost"`},
+		{"expvar and probes share an address", func(c *Config) { c.Debug.ExpvarAddr = ":8080"; c.Probes.Addr = ":8080" }, "must differ"},
+		{"pprof addr", func(c *Config) { c.Debug.PprofAddr = "127.0.0.1:6061" }, ""},
+		{"pprof addr without port", func(c *Config) { c.Debug.PprofAddr = "localhost" }, `debug.pprof_addr "localhost"`},
+		{"pprof and expvar share an address", func(c *Config) { c.Debug.PprofAddr, c.Debug.ExpvarAddr = ":6060", ":6060" }, "must differ"},
+		{"probes and metrics share an address", func(c *Config) { c.Probes.Addr, c.Metrics.PrometheusAddr = ":8080", ":8080" }, "must differ"},
+		{"otel endpoint", func(c *Config) { c.Otel.TraceEndpoint = "http://localhost:4317" }, ""},
+		{"otel endpoint without scheme", func(c *Config) { c.Otel.TraceEndpoint = "localhost:4317" }, "otel.trace_endpoint"},
+		{"otel metrics endpoint without scheme", func(c *Config) { c.Otel.MetricsEndpoint = "localhost:4317" }, "otel.metrics_endpoint"},
+		{"statsd addr", func(c *Config) { c.Metrics.StatsD = StatsDConfig{Enabled: true, Addr: "localhost:8125"} }, ""},
+		{"statsd addr without port", func(c *Config) { c.Metrics.StatsD = StatsDConfig{Enabled: true, Addr: "localhost"} }, "metrics.statsd.addr"},
+		{"datadog socket", func(c *Config) { c.Metrics.DataDog = DataDogConfig{Enabled: true, Addr: "unix:///tmp/dsd.sock"} }, ""},
+		{"datadog addr without port", func(c *Config) { c.Metrics.DataDog = DataDogConfig{Enabled: true, Addr: "localhost"} }, "metrics.datadog.addr"},
+		{"json log format", func(c *Config) { c.LogFormat = "json" }, ""},
+		{"unknown log format", func(c *Config) { c.LogFormat = "xml" }, "log_format"},
+		{"syslog log target", func(c *Config) { c.LogTarget = "syslog_only" }, ""},
+		{"unknown log target", func(c *Config) { c.LogTarget = "journald" }, "log_target"},
+		{"log rotation", func(c *Config) { c.LogMaxSizeMB, c.LogMaxBackups = 10, 3 }, ""},
+		{"negative log backups", func(c *Config) { c.LogMaxBackups = -1 }, "log_max_backups"},
+		{"influxdb", func(c *Config) { c.Metrics.InfluxDB = InfluxDBConfig{URL: "http://db:8086", Org: "o", Bucket: "b"} }, ""},
+		{"influxdb without bucket", func(c *Config) { c.Metrics.InfluxDB = InfluxDBConfig{URL: "http://db:8086", Org: "o"} }, "org and bucket"},
+		{"influxdb url without scheme", func(c *Config) { c.Metrics.InfluxDB = InfluxDBConfig{URL: "db:8086", Org: "o", Bucket: "b"} }, "metrics.influxdb.url"},
+		{"no enabled provider", func(c *Config) { c.Providers["cloudflare"] = map[string]any{"enabled": false} }, "no provider is enabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			err := ValidateConfig(cfg)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("ValidateConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ValidateConfig() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_ProviderValidators(t *testing.T) {
+	withProviderValidator(t, "cloudflare", requireAPIToken)
+	var checked []string
+	withProviderValidator(t, "route53", func(section map[string]any) error {
+		checked = append(checked, "route53")
+		return nil
+	})
+
+	cfg := validConfig()
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+	if len(checked) != 0 {
+		t.Error("expected a disabled provider not to be validated")
+	}
+	cfg.Providers["cloudflare"] = map[string]any{"enabled": true}
+	if err := ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "cloudflare: api_token is required") {
+		t.Errorf("expected the registered validator's error, got %v", err)
+	}
+
+	RegisterProviderValidator("cloudflare", nil)
+	if err := ValidateConfig(cfg); err != nil {
+		t.Errorf("expected no check after the validator was removed, got %v", err)
+	}
+}
+
+func TestValidateConfig_ReportsAllViolations(t *testing.T) {
+	withProviderValidator(t, "cloudflare", requireAPIToken)
+	cfg := &Config{Interval: time.Second, IPSource: "not a url", Providers: map[string]any{"cloudflare": map[string]any{"enabled": true}}}
+	err := ValidateConfig(cfg)
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"interval", "IP source", "api_token is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+}
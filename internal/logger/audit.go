@@ -0,0 +1,87 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// auditHeader is the first row of a new audit log.
+var auditHeader = []string{"timestamp", "provider", "record", "old_ip", "new_ip"}
+
+// AuditEntry is one row of the audit log: an IP change applied to a provider's record.
+type AuditEntry struct {
+	Time     string // When the change was applied, e.g. in RFC 3339 format
+	Provider string // Provider name
+	Record   string // Record name(s) that changed
+	OldIP    string // Previous value, empty if the record did not exist
+	NewIP    string // New value
+}
+
+// AuditLogger appends AuditEntry rows to a CSV file. Unlike the application log it is never
+// filtered by level, rotated, or written to the console. It is safe for concurrent use.
+type AuditLogger struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *csv.Writer
+}
+
+// NewAuditLogger opens path for appending, creating it with mode 0600 if needed, since it records
+// the host's IP addresses. A new or empty file gets a timestamp,provider,record,old_ip,new_ip
+// header row first.
+//
+// Returns an error if the file cannot be opened or the header cannot be written.
+func NewAuditLogger(path string) (*AuditLogger, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	a := &AuditLogger{file: file, w: csv.NewWriter(file)}
+	info, err := file.Stat()
+	if err == nil && info.Size() == 0 {
+		err = a.write(auditHeader)
+	}
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write audit log header: %w", err)
+	}
+	return a, nil
+}
+
+// Append writes entry as one row and flushes it to the file.
+func (a *AuditLogger) Append(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.write([]string{entry.Time, entry.Provider, entry.Record, entry.OldIP, entry.NewIP})
+}
+
+// Close closes the file. Rows already appended have been flushed.
+func (a *AuditLogger) Close() error {
+	return a.file.Close()
+}
+
+// write writes and flushes a single row.
+func (a *AuditLogger) write(row []string) error {
+	a.w.Write(row) // Errors are reported by Error after Flush
+	a.w.Flush()
+	return a.w.Error()
+}
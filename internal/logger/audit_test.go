@@ -0,0 +1,116 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// readAudit parses the audit log at path.
+func readAudit(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("audit log is not valid CSV: %v", err)
+	}
+	return rows
+}
+
+// TestAuditLogger_CSV verifies the header and that fields needing quotes, such as a list of
+// record names, survive a round trip.
+func TestAuditLogger_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.csv")
+	a, err := NewAuditLogger(path)
+	if err != nil {
+		t.Fatalf("NewAuditLogger failed: %v", err)
+	}
+	entry := AuditEntry{
+		Time:     "2025-06-01T12:00:00Z",
+		Provider: "cloudflare",
+		Record:   "home.example.com,\"vpn\".example.com",
+		OldIP:    "1.2.3.4",
+		NewIP:    "5.6.7.8",
+	}
+	if err := a.Append(entry); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	// Append flushes, so the row is readable before Close.
+	want := [][]string{
+		{"timestamp", "provider", "record", "old_ip", "new_ip"},
+		{entry.Time, entry.Provider, entry.Record, entry.OldIP, entry.NewIP},
+	}
+	if got := readAudit(t, path); !reflect.DeepEqual(got, want) {
+		t.Errorf("audit log = %q, want %q", got, want)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil {
+		t.Errorf("Stat failed: %v", err)
+	} else if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm != 0o600 {
+		t.Errorf("expected the audit log to be created with mode 0600, got %v", perm)
+	}
+}
+
+// TestAuditLogger_Appends verifies that entries accumulate across calls and reopenings, with the
+// header written only once, whatever the log level.
+func TestAuditLogger_Appends(t *testing.T) {
+	SetLevel("error")
+	t.Cleanup(func() { SetLevel("info") })
+	path := filepath.Join(t.TempDir(), "audit.csv")
+	for i, newIP := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		a, err := NewAuditLogger(path)
+		if err != nil {
+			t.Fatalf("NewAuditLogger failed: %v", err)
+		}
+		if err := a.Append(AuditEntry{Time: "t", Provider: "route53", Record: "home.example.com", NewIP: newIP}); err != nil {
+			t.Fatalf("Append %d failed: %v", i, err)
+		}
+		if i == 1 {
+			a.Append(AuditEntry{Time: "t", Provider: "cloudflare", Record: "vpn.example.com", OldIP: "9.9.9.9", NewIP: newIP})
+		}
+		a.Close()
+	}
+
+	rows := readAudit(t, path)
+	if len(rows) != 5 || rows[0][0] != "timestamp" {
+		t.Fatalf("expected a header and 4 rows, got %q", rows)
+	}
+	var newIPs []string
+	for _, row := range rows[1:] {
+		newIPs = append(newIPs, row[4])
+	}
+	if want := []string{"1.1.1.1", "2.2.2.2", "2.2.2.2", "3.3.3.3"}; !reflect.DeepEqual(newIPs, want) {
+		t.Errorf("new_ip column = %q, want %q", newIPs, want)
+	}
+	if rows[3][1] != "cloudflare" || rows[3][3] != "9.9.9.9" {
+		t.Errorf("unexpected row %q", rows[3])
+	}
+}
@@ -0,0 +1,61 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// correlationKey is the context key under which ContextWithCorrelationID stores the ID.
+type correlationKey struct{}
+
+// NewCorrelationID returns a random (version 4) UUID for tying together the log lines of one
+// operation, such as an update cycle.
+func NewCorrelationID() string {
+	var b [16]byte
+	rand.Read(b[:])         // never fails; see crypto/rand.Read
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id, for WithCorrelationID to find.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the ID stored in ctx by ContextWithCorrelationID, or "" if there is none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// WithCorrelationID returns a logger writing to the current outputs that adds a "correlation_id"
+// field with the ID carried by ctx. Without an ID, it is a plain logger.
+func WithCorrelationID(ctx context.Context) zerolog.Logger {
+	id := CorrelationID(ctx)
+	if id == "" {
+		return base
+	}
+	return base.With().Str("correlation_id", id).Logger()
+}
@@ -0,0 +1,72 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewCorrelationID(t *testing.T) {
+	a, b := NewCorrelationID(), NewCorrelationID()
+	if !uuidPattern.MatchString(a) {
+		t.Errorf("expected a version 4 UUID, got %q", a)
+	}
+	if a == b {
+		t.Errorf("expected distinct IDs, got %q twice", a)
+	}
+}
+
+// TestWithCorrelationID verifies that the ID carried by the context is logged as the
+// correlation_id field, and that no field is added without one.
+func TestWithCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriter(&buf)
+	t.Cleanup(func() { SetWriter(nil) })
+
+	ctx := ContextWithCorrelationID(context.Background(), "cycle-1")
+	if got := CorrelationID(ctx); got != "cycle-1" {
+		t.Errorf("CorrelationID() = %q, want cycle-1", got)
+	}
+	log := WithCorrelationID(ctx)
+	log.Info().Msg("with id")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+	}
+	if entry["correlation_id"] != "cycle-1" {
+		t.Errorf("expected correlation_id cycle-1, got %v", entry)
+	}
+
+	buf.Reset()
+	log = WithCorrelationID(context.Background())
+	log.Info().Msg("without id")
+	entry = nil
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+	}
+	if _, ok := entry["correlation_id"]; ok {
+		t.Errorf("expected no correlation_id without one in the context, got %v", entry)
+	}
+}
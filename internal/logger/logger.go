@@ -18,57 +18,172 @@
 
 // Package logger provides a simple logging system for the dynago application.
 //
-// It supports different log levels (debug, info, warn, error), writes logs to a file and pretty-prints to the console.
+// It supports different log levels (debug, info, warn, error), writes logs to a file and pretty-prints to the console,
+// or writes newline-delimited JSON to the console for log aggregation pipelines. On Unix systems, logs can also be
+// sent to syslog.
 package logger
 
 import (
+	"fmt"
 	"io"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Console output formats accepted by InitLogger.
+const (
+	FormatPretty = "pretty" // Human-readable, colorized lines (the default)
+	FormatJSON   = "json"   // One JSON object per line
+)
+
+// Log targets accepted by InitLogger.
+const (
+	TargetConsole    = "console"     // The console only (the default)
+	TargetSyslog     = "syslog"      // The console and syslog
+	TargetSyslogOnly = "syslog_only" // Syslog only
+)
+
+// Rotation configures rotation of the application log file. The zero value disables rotation,
+// so the file grows indefinitely.
+type Rotation struct {
+	MaxSizeMB  int  // Size in megabytes at which the file is rotated (lumberjack's default of 100 if 0)
+	MaxBackups int  // Rotated files to keep (all if 0)
+	Compress   bool // Gzip rotated files
+}
+
+// enabled reports whether any rotation setting is set.
+func (r Rotation) enabled() bool {
+	return r != Rotation{}
+}
+
 var (
-	logLevel  zerolog.Level // Track the configured log level
-	appWriter io.Writer     // Writer for application logs (file or discard)
+	logLevel      zerolog.Level              // Track the configured log level
+	appWriter     io.Writer     = io.Discard // Writer for application logs (file or discard)
+	stdout        io.Writer     = os.Stdout  // Console destination (replaced in tests)
+	jsonOutput    bool                       // Write JSON to the console instead of pretty lines
+	consoleOutput = true                     // Write to the console; false with TargetSyslogOnly
+	sysWriter     io.Writer                  // Syslog writer for TargetSyslog and TargetSyslogOnly, or nil
 )
 
 // InitLogger initializes the logging system for the application.
 //
-// All log messages are written to both the specified file and the console (with pretty formatting).
+// All log messages are written to both the specified file, as JSON, and the console. With
+// TargetSyslog they are also sent as JSON to the local syslog daemon with facility LOG_DAEMON,
+// at the syslog severity matching their level; TargetSyslogOnly does the same without the console.
+// Syslog is not available on Windows, where both syslog targets log a warning and use the console.
 //
 // Parameters:
 //   - appLogFile:   Path to the application log file. If empty, logs are discarded.
 //   - level:        Logging level ("debug", "info", "warn", "error").
+//   - format:       Console format, FormatPretty or FormatJSON ("" means FormatPretty).
+//   - target:       TargetConsole, TargetSyslog, or TargetSyslogOnly ("" means TargetConsole).
+//   - rotation:     Rotation of the log file; when set, the file is written through lumberjack.
+//
+// A log file or syslog connection opened by a previous call is closed.
 //
-// Returns an error if the log file cannot be opened.
+// Returns an error if the format or target is unknown, or the log file or syslog cannot be opened.
 //
 // Example:
 //
-//	err := logger.InitLogger("app.log", "", "debug")
+//	err := logger.InitLogger("app.log", "debug", logger.FormatPretty, logger.TargetConsole, logger.Rotation{MaxSizeMB: 10, MaxBackups: 3})
 //	if err != nil {
 //	    panic(err)
 //	}
-func InitLogger(appLogFile, level string) error {
+func InitLogger(appLogFile, level, format, target string, rotation Rotation) error {
+	switch format {
+	case "", FormatPretty:
+		jsonOutput = false
+	case FormatJSON:
+		jsonOutput = true
+	default:
+		return fmt.Errorf("unknown log format %q (want %q or %q)", format, FormatPretty, FormatJSON)
+	}
+	switch target {
+	case "", TargetConsole, TargetSyslog, TargetSyslogOnly:
+	default:
+		return fmt.Errorf("unknown log target %q (want %q, %q, or %q)", target, TargetConsole, TargetSyslog, TargetSyslogOnly)
+	}
+	for _, w := range []io.Writer{appWriter, sysWriter} {
+		if c, ok := w.(io.Closer); ok {
+			c.Close()
+		}
+	}
 	appWriter = io.Discard
+	sysWriter = nil
+	consoleOutput = true
 
-	var appFile *os.File
-	var err error
+	useSyslog := target == TargetSyslog || target == TargetSyslogOnly
+	if useSyslog && syslogSupported {
+		w, err := newSyslogWriter()
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		sysWriter = w
+		consoleOutput = target == TargetSyslog
+	}
 
-	if appLogFile != "" {
-		appFile, err = os.OpenFile(appLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	switch {
+	case appLogFile == "":
+	case rotation.enabled():
+		rotated := &lumberjack.Logger{
+			Filename:   appLogFile,
+			MaxSize:    rotation.MaxSizeMB,
+			MaxBackups: rotation.MaxBackups,
+			Compress:   rotation.Compress,
+		}
+		// lumberjack opens the file on the first write; an empty write opens it now, so an
+		// unwritable log file fails here instead of after startup.
+		if _, err := rotated.Write(nil); err != nil {
+			return err
+		}
+		appWriter = rotated
+	default:
+		appFile, err := os.OpenFile(appLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
 			return err
 		}
 		appWriter = appFile
 	}
 
+	SetLevel(level)
+
+	if useSyslog && !syslogSupported {
+		Warn("log_target %q is not supported on %s, logging to the console instead", target, runtime.GOOS)
+	}
+	return nil
+}
+
+// SetWriter replaces the application log writer, normally the log file opened by InitLogger, with w.
+// Entries are written to it as JSON whatever the console format, one at a time even when logged
+// from several goroutines. A nil w discards them.
+//
+// It lets tests in any package capture log output:
+//
+//	var buf bytes.Buffer
+//	logger.SetWriter(&buf)
+//	t.Cleanup(func() { logger.SetWriter(nil) })
+//
+// A log file opened by InitLogger is closed; w itself is never closed by this package.
+func SetWriter(w io.Writer) {
+	if c, ok := appWriter.(io.Closer); ok {
+		c.Close()
+	}
+	appWriter = io.Discard
+	if w != nil {
+		appWriter = zerolog.SyncWriter(w)
+	}
+}
+
+// SetLevel sets the minimum level logged: "debug", "info", "warn", or "error". Any other value,
+// including "", means "info".
+func SetLevel(level string) {
 	switch strings.ToLower(level) {
 	case "debug":
 		logLevel = zerolog.DebugLevel
-	case "info":
-		logLevel = zerolog.InfoLevel
 	case "warn":
 		logLevel = zerolog.WarnLevel
 	case "error":
@@ -77,8 +192,51 @@ func InitLogger(appLogFile, level string) error {
 		logLevel = zerolog.InfoLevel
 	}
 	zerolog.SetGlobalLevel(logLevel)
+}
 
-	return nil
+// output returns the writer for a log entry: the log file, the console unless the target is
+// TargetSyslogOnly, and syslog if enabled.
+func output() zerolog.LevelWriter {
+	writers := []io.Writer{appWriter}
+	if consoleOutput {
+		writers = append(writers, console())
+	}
+	if sysWriter != nil {
+		writers = append(writers, sysWriter)
+	}
+	return zerolog.MultiLevelWriter(writers...)
+}
+
+// liveOutput writes each entry to output() as it is at that moment, so loggers created before a
+// later InitLogger call follow it.
+type liveOutput struct{}
+
+func (liveOutput) Write(p []byte) (int, error) { return output().Write(p) }
+
+func (liveOutput) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	return output().WriteLevel(level, p)
+}
+
+// base is the package-level zerolog.Logger that structured loggers are derived from. It writes where
+// the package-level functions do, and like them obeys the level set by InitLogger.
+var base = zerolog.New(liveOutput{}).With().Timestamp().Caller().Logger()
+
+// WithProvider returns a logger whose entries carry a "provider" field set to name, for structured
+// logging about one DNS provider:
+//
+//	log := logger.WithProvider("cloudflare")
+//	log.Info().Msgf("cloudflare: DNS record updated to %s", ip)
+func WithProvider(name string) zerolog.Logger {
+	return base.With().Str("provider", name).Logger()
+}
+
+// console returns the writer for console output: stdout itself in JSON mode, or a ConsoleWriter
+// pretty-printing to it.
+func console() io.Writer {
+	if jsonOutput {
+		return stdout
+	}
+	return zerolog.ConsoleWriter{Out: stdout, TimeFormat: "2006-01-02 15:04:05"}
 }
 
 // Info logs an informational message if the log level allows it.
@@ -87,9 +245,7 @@ func InitLogger(appLogFile, level string) error {
 //	args:   Arguments for the format string.
 func Info(format string, args ...any) {
 	if logLevel <= zerolog.InfoLevel {
-		console := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
-		mw := io.MultiWriter(appWriter, console)
-		l := zerolog.New(mw).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+		l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
 		l.Info().Msgf(format, args...)
 	}
 }
@@ -100,9 +256,7 @@ func Info(format string, args ...any) {
 //	args:   Arguments for the format string.
 func Warn(format string, args ...any) {
 	if logLevel <= zerolog.WarnLevel {
-		console := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
-		mw := io.MultiWriter(appWriter, console)
-		l := zerolog.New(mw).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+		l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
 		l.Warn().Msgf(format, args...)
 	}
 }
@@ -113,22 +267,58 @@ func Warn(format string, args ...any) {
 //	args:   Arguments for the format string.
 func Error(format string, args ...any) {
 	if logLevel <= zerolog.ErrorLevel {
-		console := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
-		mw := io.MultiWriter(appWriter, console)
-		l := zerolog.New(mw).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+		l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
 		l.Error().Msgf(format, args...)
 	}
 }
 
+// exit ends the process after Fatal logs its message (see SetExitFunc).
+var exit = os.Exit
+
+// SetExitFunc makes Fatal call f instead of os.Exit, so tests can check that it was called and
+// with which status. A nil f restores os.Exit:
+//
+//	var codes []int
+//	logger.SetExitFunc(func(code int) { codes = append(codes, code) })
+//	t.Cleanup(func() { logger.SetExitFunc(nil) })
+func SetExitFunc(f func(code int)) {
+	if f == nil {
+		f = os.Exit
+	}
+	exit = f
+}
+
+// Fatal logs a fatal-level message, whatever the log level, then exits the process with status 1
+// like zerolog's Fatal. Deferred functions do not run, so use it only where nothing needs to be
+// shut down; elsewhere, log an error and return it.
+//
+//	format: Format string (like fmt.Printf).
+//	args:   Arguments for the format string.
+func Fatal(format string, args ...any) {
+	l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+	l.WithLevel(zerolog.FatalLevel).Msgf(format, args...)
+	exit(1)
+}
+
+// Panic logs a panic-level message, whatever the log level, then panics with the message like
+// zerolog's Panic.
+//
+//	format: Format string (like fmt.Printf).
+//	args:   Arguments for the format string.
+func Panic(format string, args ...any) {
+	l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+	msg := fmt.Sprintf(format, args...)
+	l.WithLevel(zerolog.PanicLevel).Msg(msg)
+	panic(msg)
+}
+
 // Debug logs a debug message if the log level allows it.
 //
 //	format: Format string (like fmt.Printf).
 //	args:   Arguments for the format string.
 func Debug(format string, args ...any) {
 	if logLevel <= zerolog.DebugLevel {
-		console := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
-		mw := io.MultiWriter(appWriter, console)
-		l := zerolog.New(mw).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
+		l := zerolog.New(output()).With().Timestamp().CallerWithSkipFrameCount(3).Logger()
 		l.Debug().Msgf(format, args...)
 	}
 }
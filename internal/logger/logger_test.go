@@ -24,31 +24,158 @@ package logger
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
-
-	"github.com/rs/zerolog"
 )
 
-// captureOutput is a helper function that captures log output written to appWriter.
-// It sets the log level to DebugLevel, runs the provided function, and returns the captured output as a string.
+// captureOutput is a helper function that captures log output written with SetWriter.
+// It sets the log level to debug, runs the provided function, and returns the captured output as a string.
 func captureOutput(f func()) string {
 	var buf bytes.Buffer
-	appWriter = &buf
-	logLevel = zerolog.DebugLevel
-	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	SetWriter(&buf)
+	SetLevel("debug")
+	defer func() {
+		SetWriter(nil)
+		SetLevel("info")
+	}()
 	f()
 	return buf.String()
 }
 
 // TestInitLogger_Defaults verifies that InitLogger initializes without error when given an empty log file path and 'info' log level.
 func TestInitLogger_Defaults(t *testing.T) {
-	err := InitLogger("", "info")
+	err := InitLogger("", "info", "", "", Rotation{})
 	if err != nil {
 		t.Fatalf("InitLogger failed: %v", err)
 	}
 }
 
+// captureConsole redirects console output to a buffer for the rest of the test.
+func captureConsole(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	stdout = &buf
+	t.Cleanup(func() {
+		stdout = os.Stdout
+		jsonOutput = false
+	})
+	return &buf
+}
+
+// TestInitLogger_JSON verifies that the JSON format writes one JSON object per line to the console.
+func TestInitLogger_JSON(t *testing.T) {
+	buf := captureConsole(t)
+	if err := InitLogger("", "info", FormatJSON, "", Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	Info("hello %s", "world")
+	Warn("careful")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %d: %q", len(lines), buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("console output is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if entry["level"] != "info" || entry["message"] != "hello world" {
+		t.Errorf("unexpected level or message in %v", entry)
+	}
+	for _, field := range []string{"time", "caller"} {
+		if _, ok := entry[field]; !ok {
+			t.Errorf("expected field %q in %v", field, entry)
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil || entry["level"] != "warn" {
+		t.Errorf("expected a warn JSON line, got %q (%v)", lines[1], err)
+	}
+}
+
+// TestInitLogger_Pretty verifies that the default format pretty-prints rather than writing JSON.
+func TestInitLogger_Pretty(t *testing.T) {
+	buf := captureConsole(t)
+	if err := InitLogger("", "info", "", "", Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	Info("hello")
+	if out := buf.String(); !strings.Contains(out, "hello") || json.Valid([]byte(strings.TrimSpace(out))) {
+		t.Errorf("expected a pretty log line, got %q", out)
+	}
+}
+
+// TestInitLogger_UnknownFormat verifies that InitLogger rejects an unknown format.
+func TestInitLogger_UnknownFormat(t *testing.T) {
+	if err := InitLogger("", "info", "xml", "", Rotation{}); err == nil {
+		t.Error("expected an unknown log format to be rejected")
+	}
+}
+
+// TestInitLogger_UnknownTarget verifies that InitLogger rejects an unknown log target.
+func TestInitLogger_UnknownTarget(t *testing.T) {
+	if err := InitLogger("", "info", "", "journald", Rotation{}); err == nil {
+		t.Error("expected an unknown log target to be rejected")
+	}
+}
+
+// TestInitLogger_Rotation verifies that a log file with rotation set is rotated once it exceeds
+// MaxSizeMB, keeping a backup alongside the new file.
+func TestInitLogger_Rotation(t *testing.T) {
+	stdout = io.Discard
+	t.Cleanup(func() {
+		stdout = os.Stdout
+		InitLogger("", "info", "", "", Rotation{}) // Closes the log file
+	})
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "dynago.log")
+	if err := InitLogger(logFile, "info", "", "", Rotation{MaxSizeMB: 1, MaxBackups: 2}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+
+	line := strings.Repeat("x", 1024)
+	for range 1100 { // Just over 1 MB
+		Info("%s", line)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	var backups int
+	for _, entry := range entries {
+		if entry.Name() != "dynago.log" && strings.HasPrefix(entry.Name(), "dynago-") {
+			backups++
+		}
+	}
+	if backups != 1 {
+		t.Errorf("expected 1 backup file after crossing 1 MB, found %v", entries)
+	}
+	info, err := os.Stat(logFile)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Size() >= 1024*1024 {
+		t.Errorf("expected the current log file to be smaller than 1 MB after rotation, got %d bytes", info.Size())
+	}
+}
+
+// TestInitLogger_RotationUnwritable verifies that a rotated log file that cannot be opened is
+// reported by InitLogger rather than on the first write.
+func TestInitLogger_RotationUnwritable(t *testing.T) {
+	t.Cleanup(func() { InitLogger("", "info", "", "", Rotation{}) })
+	notDir := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(notDir, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := InitLogger(filepath.Join(notDir, "dynago.log"), "info", "", "", Rotation{MaxSizeMB: 1}); err == nil {
+		t.Error("expected InitLogger to fail for a log file under a regular file")
+	}
+}
+
 // TestInfoLog checks that Info logs the expected formatted message to the application log.
 func TestInfoLog(t *testing.T) {
 	output := captureOutput(func() {
@@ -79,6 +206,46 @@ func TestErrorLog(t *testing.T) {
 	}
 }
 
+// mockExit replaces the exit function for the rest of the test and returns the recorded exit codes.
+func mockExit(t *testing.T) *[]int {
+	t.Helper()
+	var codes []int
+	SetExitFunc(func(code int) { codes = append(codes, code) })
+	t.Cleanup(func() { SetExitFunc(nil) })
+	return &codes
+}
+
+// TestFatalLog checks that Fatal logs the message at fatal level, even when the level filters
+// errors, and then exits with status 1.
+func TestFatalLog(t *testing.T) {
+	codes := mockExit(t)
+	output := captureOutput(func() {
+		SetLevel("error")
+		Fatal("fatal message: %s", "baz")
+	})
+	if !strings.Contains(output, "fatal message: baz") || !strings.Contains(output, `"level":"fatal"`) {
+		t.Errorf("Fatal log not found in output: %s", output)
+	}
+	if len(*codes) != 1 || (*codes)[0] != 1 {
+		t.Errorf("expected a single exit with status 1, got %v", *codes)
+	}
+}
+
+// TestPanicLog checks that Panic logs the message at panic level and then panics with it.
+func TestPanicLog(t *testing.T) {
+	var recovered any
+	output := captureOutput(func() {
+		defer func() { recovered = recover() }()
+		Panic("panic message: %d", 7)
+	})
+	if !strings.Contains(output, "panic message: 7") || !strings.Contains(output, `"level":"panic"`) {
+		t.Errorf("Panic log not found in output: %s", output)
+	}
+	if recovered != "panic message: 7" {
+		t.Errorf("expected a panic with the message, got %v", recovered)
+	}
+}
+
 // TestDebugLog_Enabled checks that Debug logs the expected message when logLevel is DebugLevel.
 func TestDebugLog_Enabled(t *testing.T) {
 	output := captureOutput(func() {
@@ -92,11 +259,76 @@ func TestDebugLog_Enabled(t *testing.T) {
 // TestDebugLog_Disabled checks that Debug does not log anything when logLevel is higher than DebugLevel.
 func TestDebugLog_Disabled(t *testing.T) {
 	var buf bytes.Buffer
-	appWriter = &buf
-	logLevel = zerolog.InfoLevel
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	SetWriter(&buf)
+	SetLevel("info")
+	t.Cleanup(func() { SetWriter(nil) })
 	Debug("should not appear")
 	if buf.Len() != 0 {
 		t.Errorf("Debug log written when disabled")
 	}
 }
+
+// TestWithProvider verifies that a provider logger adds the provider field, reports its caller,
+// and obeys the level and format set by a later InitLogger call.
+func TestWithProvider(t *testing.T) {
+	buf := captureConsole(t)
+	log := WithProvider("cloudflare")
+	if err := InitLogger("", "warn", FormatJSON, "", Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	t.Cleanup(func() { InitLogger("", "info", "", "", Rotation{}) })
+	log.Info().Msg("hidden")
+	log.Warn().Msgf("rate limited %d times", 2)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+	}
+	if entry["provider"] != "cloudflare" || entry["level"] != "warn" || entry["message"] != "rate limited 2 times" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+		t.Errorf("expected the caller to be the test, got %q", caller)
+	}
+}
+
+// TestSetWriter verifies that SetWriter receives JSON entries even with the pretty console format,
+// and that a nil writer discards them.
+func TestSetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriter(&buf)
+	t.Cleanup(func() { SetWriter(nil) })
+	Info("captured")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil || entry["message"] != "captured" {
+		t.Fatalf("expected a JSON entry, got %q (%v)", buf.String(), err)
+	}
+
+	buf.Reset()
+	SetWriter(nil)
+	Info("discarded")
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing written after SetWriter(nil), got %q", buf.String())
+	}
+}
+
+// TestSetLevel verifies that SetLevel filters entries for both the package-level functions and
+// structured loggers.
+func TestSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	SetWriter(&buf)
+	SetLevel("warn")
+	t.Cleanup(func() {
+		SetWriter(nil)
+		SetLevel("info")
+	})
+	log := WithProvider("mock")
+	Info("hidden")
+	log.Info().Msg("hidden")
+	Warn("shown")
+	log.Error().Msg("shown")
+
+	if got := strings.Count(buf.String(), "\n"); got != 2 || strings.Contains(buf.String(), "hidden") {
+		t.Errorf("expected only the warn and error entries, got %q", buf.String())
+	}
+}
@@ -0,0 +1,50 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import "github.com/rs/zerolog"
+
+// redactMask replaces the hidden part of a redacted value.
+const redactMask = "****"
+
+// redactKeep is the number of characters Redact keeps at each end of a value.
+const redactKeep = 4
+
+// Redact masks a secret such as an API token for logging, keeping its first and last four
+// characters so the credential in use can still be told apart, e.g. "abcd****wxyz". Values of
+// eight characters or fewer are masked entirely, and an empty value stays empty so unset secrets
+// remain visible.
+func Redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= 2*redactKeep {
+		return redactMask
+	}
+	return string(r[:redactKeep]) + redactMask + string(r[len(r)-redactKeep:])
+}
+
+// MaskedField returns a dictionary event holding key with its value passed through Redact, for
+// structured logging with zerolog:
+//
+//	l.Info().Dict("credentials", logger.MaskedField("api_token", token)).Msg("token verified")
+func MaskedField(key, value string) *zerolog.Event {
+	return zerolog.Dict().Str(key, Redact(value))
+}
@@ -0,0 +1,81 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// testToken is a made-up Cloudflare-style API token.
+const testToken = "cfTk9aQ2ZxW7mNpL4rVb8sYdE1uH6gJ3"
+
+// TestRedact verifies the masking of short, long, and empty values.
+func TestRedact(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"short":      "****",
+		"12345678":   "****",
+		"123456789":  "1234****6789",
+		testToken:    "cfTk****6gJ3",
+		"pässwörter": "päss****rter",
+	}
+	for in, want := range tests {
+		if got := Redact(in); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestRedact_LogOutput verifies that a token logged through Redact is masked in the output.
+func TestRedact_LogOutput(t *testing.T) {
+	out := captureOutput(func() {
+		Info("cloudflare: API token %s active", Redact(testToken))
+	})
+	if strings.Contains(out, testToken) {
+		t.Fatalf("expected the token to be masked, got %q", out)
+	}
+	if !strings.Contains(out, "cfTk****6gJ3") {
+		t.Errorf("expected the masked token in the output, got %q", out)
+	}
+}
+
+// TestMaskedField verifies that MaskedField masks the value in structured output.
+func TestMaskedField(t *testing.T) {
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	l.Info().Dict("credentials", MaskedField("api_token", testToken)).Msg("token verified")
+
+	if strings.Contains(buf.String(), testToken) {
+		t.Fatalf("expected the token to be masked, got %q", buf.String())
+	}
+	var entry struct {
+		Credentials map[string]string `json:"credentials"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if got := entry.Credentials["api_token"]; got != "cfTk****6gJ3" {
+		t.Errorf("expected api_token cfTk****6gJ3, got %q", got)
+	}
+}
@@ -0,0 +1,46 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+
+	"github.com/rs/zerolog"
+)
+
+// syslogSupported reports whether syslog targets are available on this platform.
+const syslogSupported = true
+
+// openSyslog connects to the local syslog daemon with facility LOG_DAEMON (replaced in tests).
+var openSyslog = func() (zerolog.SyslogWriter, error) {
+	return syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, "dynago")
+}
+
+// newSyslogWriter opens syslog and returns a writer sending each entry at the syslog severity
+// matching its level. The writer is an io.Closer closing the connection.
+func newSyslogWriter() (io.Writer, error) {
+	w, err := openSyslog()
+	if err != nil {
+		return nil, err
+	}
+	return zerolog.SyslogLevelWriter(w), nil
+}
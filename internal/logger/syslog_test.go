@@ -0,0 +1,116 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeSyslog records the messages sent to it, prefixed with their syslog severity.
+type fakeSyslog struct {
+	entries []string
+	closed  bool
+}
+
+func (f *fakeSyslog) record(severity, m string) error {
+	f.entries = append(f.entries, severity+" "+strings.TrimSpace(m))
+	return nil
+}
+
+func (f *fakeSyslog) Write(p []byte) (int, error) { return len(p), f.record("none", string(p)) }
+func (f *fakeSyslog) Debug(m string) error        { return f.record("debug", m) }
+func (f *fakeSyslog) Info(m string) error         { return f.record("info", m) }
+func (f *fakeSyslog) Warning(m string) error      { return f.record("warning", m) }
+func (f *fakeSyslog) Err(m string) error          { return f.record("err", m) }
+func (f *fakeSyslog) Emerg(m string) error        { return f.record("emerg", m) }
+func (f *fakeSyslog) Crit(m string) error         { return f.record("crit", m) }
+func (f *fakeSyslog) Close() error                { f.closed = true; return nil }
+
+// defaultOpenSyslog is the real openSyslog, restored after each test.
+var defaultOpenSyslog = openSyslog
+
+// useFakeSyslog makes InitLogger connect to a fakeSyslog for the rest of the test.
+func useFakeSyslog(t *testing.T) *fakeSyslog {
+	t.Helper()
+	fake := &fakeSyslog{}
+	openSyslog = func() (zerolog.SyslogWriter, error) { return fake, nil }
+	t.Cleanup(func() {
+		InitLogger("", "info", "", "", Rotation{}) // Closes the fake
+		openSyslog = defaultOpenSyslog
+	})
+	return fake
+}
+
+// TestInitLogger_Syslog verifies that TargetSyslog sends entries to syslog at the matching
+// severity while still writing to the console.
+func TestInitLogger_Syslog(t *testing.T) {
+	buf := captureConsole(t)
+	fake := useFakeSyslog(t)
+	if err := InitLogger("", "info", "", TargetSyslog, Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	Info("hello")
+	Warn("careful")
+	Error("broken")
+	Debug("hidden")
+
+	if len(fake.entries) != 3 {
+		t.Fatalf("expected 3 syslog entries, got %q", fake.entries)
+	}
+	for i, severity := range []string{"info", "warning", "err"} {
+		if !strings.HasPrefix(fake.entries[i], severity+" {") {
+			t.Errorf("expected a JSON %s entry, got %q", severity, fake.entries[i])
+		}
+	}
+	if !strings.Contains(fake.entries[0], `"message":"hello"`) {
+		t.Errorf("expected the message in the syslog entry, got %q", fake.entries[0])
+	}
+	if !strings.Contains(buf.String(), "hello") {
+		t.Errorf("expected the console to be written too, got %q", buf.String())
+	}
+
+	if err := InitLogger("", "info", "", TargetConsole, Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	if !fake.closed {
+		t.Error("expected the syslog connection to be closed when the logger is reinitialized")
+	}
+}
+
+// TestInitLogger_SyslogOnly verifies that TargetSyslogOnly leaves the console silent.
+func TestInitLogger_SyslogOnly(t *testing.T) {
+	buf := captureConsole(t)
+	fake := useFakeSyslog(t)
+	if err := InitLogger("", "info", "", TargetSyslogOnly, Rotation{}); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	Info("hello")
+
+	if len(fake.entries) != 1 {
+		t.Errorf("expected 1 syslog entry, got %q", fake.entries)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no console output, got %q", buf.String())
+	}
+}
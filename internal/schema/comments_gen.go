@@ -0,0 +1,117 @@
+// Code generated by gen_comments.go; DO NOT EDIT.
+
+package schema
+
+// fieldComments holds the doc comments of the config types, keyed by type and field name.
+var fieldComments = map[string]string{
+	"github.com/aaronlmathis/dynago/internal/config.Config":                                      "Config represents the root configuration for dynago loaded from YAML.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.AllowShortInterval":                   "AllowShortInterval accepts an interval below MinInterval (set by --allow-short-interval for testing).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.AuditLog":                             "AuditLog is a CSV file every applied IP change is appended to, whatever the log level (empty disables).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.CircuitBreakerThreshold":              "CircuitBreakerThreshold is how many consecutive failures pause a provider (default 5).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.CircuitBreakerTimeout":                "CircuitBreakerTimeout is how long a paused provider is skipped before a trial call (default 5m).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.DebounceCount":                        "DebounceCount is how many consecutive cycles must report the same new IP before updating (0 or 1 disables).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Debug":                                "Debug configures debugging endpoints, all disabled by default.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.DryRun":                               "Log planned updates without writing to DNS",
+	"github.com/aaronlmathis/dynago/internal/config.Config.ForceUpdate":                          "Update records without comparing them first (set by --force)",
+	"github.com/aaronlmathis/dynago/internal/config.Config.HealthAddr":                           "HealthAddr is an alias for probes.addr. When only health_addr is set, LoadConfig copies it to\nProbes.Addr, which also enables the probes unless probes.enabled is false.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.HistoryDB":                            "HistoryDB is a SQLite database every applied IP change is recorded in, for `dynago history` (empty disables).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.IPSourceV6":                           "Source of the public IPv6 address for AAAA records",
+	"github.com/aaronlmathis/dynago/internal/config.Config.IPSources":                            "Fallback IP sources tried in order after IPSource",
+	"github.com/aaronlmathis/dynago/internal/config.Config.LogCompressBackups":                   "Gzip rotated files",
+	"github.com/aaronlmathis/dynago/internal/config.Config.LogFormat":                            "Console log format, \"pretty\" (default) or \"json\"",
+	"github.com/aaronlmathis/dynago/internal/config.Config.LogMaxBackups":                        "Rotated files to keep (all if 0)",
+	"github.com/aaronlmathis/dynago/internal/config.Config.LogMaxSizeMB":                         "LogMaxSizeMB, LogMaxBackups, and LogCompressBackups rotate the -log file; all zero disables rotation.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.LogTarget":                            "\"console\" (default), \"syslog\" (console and syslog), or \"syslog_only\"",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Metrics":                              "Metrics configures the Prometheus metrics endpoint (disabled unless prometheus_addr is set).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.MetricsAddr":                          "MetricsAddr is an alias for metrics.prometheus_addr. When only metrics_addr is set, LoadConfig\ncopies it to Metrics.PrometheusAddr.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Once":                                 "Run a single update cycle and exit (set by --once)",
+	"github.com/aaronlmathis/dynago/internal/config.Config.OnlyProviders":                        "OnlyProviders limits updates to these provider names (set by --provider; empty runs all).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Otel":                                 "Otel configures OpenTelemetry tracing and metrics export (requires a build with the otel tag).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Path":                                 "File the config was loaded from (empty if not loaded from a file)",
+	"github.com/aaronlmathis/dynago/internal/config.Config.PreUpdateHook":                        "PreUpdateHook and PostUpdateHook are shell command lines run around each DNS update.\n{provider}, {old_ip}, and {new_ip} are replaced before the command runs.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.Probes":                               "Probes configures the /healthz, /readyz, and /livez endpoints (disabled by default).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.ProviderTimeout":                      "ProviderTimeout bounds each provider's check-and-update within a cycle (default 30s).",
+	"github.com/aaronlmathis/dynago/internal/config.Config.RetryPolicy":                          "Retries for failed provider updates",
+	"github.com/aaronlmathis/dynago/internal/config.Config.RunOnStart":                           "RunOnStart runs each provider's HealthCheck at startup, refusing to start on failure, and runs\nthe first update cycle immediately instead of after one interval.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.StrictPermissions":                    "StrictPermissions makes LoadConfig reject a world-readable config file instead of warning.",
+	"github.com/aaronlmathis/dynago/internal/config.Config.ValidateCredentials":                  "ValidateCredentials runs each provider's SelfTest at startup and refuses to start on failure.",
+	"github.com/aaronlmathis/dynago/internal/config.DataDogConfig":                               "DataDogConfig holds the metrics.datadog section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.DataDogConfig.Addr":                          "Agent address, host:port or unix:///path/to/dsd.socket (default \"127.0.0.1:8125\")",
+	"github.com/aaronlmathis/dynago/internal/config.DataDogConfig.Enabled":                       "Send metrics and events to the DogStatsD agent at Addr",
+	"github.com/aaronlmathis/dynago/internal/config.DataDogConfig.GlobalTags":                    "Tags added to every metric and event, e.g. \"env:home\"",
+	"github.com/aaronlmathis/dynago/internal/config.DebugConfig":                                 "DebugConfig holds the debug section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.DebugConfig.ExpvarAddr":                      "Listen address for /debug/vars, e.g. \"127.0.0.1:6060\" (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.DebugConfig.PprofAddr":                       "Listen address for /debug/pprof/, e.g. \"127.0.0.1:6061\" (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig":                              "InfluxDBConfig holds the metrics.influxdb section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig.Bucket":                       "Bucket the points are written to",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig.FlushInterval":                "How often buffered points are written (default 10s)",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig.Org":                          "Organization owning Bucket",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig.Token":                        "API token with write access to Bucket",
+	"github.com/aaronlmathis/dynago/internal/config.InfluxDBConfig.URL":                          "InfluxDB v2 server URL, e.g. \"http://influxdb:8086\" (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig":                               "MetricsConfig holds the metrics section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.DataDog":                       "Sends tagged metrics and events to a DataDog agent over DogStatsD",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.InfluxDB":                      "Writes the history of DNS checks and updates to InfluxDB v2",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.PrometheusAddr":                "Listen address for /metrics, e.g. \":9090\" (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.Pushgateway":                   "Pushes metrics after each cycle, e.g. for --once runs from cron",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.StatsD":                        "Sends metrics to a StatsD daemon, e.g. for Graphite or InfluxDB",
+	"github.com/aaronlmathis/dynago/internal/config.MetricsConfig.TelegrafAddr":                  "Listen address for /metrics/telegraf; may equal PrometheusAddr (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.OtelConfig":                                  "OtelConfig holds the otel section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.OtelConfig.MetricsEndpoint":                  "OTLP gRPC collector URL for metrics (empty disables OTLP metrics)",
+	"github.com/aaronlmathis/dynago/internal/config.OtelConfig.MetricsInterval":                  "How often metrics are exported (default 60s)",
+	"github.com/aaronlmathis/dynago/internal/config.OtelConfig.ServiceName":                      "service.name of the exported spans and metrics (default \"dynago\")",
+	"github.com/aaronlmathis/dynago/internal/config.OtelConfig.TraceEndpoint":                    "OTLP gRPC collector URL, e.g. \"http://localhost:4317\" (empty disables tracing)",
+	"github.com/aaronlmathis/dynago/internal/config.ProbesConfig":                                "ProbesConfig holds the probes section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.ProbesConfig.Addr":                           "Listen address for the probe endpoints, e.g. \":8080\"",
+	"github.com/aaronlmathis/dynago/internal/config.ProbesConfig.Enabled":                        "Serve the probe endpoints, on DefaultProbesAddr unless Addr is set",
+	"github.com/aaronlmathis/dynago/internal/config.PushgatewayConfig":                           "PushgatewayConfig holds the metrics.pushgateway section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.PushgatewayConfig.DeleteOnShutdown":          "Delete the pushed metrics when the service stops (not with --once)",
+	"github.com/aaronlmathis/dynago/internal/config.PushgatewayConfig.Instance":                  "Instance grouping label (default the host name)",
+	"github.com/aaronlmathis/dynago/internal/config.PushgatewayConfig.Job":                       "Job label of the pushed metrics (default \"dynago\")",
+	"github.com/aaronlmathis/dynago/internal/config.PushgatewayConfig.URL":                       "Pushgateway base URL, e.g. \"http://pushgateway:9091\" (empty disables)",
+	"github.com/aaronlmathis/dynago/internal/config.RetryPolicyConfig":                           "RetryPolicyConfig holds the retry_policy section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.RetryPolicyConfig.BaseDelay":                 "Delay before the first retry",
+	"github.com/aaronlmathis/dynago/internal/config.RetryPolicyConfig.MaxAttempts":               "Total attempts including the first",
+	"github.com/aaronlmathis/dynago/internal/config.RetryPolicyConfig.MaxDelay":                  "Upper bound for any single delay",
+	"github.com/aaronlmathis/dynago/internal/config.StatsDConfig":                                "StatsDConfig holds the metrics.statsd section of the config.",
+	"github.com/aaronlmathis/dynago/internal/config.StatsDConfig.Addr":                           "UDP address of the StatsD daemon (default \"127.0.0.1:8125\")",
+	"github.com/aaronlmathis/dynago/internal/config.StatsDConfig.Enabled":                        "Send metrics to the StatsD daemon at Addr",
+	"github.com/aaronlmathis/dynago/internal/config.StatsDConfig.Prefix":                         "Prefix of every stat name (default \"dynago\")",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig":                       "CloudflareConfig holds Cloudflare-specific configuration.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.APIEmail":              "Alias for email",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.APIKey":                "Legacy Global API Key, used instead of api_token",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.AutoCreate":            "AutoCreate is an alias for CreateIfMissing. When only auto_create is set, it decides;\nwhen neither is set, records are created (the create_if_missing default).",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.CreateIfMissing":       "CreateIfMissing makes UpdateRecordIP create configured records that do not exist yet\n(default true). When false, a missing record is an error.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.DualStack":             "DualStack manages an A and an AAAA record for every configured name, instead of one record\nof record_type. The service keeps them at the public IPv4 and IPv6 address respectively.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.Email":                 "Account email for api_key authentication",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.Proxied":               "Default for records entries that do not set proxied",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.RecordCommentTemplate": "RecordCommentTemplate is a text/template rendered into each updated record's comment\n(default DefaultCommentTemplate). RecordTags are merged into each updated record's tags.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.RecordNames":           "RecordNames lists several records in the top-level zone, kept at the same IP. GetRecordIP\ncompares the first against the current IP.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.Records":               "Multiple zone+record pairs managed by this provider",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.ToggleDevMode":         "ToggleDevMode turns on development mode (cache bypass) for each updated zone, then turns it\noff again after DevModeDuration (default 3m).",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareConfig.ZoneName":              "Looked up to find the zone ID when zone_id is empty",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareRecord":                       "CloudflareRecord identifies a single DNS record managed by the Cloudflare provider.",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareRecord.Proxied":               "Overrides the top-level proxied flag when set",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareRecord.TTL":                   "TTL in seconds; 0 keeps the current TTL, 1 means automatic",
+	"github.com/aaronlmathis/dynago/providers/cloudflare.CloudflareRecord.ZoneName":              "Looked up to find the zone ID when zone_id is empty",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config":                             "Route53Config holds AWS Route53-specific configuration.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.AliasTarget":                 "AliasTarget makes the records ALIAS records pointing at this AWS resource (e.g. an ELB or\nCloudFront DNS name in the hosted zone AliasHostedZoneID) instead of the public IP.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.AssumeRoleARN":               "Role to assume via STS; static credentials are used directly when empty",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.CreateHealthCheck":           "CreateHealthCheck creates a Route53 health check probing each record over\nHealthCheckProtocol (\"HTTP\" or \"HTTPS\", default \"HTTP\") at HealthCheckPath, and associates\nit with the record. Requires a routing_policy.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.CredentialsExpiry":           "CredentialsExpiry is when the temporary credentials given by SessionToken expire. The service\nwarns at startup if they expire before the next update.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.ExternalID":                  "Optional external ID passed when assuming the role",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.GeoContinentCode":            "e.g. \"EU\"",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.GeoCountryCode":              "e.g. \"DE\", or \"*\" for the default location",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.HealthCheckStateFile":        "HealthCheckStateFile is a JSON file the created health check IDs are saved to, so they are\nreused after a restart. Without it they are only kept in memory.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.RecordNames":                 "Several records in the hosted zone, updated in one change batch",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.RoleSessionName":             "Session name used when assuming the role (default \"dynago\")",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.RoutingPolicy":               "RoutingPolicy \"weighted\" manages only the record set with SetIdentifier among records sharing\nthe name, giving it Weight. \"geolocation\" manages only the record set for GeoContinentCode or\nGeoCountryCode, created with SetIdentifier. Empty manages a simple record.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.SessionToken":                "Set with temporary access keys, e.g. from sts get-session-token",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.SetIdentifier":               "Required with either routing policy",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.TTL":                         "TTL in seconds set by UpdateRecordIP (default 300)",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.UseInstanceProfile":          "UseInstanceProfile takes credentials from the EC2 instance profile via IMDSv2 instead of\naccess_key_id and secret_access_key, e.g. when running in the VPC of a private zone.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.WaitForInSync":               "WaitForInSync and InSyncTimeout are aliases for WaitForPropagation and PropagationTimeout.",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.WaitForPropagation":          "WaitForPropagation makes UpdateRecordIP poll the change until Route53 reports it INSYNC,\ngiving up after PropagationTimeout (default 60s).",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.Weight":                      "Required with weighted routing; 0 sends no traffic",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.ZoneName":                    "Looked up to find the hosted zone ID when hosted_zone_id is empty",
+	"github.com/aaronlmathis/dynago/providers/route53.Route53Config.ZonePrivate":                 "Look up the private zone named zone_name instead of the public one",
+}
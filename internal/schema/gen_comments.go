@@ -0,0 +1,71 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build ignore
+
+// gen_comments writes comments_gen.go, the field comments used for schema descriptions.
+//
+// Run it with go generate after changing the comments of a config struct.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/aaronlmathis/dynago/internal/schema"
+)
+
+func main() {
+	out, err := os.Getwd()
+	if err != nil {
+		log.Fatal(err)
+	}
+	// ExtractComments reads the sources relative to the module root.
+	if err := os.Chdir("../.."); err != nil {
+		log.Fatal(err)
+	}
+	comments, err := schema.ExtractComments()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by gen_comments.go; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "package schema")
+	fmt.Fprintln(&buf)
+	fmt.Fprintln(&buf, "// fieldComments holds the doc comments of the config types, keyed by type and field name.")
+	fmt.Fprintln(&buf, "var fieldComments = map[string]string{")
+	for _, key := range slices.Sorted(maps.Keys(comments)) {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", key, comments[key])
+	}
+	fmt.Fprintln(&buf, "}")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out+"/comments_gen.go", src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
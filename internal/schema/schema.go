@@ -0,0 +1,154 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package schema generates a JSON Schema for the dynago config file, so editors can offer
+// completion and validation while it is edited.
+package schema
+
+//go:generate go run gen_comments.go
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	cfprovider "github.com/aaronlmathis/dynago/providers/cloudflare"
+	r53provider "github.com/aaronlmathis/dynago/providers/route53"
+	"github.com/invopop/jsonschema"
+)
+
+// Draft7 is the JSON Schema version of the generated document.
+const Draft7 = "http://json-schema.org/draft-07/schema#"
+
+// providerConfigs maps each built-in provider's section name under providers to its config type.
+var providerConfigs = map[string]reflect.Type{
+	"cloudflare": reflect.TypeOf(cfprovider.CloudflareConfig{}),
+	"route53":    reflect.TypeOf(r53provider.Route53Config{}),
+}
+
+// schemaTypes lists every struct type that appears in the schema, for ExtractComments.
+var schemaTypes = []reflect.Type{
+	reflect.TypeOf(config.Config{}),
+	reflect.TypeOf(config.RetryPolicyConfig{}),
+	reflect.TypeOf(config.MetricsConfig{}),
+	reflect.TypeOf(config.PushgatewayConfig{}),
+	reflect.TypeOf(config.StatsDConfig{}),
+	reflect.TypeOf(config.DataDogConfig{}),
+	reflect.TypeOf(config.InfluxDBConfig{}),
+	reflect.TypeOf(config.ProbesConfig{}),
+	reflect.TypeOf(config.OtelConfig{}),
+	reflect.TypeOf(config.DebugConfig{}),
+	reflect.TypeOf(cfprovider.CloudflareConfig{}),
+	reflect.TypeOf(cfprovider.CloudflareRecord{}),
+	reflect.TypeOf(r53provider.Route53Config{}),
+}
+
+// Generate returns a JSON Schema (draft 7) document describing the config file: config.Config
+// with the providers section replaced by the config of each built-in provider.
+//
+// Properties use the YAML key names, descriptions come from the Go field comments (see
+// fieldComments), and durations are strings such as "5m". Unknown keys are rejected so that
+// editors flag typos, which dynago itself silently ignores.
+func Generate() ([]byte, error) {
+	r := newReflector()
+	root := r.Reflect(&config.Config{})
+	root.Version = Draft7
+	root.Title = "dynago configuration"
+	// ValidateConfig rejects a config without these; provider settings have alternatives
+	// (e.g. zone_id or zone_name), so none of them is required on its own.
+	root.Required = []string{"interval", "ip_source", "providers"}
+
+	providers := &jsonschema.Schema{
+		Type:                 "object",
+		Description:          "Provider name to provider settings. Only built-in providers are recognized.",
+		Properties:           jsonschema.NewProperties(),
+		AdditionalProperties: jsonschema.FalseSchema,
+	}
+	for _, name := range slices.Sorted(maps.Keys(providerConfigs)) {
+		s := r.ReflectFromType(providerConfigs[name])
+		s.Version = ""
+		providers.Properties.Set(name, s)
+	}
+	root.Properties.Set("providers", providers)
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// newReflector returns a reflector that reads YAML field names, leaves fields optional unless
+// tagged jsonschema:"required", and inlines nested types instead of using definitions.
+func newReflector() *jsonschema.Reflector {
+	return &jsonschema.Reflector{
+		Anonymous:                  true,
+		DoNotReference:             true,
+		RequiredFromJSONSchemaTags: true,
+		FieldNameTag:               "yaml",
+		CommentMap:                 fieldComments,
+		Mapper:                     mapType,
+	}
+}
+
+// mapType describes time.Duration as a Go duration string, which is how the YAML is parsed.
+func mapType(t reflect.Type) *jsonschema.Schema {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return &jsonschema.Schema{
+			Type:     "string",
+			Pattern:  `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`,
+			Examples: []any{"30s", "5m", "1h"},
+		}
+	}
+	return nil
+}
+
+// ExtractComments parses the Go sources of the types in the schema and returns their doc and
+// field comments, keyed as jsonschema.Reflector.CommentMap expects. It is used by go generate to
+// write fieldComments and must be called from the module root.
+func ExtractComments() (map[string]string, error) {
+	r := new(jsonschema.Reflector)
+	module := modulePath()
+	dirs := make(map[string]bool)
+	for _, t := range schemaTypes {
+		dir := strings.TrimPrefix(t.PkgPath(), module+"/")
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := r.AddGoComments(module, dir); err != nil {
+			return nil, err
+		}
+	}
+	comments := make(map[string]string)
+	for key, text := range r.CommentMap {
+		for _, t := range schemaTypes {
+			name := t.PkgPath() + "." + t.Name()
+			if key == name || strings.HasPrefix(key, name+".") {
+				comments[key] = text
+				break
+			}
+		}
+	}
+	return comments, nil
+}
+
+// modulePath returns the dynago module path, derived from the config package's import path.
+func modulePath() string {
+	return strings.TrimSuffix(reflect.TypeOf(config.Config{}).PkgPath(), "/internal/config")
+}
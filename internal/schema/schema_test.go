@@ -0,0 +1,88 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	out, err := Generate()
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if doc["$schema"] != Draft7 {
+		t.Errorf("$schema = %v, want %s", doc["$schema"], Draft7)
+	}
+	if doc["type"] != "object" {
+		t.Errorf("type = %v, want object", doc["type"])
+	}
+	required, _ := doc["required"].([]any)
+	for _, want := range []string{"interval", "ip_source", "providers"} {
+		if !slices.Contains(required, any(want)) {
+			t.Errorf("expected %q to be required, got %v", want, required)
+		}
+	}
+
+	props := doc["properties"].(map[string]any)
+	if _, ok := props["path"]; ok {
+		t.Error("fields tagged yaml:\"-\" must not appear in the schema")
+	}
+	interval := props["interval"].(map[string]any)
+	if interval["type"] != "string" {
+		t.Errorf("interval type = %v, want a duration string", interval["type"])
+	}
+	debounce := props["debounce_count"].(map[string]any)
+	if debounce["description"] == "" || debounce["description"] == nil {
+		t.Error("expected debounce_count to have a description from its field comment")
+	}
+
+	providers := props["providers"].(map[string]any)["properties"].(map[string]any)
+	for _, name := range []string{"cloudflare", "route53"} {
+		p, ok := providers[name].(map[string]any)
+		if !ok {
+			t.Fatalf("expected a schema for providers.%s", name)
+		}
+		if _, ok := p["$schema"]; ok {
+			t.Errorf("providers.%s must not redeclare $schema", name)
+		}
+		if _, ok := p["properties"].(map[string]any)["record_name"]; !ok {
+			t.Errorf("expected providers.%s to describe record_name", name)
+		}
+	}
+}
+
+// TestFieldCommentsUpToDate fails when a config struct's comments changed without rerunning go generate.
+func TestFieldCommentsUpToDate(t *testing.T) {
+	t.Chdir("../..")
+	comments, err := ExtractComments()
+	if err != nil {
+		t.Fatalf("ExtractComments: %v", err)
+	}
+	if !maps.Equal(comments, fieldComments) {
+		t.Error("comments_gen.go is out of date; run go generate ./internal/schema")
+	}
+}
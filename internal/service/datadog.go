@@ -0,0 +1,88 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// datadogNamespace prefixes every metric name and event title sent to DataDog.
+const datadogNamespace = "dynago."
+
+// dogStatsD is the subset of *statsd.Client used by datadogEmitter.
+type dogStatsD interface {
+	Count(name string, value int64, tags []string, rate float64) error
+	Timing(name string, value time.Duration, tags []string, rate float64) error
+	Gauge(name string, value float64, tags []string, rate float64) error
+	Event(e *statsd.Event) error
+}
+
+// datadogEmitter sends stats to a DataDog agent over DogStatsD. Unlike plain StatsD, the provider
+// is a provider:<name> tag rather than part of the metric name, so dashboards can filter on it.
+type datadogEmitter struct {
+	client dogStatsD
+}
+
+// Record sends stat unsampled; events are sent as DataDog events titled dynago.<name>.
+func (e datadogEmitter) Record(stat Stat) error {
+	var tags []string
+	if stat.Provider != "" {
+		tags = []string{"provider:" + stat.Provider}
+	}
+	switch stat.Kind {
+	case StatCounter:
+		return e.client.Count(stat.Name, stat.Value, tags, 1)
+	case StatTimer:
+		return e.client.Timing(stat.Name, stat.Duration, tags, 1)
+	case StatGauge:
+		return e.client.Gauge(stat.Name, float64(stat.Value), tags, 1)
+	case StatEvent:
+		return e.client.Event(&statsd.Event{Title: datadogNamespace + stat.Name, Text: stat.Text, Tags: tags})
+	}
+	return nil
+}
+
+// newDataDogEmitter creates a DogStatsD client for cfg, defaulting the address to
+// config.DefaultDataDogAddr. The global tags are added to every metric and event.
+//
+// Returns the emitter and a function closing the client, which flushes any buffered stats.
+func newDataDogEmitter(cfg config.DataDogConfig) (Emitter, func(), error) {
+	addr := cfg.Addr
+	if addr == "" {
+		addr = config.DefaultDataDogAddr
+	}
+	client, err := statsd.New(addr,
+		statsd.WithNamespace(datadogNamespace),
+		statsd.WithTags(cfg.GlobalTags),
+		statsd.WithoutTelemetry(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Info("Sending DogStatsD metrics and events to %s", addr)
+	return datadogEmitter{client: client}, func() {
+		if err := client.Close(); err != nil {
+			logger.Warn("Failed to close DogStatsD client: %v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,51 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+func TestNewDataDogEmitter(t *testing.T) {
+	conn := listenUDP(t)
+	emitter, closeClient, err := newDataDogEmitter(config.DataDogConfig{
+		Enabled:    true,
+		Addr:       conn.LocalAddr().String(),
+		GlobalTags: []string{"env:home"},
+	})
+	if err != nil {
+		t.Fatalf("newDataDogEmitter: %v", err)
+	}
+	emitter.Record(Stat{Kind: StatCounter, Name: "update.success", Provider: "cloudflare", Value: 1})
+	emitter.Record(Stat{Kind: StatTimer, Name: "ip_fetch.duration_ms", Duration: 250 * time.Millisecond})
+	emitter.Record(Stat{Kind: StatGauge, Name: "circuit_open", Provider: "route53", Value: 1})
+	emitter.Record(Stat{Kind: StatEvent, Name: "ip.changed", Provider: "cloudflare",
+		Text: "cloudflare: DNS record updated from 1.2.3.4 to 5.6.7.8"})
+	closeClient() // Flushes the buffered stats
+
+	expectPackets(t, conn,
+		"_e{17,54}:dynago.ip.changed|cloudflare: DNS record updated from 1.2.3.4 to 5.6.7.8|#env:home,provider:cloudflare",
+		"dynago.update.success:1|c|#env:home,provider:cloudflare",
+		"dynago.ip_fetch.duration_ms:250.000000|ms|#env:home",
+		"dynago.circuit_open:1|g|#env:home,provider:route53",
+	)
+}
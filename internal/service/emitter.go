@@ -0,0 +1,71 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// StatKind is the type of a Stat.
+type StatKind int
+
+const (
+	StatCounter StatKind = iota // Value is added to a counter
+	StatTimer                   // Duration is recorded, in milliseconds
+	StatGauge                   // The gauge is set to Value
+	StatEvent                   // Text describes something that happened; Value is unused
+)
+
+// Stat is one measurement or event sent through an Emitter.
+type Stat struct {
+	Kind     StatKind
+	Name     string        // Relative to the emitter's prefix, e.g. "update.success"
+	Provider string        // Provider the stat is about, or "" for service-wide stats
+	Value    int64         // Counter increment or gauge value
+	Duration time.Duration // Timer value
+	Text     string        // Event description
+}
+
+// Emitter sends stats to a StatsD-style backend: plain StatsD when metrics.statsd is enabled, and
+// DogStatsD when metrics.datadog is enabled. Each decides how to name or tag the provider, and may
+// ignore kinds it cannot represent.
+//
+// Tests and embedders can add their own with WithEmitter.
+type Emitter interface {
+	Record(stat Stat) error
+}
+
+// WithEmitter makes the service send its stats to e, in addition to any emitters the config enables.
+func WithEmitter(e Emitter) Option {
+	return func(s *DNSUpdateService) {
+		s.metrics.emitters = append(s.metrics.emitters, e)
+	}
+}
+
+// emitStat sends stat to every emitter, logging failures at debug level: the backends are
+// fire-and-forget, so an unreachable daemon must not disturb the update cycle.
+func (m *metrics) emitStat(stat Stat) {
+	for _, e := range m.emitters {
+		if err := e.Record(stat); err != nil {
+			logger.Debug("Failed to send %s stat: %v", stat.Name, err)
+		}
+	}
+}
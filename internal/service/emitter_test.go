@@ -0,0 +1,116 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// mockEmitter records the stats sent to it.
+type mockEmitter struct {
+	mu    sync.Mutex
+	stats []Stat
+}
+
+func (m *mockEmitter) Record(stat Stat) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats = append(m.stats, stat)
+	return nil
+}
+
+// find returns the stats recorded with the given kind, name, and provider.
+func (m *mockEmitter) find(kind StatKind, name, provider string) []Stat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found []Stat
+	for _, stat := range m.stats {
+		if stat.Kind == kind && stat.Name == name && stat.Provider == provider {
+			found = append(found, stat)
+		}
+	}
+	return found
+}
+
+func TestEmitter_CycleStats(t *testing.T) {
+	emitter := &mockEmitter{}
+	cfg := &config.Config{IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg, WithEmitter(emitter),
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+
+	ok := &mockProvider{name: "ok", getIP: "5.6.7.8"}
+	changed := &mockProvider{name: "changed", getIP: "1.1.1.1"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	tripped := &mockProvider{name: "tripped", getIP: "5.6.7.8"}
+	reg := newTestRegistry(t, ok, changed, failing, tripped)
+	for range 5 {
+		reg.Breaker(tripped).RecordFailure()
+	}
+	service.checkAndUpdate(reg)
+
+	for _, c := range []struct{ name, provider string }{
+		{"update.success", "ok"},
+		{"update.success", "changed"},
+		{"update.failure", "failing"},
+	} {
+		if got := emitter.find(StatCounter, c.name, c.provider); len(got) != 1 || got[0].Value != 1 {
+			t.Errorf("counter %s{%s} = %+v, want one increment", c.name, c.provider, got)
+		}
+	}
+	if got := emitter.find(StatCounter, "update.success", "tripped"); len(got) != 0 {
+		t.Errorf("expected no counter for a provider skipped by its circuit breaker, got %+v", got)
+	}
+	if got := emitter.find(StatTimer, "ip_fetch.duration_ms", ""); len(got) != 1 {
+		t.Errorf("ip_fetch.duration_ms recorded %d times, want 1", len(got))
+	}
+	for _, provider := range []string{"ok", "changed", "failing"} {
+		if got := emitter.find(StatTimer, "update.duration_ms", provider); len(got) != 1 {
+			t.Errorf("update.duration_ms{%s} recorded %d times, want 1", provider, len(got))
+		}
+	}
+	for provider, want := range map[string]int64{"ok": 0, "failing": 0, "tripped": 1} {
+		if got := emitter.find(StatGauge, "circuit_open", provider); len(got) != 1 || got[0].Value != want {
+			t.Errorf("circuit_open{%s} = %+v, want %d", provider, got, want)
+		}
+	}
+
+	events := emitter.find(StatEvent, "ip.changed", "changed")
+	if len(events) != 1 || events[0].Text != "changed: DNS record updated from 1.1.1.1 to 5.6.7.8" {
+		t.Errorf("ip.changed events = %+v, want one for the changed provider", events)
+	}
+	if got := emitter.find(StatEvent, "ip.changed", "ok"); len(got) != 0 {
+		t.Errorf("expected no ip.changed event for an unchanged record, got %+v", got)
+	}
+}
+
+func TestEmitter_NoEventInDryRun(t *testing.T) {
+	emitter := &mockEmitter{}
+	cfg := &config.Config{IPSource: "mock", DryRun: true}
+	service := NewDNSUpdateService(context.Background(), cfg, WithEmitter(emitter))
+
+	service.runCycle(context.Background(), newTestRegistry(t, &mockProvider{name: "mock", getIP: "1.1.1.1"}), "5.6.7.8", "")
+	if got := emitter.find(StatEvent, "ip.changed", "mock"); len(got) != 0 {
+		t.Errorf("expected no ip.changed event in dry-run mode, got %+v", got)
+	}
+}
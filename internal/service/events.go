@@ -0,0 +1,77 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"strings"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// EventBufferSize is the recommended capacity for a channel passed to WithEvents.
+const EventBufferSize = 100
+
+// ProviderEvent describes the outcome of a single DNS record update attempt.
+type ProviderEvent struct {
+	Time     time.Time // When the attempt finished
+	Provider string    // Provider name (e.g., "cloudflare")
+	Record   string    // DNS record name, if known
+	OldIP    string    // IP held by the record before the attempt
+	NewIP    string    // IP the record was updated to
+	Error    error     // Non-nil if the update failed
+}
+
+// WithEvents makes the service publish a ProviderEvent on ch after each update attempt.
+//
+// Sends never block: if ch is full the event is dropped, so callers must drain the channel
+// continuously. A buffered channel of EventBufferSize is recommended.
+func WithEvents(ch chan ProviderEvent) Option {
+	return func(s *DNSUpdateService) {
+		s.Events = ch
+	}
+}
+
+// emit publishes an update event without blocking. It is a no-op if no channel is configured.
+func (s *DNSUpdateService) emit(p providers.DNSProvider, oldIP, newIP string, err error) {
+	if s.Events == nil {
+		return
+	}
+	event := ProviderEvent{
+		Time:     time.Now(),
+		Provider: p.ProviderName(),
+		Record:   recordName(p),
+		OldIP:    oldIP,
+		NewIP:    newIP,
+		Error:    err,
+	}
+	select {
+	case s.Events <- event:
+	default:
+	}
+}
+
+// recordName returns the DNS record(s) managed by p, joined with commas, or "" if p does not
+// implement providers.RecordNamer.
+func recordName(p providers.DNSProvider) string {
+	if namer, ok := p.(providers.RecordNamer); ok {
+		return strings.Join(namer.RecordNames(), ",")
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+func TestDNSUpdateService_EmitsEvents(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	events := make(chan ProviderEvent, EventBufferSize)
+	service := NewDNSUpdateService(context.Background(), cfg, WithEvents(events))
+	ok := &mockProvider{name: "ok", getIP: "1.2.3.4"}
+	failing := &mockProvider{name: "failing", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+
+	before := time.Now()
+	service.runCycle(context.Background(), newTestRegistry(t, ok, failing), "5.6.7.8", "")
+
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	got := map[string]ProviderEvent{}
+	for i := 0; i < 2; i++ {
+		ev := <-events
+		got[ev.Provider] = ev
+	}
+	okEv := got["ok"]
+	if okEv.OldIP != "1.2.3.4" || okEv.NewIP != "5.6.7.8" || okEv.Error != nil || okEv.Time.Before(before) {
+		t.Errorf("unexpected success event: %+v", okEv)
+	}
+	failEv := got["failing"]
+	if failEv.OldIP != "1.2.3.4" || failEv.NewIP != "5.6.7.8" || failEv.Error == nil {
+		t.Errorf("unexpected failure event: %+v", failEv)
+	}
+}
+
+func TestDNSUpdateService_EventsDoNotBlock(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	events := make(chan ProviderEvent) // Unbuffered and never drained
+	service := NewDNSUpdateService(context.Background(), cfg, WithEvents(events))
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	done := make(chan struct{})
+	go func() {
+		service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runCycle blocked on an undrained event channel")
+	}
+}
+
+func TestDNSUpdateService_NoEventsUnchangedOrDryRun(t *testing.T) {
+	events := make(chan ProviderEvent, EventBufferSize)
+	unchanged := &mockProvider{name: "unchanged", getIP: "5.6.7.8"}
+	NewDNSUpdateService(context.Background(), &config.Config{}, WithEvents(events)).
+		runCycle(context.Background(), newTestRegistry(t, unchanged), "5.6.7.8", "")
+	dry := &mockProvider{name: "dry", getIP: "1.2.3.4"}
+	NewDNSUpdateService(context.Background(), &config.Config{DryRun: true}, WithEvents(events)).
+		runCycle(context.Background(), newTestRegistry(t, dry), "5.6.7.8", "")
+
+	if len(events) != 0 {
+		t.Errorf("expected no events, got %d", len(events))
+	}
+}
+
+// namedProvider is a mockProvider that names its records.
+type namedProvider struct {
+	mockProvider
+	names []string
+}
+
+func (p *namedProvider) RecordNames() []string { return p.names }
+
+func TestRecordName(t *testing.T) {
+	named := &namedProvider{mockProvider: mockProvider{name: "named"}, names: []string{"a.example.com", "b.example.com"}}
+	if got := recordName(named); got != "a.example.com,b.example.com" {
+		t.Errorf("recordName() = %q, want the names joined with commas", got)
+	}
+	if got := recordName(&mockProvider{name: "unnamed"}); got != "" {
+		t.Errorf("recordName() = %q for a provider without RecordNames, want empty", got)
+	}
+}
@@ -0,0 +1,66 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"expvar"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// Runtime counters published through expvar and served on /debug/vars while debug.expvar_addr is
+// set. expvar variables are process-wide, so every service in the process shares them.
+var (
+	expvarUpdates    = expvar.NewMap("dynago.update.total")     // Checks and updates per provider, failed or not
+	expvarErrors     = expvar.NewMap("dynago.error.total")      // Failed checks and updates per provider
+	expvarLastIP     = expvar.NewMap("dynago.last_ip")          // IP each provider's records were last seen or set to
+	expvarLastUpdate = expvar.NewMap("dynago.last_update_unix") // Unix time of each provider's last success
+)
+
+// observeExpvarUpdate records the outcome of one provider's check or update in the expvar counters.
+func observeExpvarUpdate(providerName string, success bool) {
+	expvarUpdates.Add(providerName, 1)
+	if !success {
+		expvarErrors.Add(providerName, 1)
+		return
+	}
+	last := new(expvar.Int)
+	last.Set(time.Now().Unix())
+	expvarLastUpdate.Set(providerName, last)
+}
+
+// observeExpvarIP records that providerName's records hold ip.
+func observeExpvarIP(providerName, ip string) {
+	v := new(expvar.String)
+	v.Set(ip)
+	expvarLastIP.Set(providerName, v)
+}
+
+// serveExpvar serves expvar's /debug/vars on ln until the service context is cancelled or stop is
+// called (see serveHTTP). It has a mux of its own rather than http.DefaultServeMux, which importing
+// net/http/pprof fills with the profiling handlers (see serveProfiling).
+func (s *DNSUpdateService) serveExpvar(ln net.Listener) (stop func()) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	logger.Info("Serving expvar counters on http://%s/debug/vars", ln.Addr())
+	return s.serveHTTP("Expvar", ln, mux)
+}
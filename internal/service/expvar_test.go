@@ -0,0 +1,116 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// expvarInt returns the value of key in m, or 0 if it is unset.
+func expvarInt(m *expvar.Map, key string) int64 {
+	if v, ok := m.Get(key).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestDNSUpdateService_ExpvarCounters(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	// expvar is process-wide, so the providers have names no other test uses.
+	changed := &mockProvider{name: "expvar-changed", getIP: "1.2.3.4"}
+	unchanged := &mockProvider{name: "expvar-unchanged", getIP: "5.6.7.8"}
+	failing := &mockProvider{name: "expvar-failing", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+	reg := newTestRegistry(t, changed, unchanged, failing)
+
+	counts := func(name string) [2]int64 {
+		return [2]int64{expvarInt(expvarUpdates, name), expvarInt(expvarErrors, name)}
+	}
+	initial := map[string][2]int64{}
+	for _, p := range reg.Providers {
+		initial[p.ProviderName()] = counts(p.ProviderName())
+	}
+
+	before := time.Now().Unix()
+	service.runCycle(context.Background(), reg, "5.6.7.8", "")
+	service.runCycle(context.Background(), reg, "5.6.7.8", "")
+
+	for name, want := range map[string][2]int64{
+		"expvar-changed":   {2, 0},
+		"expvar-unchanged": {2, 0},
+		"expvar-failing":   {2, 2},
+	} {
+		now := counts(name)
+		if got := [2]int64{now[0] - initial[name][0], now[1] - initial[name][1]}; got != want {
+			t.Errorf("%s: expected dynago.update.total and dynago.error.total to grow by %v, got %v", name, want, got)
+		}
+	}
+	if got := expvarInt(expvarLastUpdate, "expvar-changed"); got < before {
+		t.Errorf("expected dynago.last_update_unix to be at least %d, got %d", before, got)
+	}
+	if expvarLastUpdate.Get("expvar-failing") != nil || expvarLastIP.Get("expvar-failing") != nil {
+		t.Error("expected no last update or IP for a provider that never succeeded")
+	}
+	for _, name := range []string{"expvar-changed", "expvar-unchanged"} {
+		if v, ok := expvarLastIP.Get(name).(*expvar.String); !ok || v.Value() != "5.6.7.8" {
+			t.Errorf("%s: expected dynago.last_ip 5.6.7.8, got %v", name, expvarLastIP.Get(name))
+		}
+	}
+}
+
+func TestDNSUpdateService_ServeExpvar(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewDNSUpdateService(ctx, &config.Config{Interval: time.Minute, IPSource: "mock"})
+	observeExpvarUpdate("expvar-served", true)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer service.serveExpvar(ln)()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/debug/vars")
+	if err != nil {
+		t.Fatalf("GET /debug/vars: %v", err)
+	}
+	defer resp.Body.Close()
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		t.Fatalf("decoding /debug/vars: %v", err)
+	}
+	for _, name := range []string{"dynago.update.total", "dynago.error.total", "dynago.last_ip", "dynago.last_update_unix", "memstats"} {
+		if _, ok := vars[name]; !ok {
+			t.Errorf("expected %s in /debug/vars", name)
+		}
+	}
+	var updates map[string]int64
+	if err := json.Unmarshal(vars["dynago.update.total"], &updates); err != nil || updates["expvar-served"] == 0 {
+		t.Errorf("expected expvar-served in dynago.update.total, got %s (%v)", vars["dynago.update.total"], err)
+	}
+}
@@ -0,0 +1,115 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// HookTimeout bounds each pre_update_hook and post_update_hook command.
+const HookTimeout = 30 * time.Second
+
+// expandHook substitutes the {provider}, {old_ip}, and {new_ip} placeholders in a hook command line.
+//
+// Each value is quoted for the shell (see quoteHookArg), so a value cannot inject commands.
+func expandHook(command, providerName, oldIP, newIP string) string {
+	return strings.NewReplacer(
+		"{provider}", quoteHookArg(providerName),
+		"{old_ip}", quoteHookArg(oldIP),
+		"{new_ip}", quoteHookArg(newIP),
+	).Replace(command)
+}
+
+// quoteHookArg quotes s for sh, or for cmd on Windows, unless it only contains characters that are
+// safe unquoted, as IP addresses and provider names do. An empty value stays empty.
+func quoteHookArg(s string) string {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("./:_-", r))
+	}) < 0 {
+		return s
+	}
+	if runtime.GOOS == "windows" {
+		// cmd has no escape inside double quotes, so drop the characters it would interpret.
+		return `"` + strings.NewReplacer(`"`, "", "%", "", "^", "", "!", "").Replace(s) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// hookEnv returns the environment of a hook: dynago's own, plus DYNAGO_PROVIDER, DYNAGO_OLD_IP,
+// and DYNAGO_NEW_IP.
+func hookEnv(providerName, oldIP, newIP string) []string {
+	return append(os.Environ(),
+		"DYNAGO_PROVIDER="+providerName,
+		"DYNAGO_OLD_IP="+oldIP,
+		"DYNAGO_NEW_IP="+newIP,
+	)
+}
+
+// runHook runs a hook command line through the system shell, bounded by HookTimeout.
+// The provider and addresses are also passed in the environment (see hookEnv).
+//
+// Output is logged at debug level. Failures are logged as warnings and never returned,
+// so a broken hook cannot block a DNS update. ctx only supplies the cycle's correlation ID for
+// logging; the hook is bounded by the service context, not by the provider's timeout.
+func (s *DNSUpdateService) runHook(ctx context.Context, name, command, providerName, oldIP, newIP string) {
+	if command == "" {
+		return
+	}
+	line := expandHook(command, providerName, oldIP, newIP)
+	log := providerLog(ctx, providerName)
+	if s.cfg.DryRun {
+		log.Info().Msgf("[dry-run] %s: would run %s: %s", providerName, name, line)
+		return
+	}
+	hookCtx, cancel := context.WithTimeout(s.ctx, HookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(hookCtx, "cmd", "/C", line)
+	} else {
+		cmd = exec.CommandContext(hookCtx, "sh", "-c", line)
+	}
+	cmd.Env = hookEnv(providerName, oldIP, newIP)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	log.Debug().Msgf("%s: running %s: %s", providerName, name, line)
+	err := cmd.Run()
+	if out := strings.TrimSpace(stdout.String()); out != "" {
+		log.Debug().Msgf("%s: %s stdout: %s", providerName, name, out)
+	}
+	if out := strings.TrimSpace(stderr.String()); out != "" {
+		log.Debug().Msgf("%s: %s stderr: %s", providerName, name, out)
+	}
+	if hookCtx.Err() == context.DeadlineExceeded {
+		log.Warn().Msgf("%s: %s timed out after %s", providerName, name, HookTimeout)
+		return
+	}
+	if err != nil {
+		log.Warn().Msgf("%s: %s failed: %v", providerName, name, err)
+	}
+}
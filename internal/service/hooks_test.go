@@ -0,0 +1,139 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+func TestExpandHook(t *testing.T) {
+	got := expandHook("notify {provider} {old_ip} -> {new_ip}", "cloudflare/AAAA", "1.2.3.4", "2001:db8::1")
+	if want := "notify cloudflare/AAAA 1.2.3.4 -> 2001:db8::1"; got != want {
+		t.Errorf("expandHook() = %q, want %q", got, want)
+	}
+}
+
+// TestDNSUpdateService_HookCannotBeInjected checks that hostile values reach a hook as plain data,
+// both substituted into the command line and in the environment.
+func TestDNSUpdateService_HookCannotBeInjected(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses sh syntax")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	pwned := filepath.Join(dir, "pwned")
+	hostile := "1.2.3.4'; touch " + pwned + "; echo '$(touch " + pwned + ")"
+	cfg := &config.Config{
+		Interval:       time.Minute,
+		IPSource:       "mock",
+		PostUpdateHook: `printf '%s|%s|%s' {old_ip} "$DYNAGO_OLD_IP" "$DYNAGO_NEW_IP" > ` + out,
+	}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: hostile}
+
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	if _, err := os.Stat(pwned); err == nil {
+		t.Fatal("hostile old IP executed a command")
+	}
+	if got, want := readHookOutput(t, out), hostile+"|"+hostile+"|5.6.7.8"; got != want {
+		t.Errorf("hook output = %q, want %q", got, want)
+	}
+}
+
+// TestDNSUpdateService_RejectsHostileIPSource checks that an IP source returning shell syntax fails
+// the cycle before any record is updated or hook is run.
+func TestDNSUpdateService_RejectsHostileIPSource(t *testing.T) {
+	dir := t.TempDir()
+	pwned := filepath.Join(dir, "pwned")
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", PreUpdateHook: "echo {new_ip}"}
+	service := NewDNSUpdateService(context.Background(), cfg, WithIPSourceFunc(func([]string) (string, error) {
+		return "5.6.7.8; touch " + pwned, nil
+	}))
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	if err := service.checkAndUpdate(newTestRegistry(t, mockProv)); err == nil {
+		t.Fatal("expected an error for a non-IP public address")
+	}
+	if mockProv.updateCalls != 0 {
+		t.Errorf("expected no update, got %d", mockProv.updateCalls)
+	}
+	if _, err := os.Stat(pwned); err == nil {
+		t.Error("hostile IP source executed a command")
+	}
+}
+
+func TestDNSUpdateService_RunsHooksAroundUpdate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses sh syntax")
+	}
+	dir := t.TempDir()
+	pre := filepath.Join(dir, "pre")
+	post := filepath.Join(dir, "post")
+	cfg := &config.Config{
+		Interval:       time.Minute,
+		IPSource:       "mock",
+		PreUpdateHook:  "echo {provider} {old_ip} {new_ip} > " + pre,
+		PostUpdateHook: "echo done {new_ip} > " + post,
+	}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	if got := readHookOutput(t, pre); got != "mock 1.2.3.4 5.6.7.8" {
+		t.Errorf("unexpected pre-update hook output %q", got)
+	}
+	if got := readHookOutput(t, post); got != "done 5.6.7.8" {
+		t.Errorf("unexpected post-update hook output %q", got)
+	}
+}
+
+func TestDNSUpdateService_FailingHookDoesNotAbortUpdate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test uses sh syntax")
+	}
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", PreUpdateHook: "exit 3"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	if err := service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected update to proceed after a failing hook, got %q", mockProv.updatedIP)
+	}
+}
+
+func readHookOutput(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	return strings.TrimSpace(string(data))
+}
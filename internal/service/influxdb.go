@@ -0,0 +1,97 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	providers "github.com/aaronlmathis/dynago/providers"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/http"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// influxMeasurement is the measurement of the points written to InfluxDB.
+const influxMeasurement = "dns_update"
+
+// pointWriter is the subset of the InfluxDB client's non-blocking api.WriteAPI used by influxWriter.
+type pointWriter interface {
+	WritePoint(point *write.Point)
+	Flush()
+}
+
+// influxWriter writes the history of DNS checks and updates to InfluxDB, one point per check.
+// A nil *influxWriter writes nothing.
+type influxWriter struct {
+	writer pointWriter
+}
+
+// newInfluxWriter creates a writer for cfg, whose URL must be set. Points are buffered and written
+// in the background every cfg.FlushInterval; failed writes are logged and retried by the client.
+//
+// Returns the writer and a function flushing any buffered points and closing the client.
+func newInfluxWriter(cfg config.InfluxDBConfig) (*influxWriter, func()) {
+	opts := influxdb2.DefaultOptions().
+		SetFlushInterval(uint(cfg.FlushInterval.Milliseconds())).
+		SetLogLevel(0) // Errors only; failed writes are logged below
+	client := influxdb2.NewClientWithOptions(cfg.URL, cfg.Token, opts)
+	writeAPI := client.WriteAPI(cfg.Org, cfg.Bucket)
+	writeAPI.SetWriteFailedCallback(func(_ string, err http.Error, retryAttempts uint) bool {
+		logger.Warn("Failed to write to InfluxDB (attempt %d): %v", retryAttempts+1, err.Error())
+		return true
+	})
+	logger.Info("Writing DNS update history to InfluxDB at %s (bucket %s)", cfg.URL, cfg.Bucket)
+	return &influxWriter{writer: writeAPI}, func() {
+		writeAPI.Flush()
+		client.Close()
+	}
+}
+
+// writeUpdate writes a dns_update point for a check of provider's record that took d. updated is
+// true if the record was changed from oldIP to newIP; otherwise newIP is the IP the record holds.
+//
+// The point is tagged with the provider and, if known, the record name.
+func (w *influxWriter) writeUpdate(provider, record, oldIP, newIP string, updated bool, d time.Duration) {
+	if w == nil {
+		return
+	}
+	tags := map[string]string{"provider": provider}
+	if record != "" {
+		tags["record"] = record
+	}
+	fields := map[string]any{
+		"updated":     updated,
+		"old_ip":      oldIP,
+		"new_ip":      newIP,
+		"duration_ms": d.Milliseconds(),
+	}
+	w.writer.WritePoint(write.NewPoint(influxMeasurement, tags, fields, time.Now()))
+}
+
+// recordHistory writes the outcome of a successful check of p, which started at start, to
+// InfluxDB if metrics.influxdb is configured (see influxWriter.writeUpdate). Nothing is written in
+// a dry run, which would otherwise record changes that were never made.
+func (s *DNSUpdateService) recordHistory(p providers.DNSProvider, oldIP, newIP string, updated bool, start time.Time) {
+	if s.metrics.influx == nil || s.cfg.DryRun {
+		return
+	}
+	s.metrics.influx.writeUpdate(p.ProviderName(), recordName(p), oldIP, newIP, updated, time.Since(start))
+}
@@ -0,0 +1,127 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// mockPointWriter records the points written to it in place of the InfluxDB write API.
+type mockPointWriter struct {
+	mu     sync.Mutex
+	points map[string]*write.Point // Keyed by provider tag
+}
+
+func (m *mockPointWriter) WritePoint(point *write.Point) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tag := range point.TagList() {
+		if tag.Key == "provider" {
+			m.points[tag.Value] = point
+		}
+	}
+}
+
+func (m *mockPointWriter) Flush() {}
+
+// pointFields returns point's tags and fields keyed by name.
+func pointFields(point *write.Point) (map[string]string, map[string]any) {
+	tags, fields := map[string]string{}, map[string]any{}
+	for _, tag := range point.TagList() {
+		tags[tag.Key] = tag.Value
+	}
+	for _, field := range point.FieldList() {
+		fields[field.Key] = field.Value
+	}
+	return tags, fields
+}
+
+func TestDNSUpdateService_WritesInfluxPoints(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	writer := &mockPointWriter{points: map[string]*write.Point{}}
+	service.metrics.influx = &influxWriter{writer: writer}
+	changed := &mockProvider{name: "changed", getIP: "1.2.3.4"}
+	unchanged := &mockProvider{name: "unchanged", getIP: "5.6.7.8"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, changed, unchanged), "5.6.7.8", "")
+
+	if len(writer.points) != 2 {
+		t.Fatalf("expected 2 points, got %d", len(writer.points))
+	}
+	for provider, want := range map[string]map[string]any{
+		"changed":   {"updated": true, "old_ip": "1.2.3.4", "new_ip": "5.6.7.8"},
+		"unchanged": {"updated": false, "old_ip": "5.6.7.8", "new_ip": "5.6.7.8"},
+	} {
+		point := writer.points[provider]
+		if point.Name() != "dns_update" {
+			t.Errorf("%s: expected measurement dns_update, got %q", provider, point.Name())
+		}
+		tags, fields := pointFields(point)
+		if len(tags) != 1 || tags["provider"] != provider {
+			t.Errorf("%s: unexpected tags %v", provider, tags) // mockProvider has no record name
+		}
+		for key, value := range want {
+			if fields[key] != value {
+				t.Errorf("%s: expected field %s=%v, got %v", provider, key, value, fields[key])
+			}
+		}
+		if _, ok := fields["duration_ms"].(int64); !ok {
+			t.Errorf("%s: expected int64 duration_ms, got %T", provider, fields["duration_ms"])
+		}
+	}
+}
+
+func TestDNSUpdateService_NoInfluxPointsInDryRun(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", DryRun: true}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	writer := &mockPointWriter{points: map[string]*write.Point{}}
+	service.metrics.influx = &influxWriter{writer: writer}
+	changed := &mockProvider{name: "changed", getIP: "1.2.3.4"}
+	unchanged := &mockProvider{name: "unchanged", getIP: "5.6.7.8"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, changed, unchanged), "5.6.7.8", "")
+
+	if len(writer.points) != 0 {
+		t.Errorf("expected no points in a dry run, got %v", writer.points)
+	}
+}
+
+func TestInfluxWriter_RecordTag(t *testing.T) {
+	writer := &mockPointWriter{points: map[string]*write.Point{}}
+	w := &influxWriter{writer: writer}
+	w.writeUpdate("cloudflare", "home.example.com", "1.2.3.4", "5.6.7.8", true, 1500*time.Millisecond)
+
+	tags, fields := pointFields(writer.points["cloudflare"])
+	if tags["record"] != "home.example.com" {
+		t.Errorf("expected record tag home.example.com, got %v", tags)
+	}
+	if fields["duration_ms"] != int64(1500) {
+		t.Errorf("expected duration_ms 1500, got %v", fields["duration_ms"])
+	}
+
+	var disabled *influxWriter
+	disabled.writeUpdate("cloudflare", "", "", "", false, 0) // Must not panic
+}
@@ -0,0 +1,137 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	providers "github.com/aaronlmathis/dynago/providers"
+	"github.com/rs/zerolog"
+)
+
+// loggingProvider is a mockProvider that records the logger set by SetLogger.
+type loggingProvider struct {
+	mockProvider
+	logger *zerolog.Logger
+}
+
+func (p *loggingProvider) SetLogger(l *zerolog.Logger) { p.logger = l }
+
+// captureLogs sends log output, as JSON, to a buffer for the rest of the test, at debug level.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.SetWriter(&buf)
+	logger.SetLevel("debug")
+	t.Cleanup(func() {
+		logger.SetWriter(nil)
+		logger.SetLevel("info")
+	})
+	return &buf
+}
+
+// logEntries parses the JSON log output captured in buf, one entry per line.
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %v\n%s", err, line)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestDNSUpdateService_LogsProviderField(t *testing.T) {
+	logs := captureLogs(t)
+
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	var found bool
+	for _, entry := range logEntries(t, logs) {
+		if msg, _ := entry["message"].(string); strings.Contains(msg, "DNS record updated to 5.6.7.8") {
+			found = true
+			if entry["provider"] != "mock" {
+				t.Errorf("expected provider field mock, got %v", entry)
+			}
+		}
+	}
+	if !found {
+		t.Error("expected the update to be logged")
+	}
+}
+
+// TestDNSUpdateService_LogsCorrelationID verifies that the entries of one update cycle share a
+// correlation_id, across providers, and that the next cycle gets a different one.
+func TestDNSUpdateService_LogsCorrelationID(t *testing.T) {
+	logs := captureLogs(t)
+
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg,
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+	reg := newTestRegistry(t,
+		&mockProvider{name: "first", getIP: "1.2.3.4"},
+		&mockProvider{name: "second", getIP: "5.6.7.8"})
+	service.checkAndUpdate(reg)
+	service.checkAndUpdate(reg)
+
+	var ids []string
+	seen := map[string]bool{}
+	for _, entry := range logEntries(t, logs) {
+		name, ok := entry["provider"].(string)
+		if !ok {
+			continue
+		}
+		id, _ := entry["correlation_id"].(string)
+		if id == "" {
+			t.Fatalf("expected a correlation_id on provider entry %v", entry)
+		}
+		if len(ids) == 0 || ids[len(ids)-1] != id {
+			ids = append(ids, id)
+		}
+		seen[name] = true
+	}
+	if len(ids) != 2 || ids[0] == ids[1] {
+		t.Errorf("expected one correlation ID per cycle, got %v", ids)
+	}
+	if !seen["first"] || !seen["second"] {
+		t.Errorf("expected entries from both providers, got %v", seen)
+	}
+}
+
+func TestSetProviderLoggers(t *testing.T) {
+	p := &loggingProvider{mockProvider: mockProvider{name: "mock"}}
+	plain := &mockProvider{name: "plain"}
+	setProviderLoggers([]providers.DNSProvider{p, plain})
+	if p.logger == nil {
+		t.Fatal("expected SetLogger to be called")
+	}
+}
@@ -0,0 +1,173 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Values of the status label of dynago_updates_total.
+const (
+	statusSuccess = "success" // The provider's record was checked and, if needed, updated
+	statusError   = "error"   // The check or update failed
+	statusSkipped = "skipped" // The provider's circuit breaker was open
+)
+
+// durationBuckets are the histogram buckets, in seconds, for IP fetch and update durations.
+var durationBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics holds the Prometheus collectors recorded by the service.
+//
+// Each service has its own registry, so several services (e.g. in tests) never collide.
+type metrics struct {
+	registry       *prometheus.Registry
+	updates        *prometheus.CounterVec   // dynago_updates_total{provider,status}
+	ipChecks       *prometheus.CounterVec   // dynago_ip_checks_total{provider}
+	ipUpdates      *prometheus.CounterVec   // dynago_ip_updates_total{provider}
+	updateErrors   *prometheus.CounterVec   // dynago_update_errors_total{provider}
+	lastUpdate     *prometheus.GaugeVec     // dynago_last_update_timestamp_seconds{provider}
+	ipFetch        prometheus.Histogram     // dynago_ip_fetch_duration_seconds
+	updateDuration *prometheus.HistogramVec // dynago_update_duration_seconds{provider}
+
+	// otel mirrors the metrics over OTLP while otel.metrics_endpoint is set; nil otherwise.
+	otel *otelMetrics
+	// emitters receive the metrics as stats, e.g. for StatsD or DataDog (see Emitter).
+	emitters []Emitter
+	// influx receives the history of DNS checks and updates while metrics.influxdb.url is set; nil otherwise.
+	influx *influxWriter
+	// telegraf keeps the latest updates for /metrics/telegraf.
+	telegraf *telegrafBuffer
+}
+
+// newMetrics creates the service's collectors and registers them with a new registry.
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		telegraf: &telegrafBuffer{},
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dynago_updates_total",
+			Help: "Provider checks and updates by outcome (success, error, or skipped).",
+		}, []string{"provider", "status"}),
+		ipChecks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dynago_ip_checks_total",
+			Help: "Provider checks, successful or not, excluding those skipped by the circuit breaker.",
+		}, []string{"provider"}),
+		ipUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dynago_ip_updates_total",
+			Help: "DNS record updates applied to a new IP address (not counting dry runs).",
+		}, []string{"provider"}),
+		updateErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dynago_update_errors_total",
+			Help: "Provider checks or updates that failed.",
+		}, []string{"provider"}),
+		lastUpdate: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "dynago_last_update_timestamp_seconds",
+			Help: "Unix time of the provider's last successful check or update.",
+		}, []string{"provider"}),
+		ipFetch: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "dynago_ip_fetch_duration_seconds",
+			Help:    "Time taken to fetch the public IP address.",
+			Buckets: durationBuckets,
+		}),
+		updateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dynago_update_duration_seconds",
+			Help:    "Time taken to check and update a provider's DNS records.",
+			Buckets: durationBuckets,
+		}, []string{"provider"}),
+	}
+	m.registry.MustRegister(m.updates, m.ipChecks, m.ipUpdates, m.updateErrors, m.lastUpdate, m.ipFetch, m.updateDuration)
+	return m
+}
+
+// observeUpdate records the outcome of one provider's reconciliation that started at start.
+// A skipped provider is counted but its duration is not observed, and emitters, /metrics/telegraf,
+// and the expvar counters only see successes and failures.
+func (m *metrics) observeUpdate(providerName, status string, start time.Time) {
+	d := time.Since(start)
+	m.otel.recordUpdate(providerName, status, d)
+	m.updates.WithLabelValues(providerName, status).Inc()
+	if status == statusSkipped {
+		return
+	}
+	m.ipChecks.WithLabelValues(providerName).Inc()
+	if status == statusError {
+		m.updateErrors.WithLabelValues(providerName).Inc()
+	}
+	m.telegraf.add(providerName, status == statusSuccess, d)
+	observeExpvarUpdate(providerName, status == statusSuccess)
+	outcome := "failure"
+	if status == statusSuccess {
+		outcome = "success"
+	}
+	m.emitStat(Stat{Kind: StatCounter, Name: "update." + outcome, Provider: providerName, Value: 1})
+	m.emitStat(Stat{Kind: StatTimer, Name: "update.duration_ms", Provider: providerName, Duration: d})
+	m.updateDuration.WithLabelValues(providerName).Observe(d.Seconds())
+	if status == statusSuccess {
+		m.lastUpdate.WithLabelValues(providerName).SetToCurrentTime()
+	}
+}
+
+// observeIPFetch records a public IP lookup that started at start.
+func (m *metrics) observeIPFetch(start time.Time) {
+	d := time.Since(start)
+	m.otel.recordIPFetch(d)
+	m.emitStat(Stat{Kind: StatTimer, Name: "ip_fetch.duration_ms", Duration: d})
+	m.ipFetch.Observe(d.Seconds())
+}
+
+// observeBreaker sends the state of a provider's circuit breaker, 1 if open and 0 otherwise, to
+// the emitters. Prometheus exposes breaker state through /livez instead.
+func (m *metrics) observeBreaker(providerName string, open bool) {
+	var value int64
+	if open {
+		value = 1
+	}
+	m.emitStat(Stat{Kind: StatGauge, Name: "circuit_open", Provider: providerName, Value: value})
+}
+
+// observeIPChange counts an applied update in dynago_ip_updates_total and sends an ip.changed
+// event to the emitters after a provider's record was updated from oldIP, which is empty if the
+// record was not read, to newIP.
+func (m *metrics) observeIPChange(providerName, oldIP, newIP string) {
+	m.ipUpdates.WithLabelValues(providerName).Inc()
+	if oldIP == "" {
+		oldIP = "unknown"
+	}
+	text := fmt.Sprintf("%s: DNS record updated from %s to %s", providerName, oldIP, newIP)
+	m.emitStat(Stat{Kind: StatEvent, Name: "ip.changed", Provider: providerName, Text: text})
+}
+
+// serveMetrics serves /metrics on ln until the service context is cancelled or stop is called
+// (see serveHTTP). If metrics.telegraf_addr is the same address, /metrics/telegraf is served too.
+func (s *DNSUpdateService) serveMetrics(ln net.Listener) (stop func()) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+	if addr := s.cfg.Metrics.TelegrafAddr; addr != "" && addr == s.cfg.Metrics.PrometheusAddr {
+		mux.Handle("GET /metrics/telegraf", s.metrics.telegraf)
+	}
+	logger.Info("Serving Prometheus metrics on http://%s/metrics", ln.Addr())
+	return s.serveHTTP("Metrics", ln, mux)
+}
@@ -0,0 +1,144 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+)
+
+func TestMetrics_RunCycleRecordsUpdates(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	failing := &mockProvider{name: "failing", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+	healthy := &mockProvider{name: "healthy", getIP: "1.2.3.4"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, failing, healthy), "5.6.7.8", "")
+
+	m := service.metrics
+	if got := testutil.ToFloat64(m.updates.WithLabelValues("healthy", statusSuccess)); got != 1 {
+		t.Errorf("healthy success count = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.updates.WithLabelValues("failing", statusError)); got != 1 {
+		t.Errorf("failing error count = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(m.lastUpdate.WithLabelValues("healthy")); got < float64(time.Now().Add(-time.Minute).Unix()) {
+		t.Errorf("healthy last update timestamp = %v, want about now", got)
+	}
+	if got := testutil.CollectAndCount(m.lastUpdate); got != 1 {
+		t.Errorf("expected a last update timestamp only for the healthy provider, got %d series", got)
+	}
+	if got := testutil.CollectAndCount(m.updateDuration); got != 2 {
+		t.Errorf("expected update durations for both providers, got %d series", got)
+	}
+}
+
+func TestMetrics_ChecksUpdatesAndErrors(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	changed := &mockProvider{name: "changed", getIP: "1.2.3.4"}
+	unchanged := &mockProvider{name: "unchanged", getIP: "5.6.7.8"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	tripped := &mockProvider{name: "tripped", getIP: "1.2.3.4"}
+	reg := newTestRegistry(t, changed, unchanged, failing, tripped)
+	for range 5 {
+		reg.Breaker(tripped).RecordFailure()
+	}
+
+	service.runCycle(context.Background(), reg, "5.6.7.8", "")
+	service.runCycle(context.Background(), newTestRegistry(t, unchanged), "5.6.7.8", "")
+
+	m := service.metrics
+	for _, c := range []struct {
+		name                    string
+		checks, updates, errors float64
+	}{
+		{"changed", 1, 1, 0},
+		{"unchanged", 2, 0, 0},
+		{"failing", 1, 0, 1},
+		{"tripped", 0, 0, 0},
+	} {
+		checks := testutil.ToFloat64(m.ipChecks.WithLabelValues(c.name))
+		updates := testutil.ToFloat64(m.ipUpdates.WithLabelValues(c.name))
+		errs := testutil.ToFloat64(m.updateErrors.WithLabelValues(c.name))
+		if checks != c.checks || updates != c.updates || errs != c.errors {
+			t.Errorf("%s: checks, updates, errors = %v, %v, %v; want %v, %v, %v",
+				c.name, checks, updates, errs, c.checks, c.updates, c.errors)
+		}
+	}
+}
+
+func TestMetrics_IPFetchDuration(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg, WithIPSourceFunc(func([]string) (string, error) {
+		return "5.6.7.8", nil
+	}))
+
+	if _, err := service.currentIP(context.Background()); err != nil {
+		t.Fatalf("currentIP: %v", err)
+	}
+
+	out, err := testutil.CollectAndFormat(service.metrics.ipFetch, expfmt.TypeTextPlain, "dynago_ip_fetch_duration_seconds")
+	if err != nil {
+		t.Fatalf("CollectAndFormat: %v", err)
+	}
+	if !strings.Contains(string(out), "dynago_ip_fetch_duration_seconds_count 1") {
+		t.Errorf("expected one IP fetch to be observed, got:\n%s", out)
+	}
+}
+
+func TestMetrics_ServerStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewDNSUpdateService(ctx, &config.Config{Interval: time.Minute, IPSource: "mock"})
+	service.metrics.updates.WithLabelValues("mock", statusSuccess).Inc()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	stop := service.serveMetrics(ln)
+	url := "http://" + ln.Addr().String() + "/metrics"
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `dynago_updates_total{provider="mock",status="success"} 1`) {
+		t.Errorf("expected the update counter in /metrics, got:\n%s", body)
+	}
+
+	cancel()
+	stop() // Waits for the shutdown triggered by cancel
+	if _, err := http.Get(url); err == nil {
+		t.Error("expected the metrics server to be shut down after the context was cancelled")
+	}
+}
@@ -0,0 +1,84 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build otel
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName identifies the instruments created by the service.
+const meterName = "github.com/aaronlmathis/dynago/internal/service"
+
+// otelMetrics holds the OpenTelemetry instruments mirroring the service's Prometheus metrics.
+// A nil *otelMetrics records nothing.
+type otelMetrics struct {
+	updates        metric.Int64UpDownCounter // dynago.updates.total{provider,status}
+	ipFetch        metric.Float64Histogram   // dynago.ip_fetch.duration
+	updateDuration metric.Float64Histogram   // dynago.update.duration{provider}
+}
+
+// newOtelMetrics creates the service's instruments from mp.
+func newOtelMetrics(mp metric.MeterProvider) (*otelMetrics, error) {
+	meter := mp.Meter(meterName)
+	updates, err := meter.Int64UpDownCounter("dynago.updates.total",
+		metric.WithDescription("Provider checks and updates by outcome (success, error, or skipped)."))
+	if err != nil {
+		return nil, err
+	}
+	ipFetch, err := meter.Float64Histogram("dynago.ip_fetch.duration",
+		metric.WithDescription("Time taken to fetch the public IP address."),
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, err
+	}
+	updateDuration, err := meter.Float64Histogram("dynago.update.duration",
+		metric.WithDescription("Time taken to check and update a provider's DNS records."),
+		metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, err
+	}
+	return &otelMetrics{updates: updates, ipFetch: ipFetch, updateDuration: updateDuration}, nil
+}
+
+// recordUpdate records the outcome of one provider's reconciliation, which took d. As with the
+// Prometheus metrics, the duration of a skipped provider is not recorded.
+func (m *otelMetrics) recordUpdate(providerName, status string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	provider := attribute.String("provider", providerName)
+	m.updates.Add(context.Background(), 1, metric.WithAttributes(provider, attribute.String("status", status)))
+	if status != statusSkipped {
+		m.updateDuration.Record(context.Background(), d.Seconds(), metric.WithAttributes(provider))
+	}
+}
+
+// recordIPFetch records a public IP lookup that took d.
+func (m *otelMetrics) recordIPFetch(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.ipFetch.Record(context.Background(), d.Seconds())
+}
@@ -0,0 +1,98 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build otel
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOtelMetrics_RecordCycle(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	instruments, err := newOtelMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("newOtelMetrics: %v", err)
+	}
+	cfg := &config.Config{IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg,
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+	service.metrics.otel = instruments
+
+	ok := &mockProvider{name: "ok", getIP: "5.6.7.8"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	service.checkAndUpdate(newTestRegistry(t, ok, failing))
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := make(map[string]metricdata.Aggregation)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			got[m.Name] = m.Data
+		}
+	}
+
+	updates, _ := got["dynago.updates.total"].(metricdata.Sum[int64])
+	counts := make(map[string]int64)
+	for _, dp := range updates.DataPoints {
+		provider, _ := dp.Attributes.Value("provider")
+		status, _ := dp.Attributes.Value("status")
+		counts[provider.AsString()+"/"+status.AsString()] = dp.Value
+	}
+	if counts["ok/success"] != 1 || counts["failing/error"] != 1 || len(counts) != 2 {
+		t.Errorf("dynago.updates.total = %v, want ok/success and failing/error once each", counts)
+	}
+	if updates.IsMonotonic {
+		t.Error("expected dynago.updates.total to be an UpDownCounter")
+	}
+
+	ipFetch, _ := got["dynago.ip_fetch.duration"].(metricdata.Histogram[float64])
+	if len(ipFetch.DataPoints) != 1 || ipFetch.DataPoints[0].Count != 1 {
+		t.Errorf("dynago.ip_fetch.duration = %+v, want one observation", ipFetch.DataPoints)
+	}
+
+	duration, _ := got["dynago.update.duration"].(metricdata.Histogram[float64])
+	for _, provider := range []string{"ok", "failing"} {
+		set := attribute.NewSet(attribute.String("provider", provider))
+		var count uint64
+		for _, dp := range duration.DataPoints {
+			if dp.Attributes.Equals(&set) {
+				count = dp.Count
+			}
+		}
+		if count != 1 {
+			t.Errorf("dynago.update.duration{provider=%q} count = %d, want 1", provider, count)
+		}
+	}
+}
+
+func TestOtelMetrics_NilRecordsNothing(t *testing.T) {
+	var m *otelMetrics
+	m.recordUpdate("mock", statusSuccess, 0)
+	m.recordIPFetch(0)
+}
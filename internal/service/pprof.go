@@ -0,0 +1,44 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"net"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// serveProfiling serves the net/http/pprof handlers under /debug/pprof/ on ln until the service
+// context is cancelled or stop is called (see serveHTTP).
+//
+// The handlers are registered on a dedicated mux, never http.DefaultServeMux, so no other server
+// exposes them. They must never be served on a public interface: profiles and goroutine dumps
+// reveal sensitive runtime state, including credentials held in memory.
+func (s *DNSUpdateService) serveProfiling(ln net.Listener) (stop func()) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	logger.Debug("Serving pprof profiles on http://%s/debug/pprof/", ln.Addr())
+	return s.serveHTTP("pprof", ln, mux)
+}
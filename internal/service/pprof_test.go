@@ -0,0 +1,77 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+func TestDNSUpdateService_ServeProfiling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewDNSUpdateService(ctx, &config.Config{Interval: time.Minute, IPSource: "mock"})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	stop := service.serveProfiling(ln)
+	base := "http://" + ln.Addr().String()
+
+	resp, err := http.Get(base + "/debug/pprof/goroutine?debug=1")
+	if err != nil {
+		t.Fatalf("GET /debug/pprof/goroutine: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "goroutine profile") {
+		t.Errorf("expected a goroutine profile, got %s:\n%.200s", resp.Status, body)
+	}
+
+	cancel()
+	stop() // Waits for the shutdown triggered by cancel
+	if _, err := http.Get(base + "/debug/pprof/"); err == nil {
+		t.Error("expected the pprof server to be shut down after the context was cancelled")
+	}
+}
+
+func TestDNSUpdateService_ExpvarDoesNotServeProfiling(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"})
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer service.serveExpvar(ln)()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/debug/pprof/")
+	if err != nil {
+		t.Fatalf("GET /debug/pprof/: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected the expvar server not to serve pprof, got %s", resp.Status)
+	}
+}
@@ -0,0 +1,94 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/aaronlmathis/dynago/internal/breaker"
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// probeHandler returns the handler for the probe endpoints:
+//
+//   - /healthz returns 200 once the update loop has started, 503 before.
+//   - /readyz returns 200 once a cycle has fetched the public IP and updated every provider, 503 before.
+//   - /livez returns 503 while the circuit breaker of every provider is open, 200 otherwise.
+//   - /status returns the service Status as JSON.
+func (s *DNSUpdateService) probeHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, s.started.Load(), "update loop not started")
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, s.ready.Load(), "no successful update cycle yet")
+	})
+	mux.HandleFunc("GET /livez", func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, !s.allBreakersOpen(), "circuit breaker open for every provider")
+	})
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.Status())
+	})
+	return mux
+}
+
+// probeResult is the JSON body of a probe response.
+type probeResult struct {
+	Status string `json:"status"`           // "ok" or "unavailable"
+	Reason string `json:"reason,omitempty"` // Why the probe failed
+}
+
+// writeProbe writes 200 {"status":"ok"} if ok is true, or 503 with reason otherwise.
+func writeProbe(w http.ResponseWriter, ok bool, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(probeResult{Status: "unavailable", Reason: reason})
+		return
+	}
+	json.NewEncoder(w).Encode(probeResult{Status: "ok"})
+}
+
+// allBreakersOpen reports whether the current registry has providers and every one of their
+// circuit breakers is open.
+func (s *DNSUpdateService) allBreakersOpen() bool {
+	reg := s.registry.Load()
+	if reg == nil || len(reg.Providers) == 0 {
+		return false
+	}
+	for _, p := range reg.Providers {
+		// The map is read without a lock. This is safe only because NewDNSProviderRegistry creates
+		// every provider's breaker up front, so the map is never written once the registry is in
+		// use. Breaker is not called since it would add a missing entry.
+		if b, ok := reg.Breakers[p.ProviderName()]; !ok || b.State() != breaker.Open {
+			return false
+		}
+	}
+	return true
+}
+
+// serveProbes serves the probe endpoints on ln until the service context is cancelled or stop is
+// called (see serveHTTP).
+func (s *DNSUpdateService) serveProbes(ln net.Listener) (stop func()) {
+	logger.Info("Serving health probes on http://%s (/healthz, /readyz, /livez, /status)", ln.Addr())
+	return s.serveHTTP("Probe", ln, s.probeHandler())
+}
@@ -0,0 +1,214 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// probeStatus returns the status code of GET path on the service's probe handler.
+func probeStatus(s *DNSUpdateService, path string) int {
+	rec := httptest.NewRecorder()
+	s.probeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestProbes_HealthzAfterLoopStarts(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"})
+	if got := probeStatus(service, "/healthz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/healthz before start = %d, want 503", got)
+	}
+	service.started.Store(true)
+	if got := probeStatus(service, "/healthz"); got != http.StatusOK {
+		t.Errorf("/healthz after start = %d, want 200", got)
+	}
+}
+
+func TestProbes_ReadyzAfterSuccessfulCycle(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	ipErr := errors.New("no route")
+	service := NewDNSUpdateService(context.Background(), cfg, WithIPSourceFunc(func([]string) (string, error) {
+		return "5.6.7.8", ipErr
+	}))
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+	reg := newTestRegistry(t, mockProv)
+
+	service.checkAndUpdate(reg)
+	if got := probeStatus(service, "/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz after a failed IP fetch = %d, want 503", got)
+	}
+	ipErr = nil
+	service.checkAndUpdate(reg)
+	if got := probeStatus(service, "/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("/readyz after a failed update = %d, want 503", got)
+	}
+	mockProv.updateErr = nil
+	service.checkAndUpdate(reg)
+	if got := probeStatus(service, "/readyz"); got != http.StatusOK {
+		t.Errorf("/readyz after a successful cycle = %d, want 200", got)
+	}
+}
+
+// TestProbes_ServeJSONOverHTTP checks the status codes and JSON bodies of /healthz and /readyz
+// when served over HTTP.
+func TestProbes_ServeJSONOverHTTP(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"})
+	server := httptest.NewServer(service.probeHandler())
+	defer server.Close()
+
+	get := func(path string) (int, probeResult) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("GET %s Content-Type = %q, want application/json", path, ct)
+		}
+		var body probeResult
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("GET %s body is not JSON: %v", path, err)
+		}
+		return resp.StatusCode, body
+	}
+
+	service.started.Store(true)
+	if code, body := get("/healthz"); code != http.StatusOK || body != (probeResult{Status: "ok"}) {
+		t.Errorf("/healthz = %d %+v, want 200 {Status:ok}", code, body)
+	}
+	if code, body := get("/readyz"); code != http.StatusServiceUnavailable || body.Status != "unavailable" || body.Reason == "" {
+		t.Errorf("/readyz before a cycle = %d %+v, want 503 with a reason", code, body)
+	}
+	service.ready.Store(true)
+	if code, body := get("/readyz"); code != http.StatusOK || body != (probeResult{Status: "ok"}) {
+		t.Errorf("/readyz after a cycle = %d %+v, want 200 {Status:ok}", code, body)
+	}
+}
+
+func TestProbes_LivezWhenEveryBreakerIsOpen(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"})
+	first, second := &mockProvider{name: "first"}, &mockProvider{name: "second"}
+	reg := newTestRegistry(t, first, second)
+	service.registry.Store(reg)
+
+	trip := func(name string) {
+		b := reg.Breakers[name]
+		for range b.FailureThreshold {
+			b.RecordFailure()
+		}
+	}
+	trip("first")
+	if got := probeStatus(service, "/livez"); got != http.StatusOK {
+		t.Errorf("/livez with one open breaker = %d, want 200", got)
+	}
+	trip("second")
+	if got := probeStatus(service, "/livez"); got != http.StatusServiceUnavailable {
+		t.Errorf("/livez with every breaker open = %d, want 503", got)
+	}
+}
+
+// TestProbes_StatusReportsIPSourceLatency checks that /status reports the latency of the IP
+// lookups made so far and the provider counters.
+func TestProbes_StatusReportsIPSourceLatency(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"},
+		WithIPSourceFunc(func([]string) (string, error) {
+			time.Sleep(2 * time.Millisecond)
+			return "5.6.7.8", nil
+		}))
+	for range 3 {
+		service.currentIP(context.Background())
+	}
+	service.recordError("mock", errors.New("boom"))
+	service.publishStatus("5.6.7.8", "")
+
+	rec := httptest.NewRecorder()
+	service.probeHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/status = %d, want 200", rec.Code)
+	}
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("/status is not JSON: %v\n%s", err, rec.Body)
+	}
+	latency := status.IPSourceLatency
+	if latency.Samples != 3 || latency.P50 < 2 || latency.P99 < latency.P50 || latency.Mean < 2 {
+		t.Errorf("unexpected ip_source_latency_ms %+v", latency)
+	}
+	if p := status.Providers["mock"]; p.Errors != 1 || p.LastError != "boom" {
+		t.Errorf("unexpected providers.mock %+v", p)
+	}
+}
+
+// TestProbes_StatusAfterUpdateCycle checks that /status reports the public IP fetched by the last
+// cycle and each provider's DNS IP, last update, and errors.
+func TestProbes_StatusAfterUpdateCycle(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{Interval: time.Minute, IPSource: "mock"},
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+	changed := &mockProvider{name: "changed", getIP: "1.2.3.4"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	service.checkAndUpdate(newTestRegistry(t, changed, failing))
+
+	server := httptest.NewServer(service.probeHandler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/status")
+	if err != nil {
+		t.Fatalf("GET /status: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading /status: %v", err)
+	}
+	for _, field := range []string{`"public_ip":"5.6.7.8"`, `"dns_ip":"5.6.7.8"`, `"last_update":"`, `"errors":0`} {
+		if !strings.Contains(string(body), field) {
+			t.Errorf("/status lacks %s:\n%s", field, body)
+		}
+	}
+	var status Status
+	if err := json.Unmarshal(body, &status); err != nil {
+		t.Fatalf("/status is not JSON: %v", err)
+	}
+
+	if status.PublicIP != "5.6.7.8" {
+		t.Errorf("public_ip = %q, want 5.6.7.8", status.PublicIP)
+	}
+	if p := status.Providers["changed"]; p.DNSIP != "5.6.7.8" || p.LastUpdate.IsZero() || p.Errors != 0 {
+		t.Errorf("unexpected providers.changed %+v", p)
+	}
+	if p := status.Providers["failing"]; p.DNSIP != "" || !p.LastUpdate.IsZero() || p.Errors != 1 || p.LastError != "boom" {
+		t.Errorf("unexpected providers.failing %+v", p)
+	}
+
+	// Changes made during the next cycle are not served until it is published.
+	service.recordSuccess("failing", "5.6.7.8", true)
+	if p := service.Status().Providers["failing"]; p.Errors != 1 || p.DNSIP != "" {
+		t.Errorf("expected /status to keep the last published cycle, got %+v", p)
+	}
+}
@@ -0,0 +1,105 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// pushTimeout bounds each request to the Pushgateway.
+const pushTimeout = 10 * time.Second
+
+// pusher pushes the service's metrics to a Prometheus Pushgateway after each update cycle.
+//
+// It gathers from its own registry, holding the service's collectors plus the batch-job gauges
+// below, so the gauges never appear on the /metrics scrape endpoint.
+type pusher struct {
+	push             *push.Pusher
+	lastRun          prometheus.Gauge // dynago_last_run_timestamp_seconds
+	lastRunSuccess   prometheus.Gauge // dynago_last_run_success
+	deleteOnShutdown bool
+}
+
+// newPusher creates a pusher for cfg, whose URL must be set, gathering the collectors in m.
+//
+// The job defaults to config.DefaultPushgatewayJob and the instance to the host name.
+func newPusher(cfg config.PushgatewayConfig, m *metrics) *pusher {
+	job := cfg.Job
+	if job == "" {
+		job = config.DefaultPushgatewayJob
+	}
+	instance := cfg.Instance
+	if instance == "" {
+		instance, _ = os.Hostname()
+	}
+	p := &pusher{
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dynago_last_run_timestamp_seconds",
+			Help: "Unix time of the last update cycle.",
+		}),
+		lastRunSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "dynago_last_run_success",
+			Help: "1 if every provider was updated successfully in the last cycle, 0 otherwise.",
+		}),
+		deleteOnShutdown: cfg.DeleteOnShutdown,
+	}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.updates, m.lastUpdate, m.ipFetch, m.updateDuration, p.lastRun, p.lastRunSuccess)
+	p.push = push.New(cfg.URL, job).
+		Grouping("instance", instance).
+		Gatherer(reg).
+		Client(&http.Client{Timeout: pushTimeout})
+	return p
+}
+
+// pushCycle records the outcome of the cycle that returned err and pushes every metric,
+// replacing those previously pushed for this job and instance. A failed push is only logged.
+// It does nothing on a nil pusher, i.e. when no Pushgateway is configured.
+func (p *pusher) pushCycle(ctx context.Context, err error) {
+	if p == nil {
+		return
+	}
+	p.lastRun.SetToCurrentTime()
+	if err != nil {
+		p.lastRunSuccess.Set(0)
+	} else {
+		p.lastRunSuccess.Set(1)
+	}
+	if err := p.push.PushContext(ctx); err != nil {
+		logger.Warn("Failed to push metrics to the Pushgateway: %v", err)
+	}
+}
+
+// shutdown deletes the pushed metrics if delete_on_shutdown is set. It does nothing on a nil pusher.
+func (p *pusher) shutdown() {
+	if p == nil || !p.deleteOnShutdown {
+		return
+	}
+	if err := p.push.Delete(); err != nil {
+		logger.Warn("Failed to delete metrics from the Pushgateway: %v", err)
+	}
+}
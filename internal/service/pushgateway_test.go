@@ -0,0 +1,121 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// pushRequest is a request received by the mock Pushgateway.
+type pushRequest struct {
+	method string
+	path   string
+	body   string
+}
+
+// newMockPushgateway starts a server that records every request and returns a function listing them.
+func newMockPushgateway(t *testing.T) (*httptest.Server, func() []pushRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []pushRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, pushRequest{method: r.Method, path: r.URL.Path, body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []pushRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pushRequest(nil), reqs...)
+	}
+}
+
+func TestPushgateway_PushesAfterOnceRun(t *testing.T) {
+	srv, requests := newMockPushgateway(t)
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	cfg.Metrics.Pushgateway = config.PushgatewayConfig{URL: srv.URL, Instance: "host1", DeleteOnShutdown: true}
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(mockProv), WithIPSourceFunc(func([]string) (string, error) {
+		return "5.6.7.8", nil
+	}))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected exactly one push (and no delete after --once), got %+v", reqs)
+	}
+	if reqs[0].method != http.MethodPut || reqs[0].path != "/metrics/job/dynago/instance/host1" {
+		t.Errorf("unexpected push request %s %s", reqs[0].method, reqs[0].path)
+	}
+	// The body is in the protobuf exposition format, which keeps metric names and labels readable.
+	for _, want := range []string{"dynago_updates_total", "dynago_last_run_success", "mock"} {
+		if !strings.Contains(reqs[0].body, want) {
+			t.Errorf("expected pushed metrics to contain %q", want)
+		}
+	}
+}
+
+func TestPushgateway_DeletesOnShutdown(t *testing.T) {
+	srv, requests := newMockPushgateway(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	cfg.Metrics.Pushgateway = config.PushgatewayConfig{URL: srv.URL, Job: "ddns", Instance: "host1", DeleteOnShutdown: true}
+	service := NewDNSUpdateService(ctx, cfg, WithProviders(&mockProvider{name: "mock"}))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 || reqs[0].method != http.MethodDelete || reqs[0].path != "/metrics/job/ddns/instance/host1" {
+		t.Errorf("expected a single DELETE of the pushed group on shutdown, got %+v", reqs)
+	}
+}
+
+func TestPushgateway_FailedPushDoesNotFailRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	cfg.Metrics.Pushgateway.URL = srv.URL
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(&mockProvider{name: "mock", getIP: "5.6.7.8"}), WithIPSourceFunc(func([]string) (string, error) {
+		return "5.6.7.8", nil
+	}))
+
+	if err := service.Start(); err != nil {
+		t.Errorf("expected a Pushgateway failure to be logged only, got %v", err)
+	}
+}
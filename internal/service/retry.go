@@ -0,0 +1,103 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// jitterFraction is the maximum relative jitter (±20%) applied to each retry delay.
+const jitterFraction = 0.2
+
+// RetryPolicy controls how failed provider updates are retried within a single update cycle.
+//
+// Delays grow exponentially (BaseDelay * 2^attempt) with ±20% jitter and are capped at MaxDelay.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first; values <= 1 disable retries
+	BaseDelay   time.Duration // Delay before the first retry
+	MaxDelay    time.Duration // Upper bound for any single delay (0 means unbounded)
+}
+
+// NewRetryPolicy builds a RetryPolicy from the retry_policy section of the config.
+func NewRetryPolicy(cfg config.RetryPolicyConfig) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   cfg.BaseDelay,
+		MaxDelay:    cfg.MaxDelay,
+	}
+}
+
+// Delay returns the jittered backoff delay to wait before retry number attempt (starting at 0).
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		// A non-positive value means the shift overflowed.
+		d = p.MaxDelay
+	}
+	jitter := (rand.Float64()*2 - 1) * jitterFraction * float64(d)
+	d += time.Duration(jitter)
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return d
+}
+
+// Do calls fn until it succeeds, MaxAttempts is reached, ctx is cancelled, or fn returns an
+// error that is not worth retrying (see retryable).
+//
+// onRetry, if non-nil, is called before each wait with the failed attempt number (starting at 1),
+// the error it returned, and the delay before the next attempt.
+//
+// Returns nil on success, ctx.Err() if cancelled while waiting, or the last error from fn.
+func (p RetryPolicy) Do(ctx context.Context, fn func() error, onRetry func(attempt int, err error, delay time.Duration)) error {
+	err := fn()
+	for attempt := 1; err != nil && attempt < p.MaxAttempts && retryable(err); attempt++ {
+		delay := p.Delay(attempt - 1)
+		if onRetry != nil {
+			onRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		err = fn()
+	}
+	return err
+}
+
+// retryable reports whether err should be retried within the current update cycle.
+//
+// Classified provider errors are retried only when Temporary and not RateLimited; rate-limited
+// providers are left alone until the next cycle. Unclassified errors are always retried.
+func retryable(err error) bool {
+	var pe *providers.ProviderError
+	if errors.As(err, &pe) {
+		return pe.Temporary && !pe.RateLimited
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+func TestRetryPolicy_DelayBackoffAndJitter(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		d := p.Delay(attempt)
+		min := time.Duration(float64(base) * 0.8)
+		max := time.Duration(float64(base) * 1.2)
+		if d < min || d > max {
+			t.Errorf("attempt %d: delay %s outside [%s, %s]", attempt, d, min, max)
+		}
+	}
+	if d := p.Delay(10); d > p.MaxDelay {
+		t.Errorf("delay %s exceeds MaxDelay %s", d, p.MaxDelay)
+	}
+}
+
+func TestRetryPolicy_DoRetriesUntilSuccess(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	calls := 0
+	err := p.Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryPolicy_DoStopsAtMaxAttempts(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}
+	calls := 0
+	retries := 0
+	err := p.Do(context.Background(), func() error {
+		calls++
+		return errors.New("permanent")
+	}, func(int, error, time.Duration) { retries++ })
+	if err == nil {
+		t.Fatalf("expected error after exhausting attempts")
+	}
+	if calls != 2 || retries != 1 {
+		t.Errorf("expected 2 calls and 1 retry, got %d calls and %d retries", calls, retries)
+	}
+}
+
+func TestRetryPolicy_DoStopsOnCancel(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := p.Do(ctx, func() error {
+		calls++
+		return errors.New("transient")
+	}, func(int, error, time.Duration) { cancel() })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call before cancellation, got %d", calls)
+	}
+}
+
+func TestRetryPolicy_DoSkipsNonRetryableProviderErrors(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	for _, pe := range []*providers.ProviderError{
+		{Provider: "mock", Op: "update record", Err: errors.New("denied"), Unauthorized: true},
+		{Provider: "mock", Op: "update record", Err: errors.New("slow down"), RateLimited: true, Temporary: true},
+	} {
+		calls := 0
+		p.Do(context.Background(), func() error {
+			calls++
+			return pe
+		}, nil)
+		if calls != 1 {
+			t.Errorf("%+v: expected 1 call without retries, got %d", pe, calls)
+		}
+	}
+
+	calls := 0
+	p.Do(context.Background(), func() error {
+		calls++
+		return &providers.ProviderError{Provider: "mock", Op: "update record", Err: errors.New("timeout"), Temporary: true}
+	}, nil)
+	if calls != 3 {
+		t.Errorf("expected temporary errors to be retried 3 times, got %d", calls)
+	}
+}
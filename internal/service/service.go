@@ -24,35 +24,173 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/aaronlmathis/dynago/internal/breaker"
 	"github.com/aaronlmathis/dynago/internal/config"
 	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/aaronlmathis/dynago/internal/store"
 	"github.com/aaronlmathis/dynago/internal/utils"
 	providers "github.com/aaronlmathis/dynago/providers"
 	cfprovider "github.com/aaronlmathis/dynago/providers/cloudflare"
 	r53provider "github.com/aaronlmathis/dynago/providers/route53"
+	"github.com/rs/zerolog"
 )
 
+// SelfTestTimeout bounds each provider's SelfTest call.
+const SelfTestTimeout = 10 * time.Second
+
 // DNSUpdateService manages the periodic update of DNS records for the host's current public IP.
 //
 // It loads configuration, initializes providers, and runs a loop to check and update DNS records as needed.
 type DNSUpdateService struct {
-	cfg      *config.Config        // Application configuration
-	ctx      context.Context       // Service context for cancellation
-	provider providers.DNSProvider // (Unused, reserved for future single-provider mode)
+	cfg      *config.Config            // Application configuration
+	ctx      context.Context           // Service context for cancellation
+	provider providers.DNSProvider     // (Unused, reserved for future single-provider mode)
+	injected []providers.DNSProvider   // Providers supplied by WithProviders, used instead of cfg.Providers
+	mu       sync.Mutex                // Guards pending while providers are reconciled in parallel
+	pending  map[string]pendingIP      // providerName -> new IP awaiting debounce confirmation
+	onReload func(*config.Config)      // Called after a successful SIGHUP reload (used by tests)
+	statsMu  sync.Mutex                // Guards stats
+	stats    map[string]*ProviderStats // providerName -> running counters
+	statusMu sync.RWMutex              // Guards status
+	status   Status                    // Snapshot published after each cycle, served on /status
+	metrics  *metrics                  // Prometheus collectors, served when metrics.prometheus_addr is set
+	latency  *utils.LatencyTracker     // Durations of recent public IP lookups, served on /status
+	audit    *logger.AuditLogger       // Audit log of applied IP changes, if audit_log is set
+	history  *store.HistoryStore       // Database of applied IP changes, if history_db is set
+	started  atomic.Bool               // Set once the update loop runs, for /healthz
+	ready    atomic.Bool               // Set after the first fully successful cycle, for /readyz
+
+	// registry is the provider registry in use, read by /livez.
+	registry atomic.Pointer[providers.DNSProviderRegistry]
+
+	// IPSourceFunc returns the current public IP from the configured sources.
+	// When nil, utils.GetCurrentIP is used (see WithIPSourceFunc).
+	IPSourceFunc func(sources []string) (string, error)
+
+	// Events, if non-nil, receives a ProviderEvent after each update attempt (see WithEvents).
+	// Sends are non-blocking, so the channel must be drained by the caller.
+	Events chan ProviderEvent
+}
+
+// pendingIP tracks a new IP that has been observed but not yet confirmed by debouncing.
+type pendingIP struct {
+	ip    string // Newly observed IP
+	count int    // Consecutive cycles the IP has been observed
+}
+
+// Option configures a DNSUpdateService.
+type Option func(*DNSUpdateService)
+
+// WithProviders makes the service use the given providers instead of building them from cfg.Providers.
+//
+// This is mainly useful for tests and for embedding dynago with custom DNSProvider implementations.
+func WithProviders(ps ...providers.DNSProvider) Option {
+	return func(s *DNSUpdateService) {
+		s.injected = ps
+	}
+}
+
+// WithIPSourceFunc replaces the public IP lookup, e.g. to return a fixed IP in tests.
+func WithIPSourceFunc(f func([]string) (string, error)) Option {
+	return func(s *DNSUpdateService) {
+		s.IPSourceFunc = f
+	}
 }
 
 // NewDNSUpdateService creates a new DNSUpdateService with the given context and configuration.
 //
 // ctx: Context for cancellation and shutdown.
 // cfg: Loaded application configuration.
-func NewDNSUpdateService(ctx context.Context, cfg *config.Config) *DNSUpdateService {
-	return &DNSUpdateService{
-		cfg: cfg,
-		ctx: ctx,
+// opts: Optional settings such as WithEvents.
+func NewDNSUpdateService(ctx context.Context, cfg *config.Config, opts ...Option) *DNSUpdateService {
+	s := &DNSUpdateService{
+		cfg:     cfg,
+		ctx:     ctx,
+		pending: make(map[string]pendingIP),
+		stats:   make(map[string]*ProviderStats),
+		metrics: newMetrics(),
+		latency: utils.NewLatencyTracker(utils.DefaultLatencyWindow),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// EnabledProviders constructs every provider configured in cfg and returns those that are enabled.
+//
+// Providers whose configuration cannot be parsed or is invalid are skipped with an error log.
+func EnabledProviders(cfg *config.Config) []providers.DNSProvider {
+	var providersList []providers.DNSProvider
+	if raw, ok := cfg.Providers["cloudflare"]; ok {
+		cf, err := cfprovider.New(raw)
+		switch {
+		case err != nil:
+			logger.Error("Skipping cloudflare provider: %v", err)
+		case cf.Cfg.Enabled:
+			providersList = append(providersList, cf)
+		}
+	}
+	if raw, ok := cfg.Providers["route53"]; ok {
+		r53, err := r53provider.New(raw)
+		switch {
+		case err != nil:
+			logger.Error("Skipping route53 provider: %v", err)
+		case r53.Cfg.Enabled:
+			providersList = append(providersList, r53)
+		}
+	}
+	return providersList
+}
+
+// providersFor returns the injected providers if WithProviders was used, or else the providers enabled in cfg,
+// narrowed to cfg.OnlyProviders when it is set.
+//
+// Returns an error if OnlyProviders names a provider that is not enabled.
+func (s *DNSUpdateService) providersFor(cfg *config.Config) ([]providers.DNSProvider, error) {
+	list := s.injected
+	if len(list) == 0 {
+		list = EnabledProviders(cfg)
+	}
+	return FilterProviders(list, cfg.OnlyProviders)
+}
+
+// FilterProviders returns the providers whose ProviderName is in names (case-insensitive), in
+// their original order. An empty names list returns all providers.
+//
+// Returns an error if a name does not match any of the given providers.
+func FilterProviders(list []providers.DNSProvider, names []string) ([]providers.DNSProvider, error) {
+	if len(names) == 0 {
+		return list, nil
+	}
+	reg := &providers.DNSProviderRegistry{Providers: list}
+	wanted := make(map[providers.DNSProvider]bool, len(names))
+	for _, name := range names {
+		p, ok := reg.GetProvider(name)
+		if !ok {
+			return nil, fmt.Errorf("provider %q is not enabled in config", name)
+		}
+		wanted[p] = true
+	}
+	var filtered []providers.DNSProvider
+	for _, p := range list {
+		if wanted[p] {
+			filtered = append(filtered, p)
+		}
 	}
+	return filtered, nil
 }
 
 // Start begins the DNS update loop.
@@ -60,71 +198,673 @@ func NewDNSUpdateService(ctx context.Context, cfg *config.Config) *DNSUpdateServ
 // It periodically fetches the current public IP address using the configured source,
 // compares it to the DNS records for each enabled provider, and updates the records if the IP has changed.
 //
-// Returns an error if the service cannot start or if no providers are enabled.
+// When Once is set in config, a single cycle is run without a ticker and its result is returned.
+// Otherwise the loop runs until the service context is cancelled; SIGHUP reloads the config file.
+// With run_on_start set, every provider's HealthCheck runs before any cycle, and the loop runs its
+// first cycle immediately instead of after one interval.
+// With metrics.pushgateway.url set, metrics are pushed after every cycle, and with metrics.statsd
+// or metrics.datadog enabled they are also sent to StatsD or DataDog as they are recorded. With
+// metrics.influxdb.url set, the history of DNS checks and updates is written to InfluxDB. Applied IP
+// changes are appended to the audit_log CSV file and recorded in the history_db SQLite database
+// when those are set; a reload reopens neither. With debug.expvar_addr set, runtime counters are
+// served on /debug/vars, and with debug.pprof_addr set, profiles on /debug/pprof/. With probes.addr
+// set, the loop serves the /healthz, /readyz, and /livez endpoints.
+//
+// Log entries about a provider, from the update loop and from providers implementing
+// providers.LoggerSetter, carry its name in a structured provider field (see logger.WithProvider).
+// Entries from the update loop also carry the correlation ID of their cycle (see
+// logger.WithCorrelationID).
+//
+// Returns an error if the service cannot start. If the provider registry cannot be created because
+// no providers are enabled, Start logs a fatal error and exits the process (see logger.Fatal).
+// Nothing has been started at that point, so no deferred cleanup is skipped.
 func (s *DNSUpdateService) Start() error {
 	if s.cfg == nil {
 		return nil // No configuration provided, nothing to do.
 	}
 	logger.Info("DNSUpdateService starting... ")
+	if s.cfg.DryRun {
+		logger.Info("[dry-run] Dry-run mode enabled, DNS records will not be modified")
+	}
 
-	var providersList []providers.DNSProvider
-	if raw, ok := s.cfg.Providers["cloudflare"]; ok {
-		cf, err := cfprovider.New(raw)
-		if err == nil && cf.Cfg.Enabled {
-			providersList = append(providersList, cf)
+	providersList, err := s.providersFor(s.cfg)
+	if err != nil {
+		logger.Error("Invalid provider selection: %v", err)
+		return err
+	}
+	reg, err := providers.NewDNSProviderRegistry(s.cfg, providersList...)
+	if err != nil {
+		logger.Fatal("Failed to create DNS provider registry: %v", err)
+		return fmt.Errorf("failed to create DNS provider registry: %w", err) // Reached only if logger.SetExitFunc replaced the exit
+	}
+	setProviderLoggers(reg.Providers)
+
+	defer func() { closeProviders(reg.Providers) }()
+
+	if addr := s.cfg.Metrics.PrometheusAddr; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error("Failed to start metrics server: %v", err)
+			return fmt.Errorf("failed to listen on metrics.prometheus_addr %s: %w", addr, err)
 		}
+		defer s.serveMetrics(ln)()
 	}
-	if raw, ok := s.cfg.Providers["route53"]; ok {
-		r53, err := r53provider.New(raw)
-		if err == nil && r53.Cfg.Enabled {
-			providersList = append(providersList, r53)
+	if addr := s.cfg.Metrics.TelegrafAddr; addr != "" && addr != s.cfg.Metrics.PrometheusAddr {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error("Failed to start Telegraf metrics server: %v", err)
+			return fmt.Errorf("failed to listen on metrics.telegraf_addr %s: %w", addr, err)
 		}
+		defer s.serveTelegraf(ln)()
 	}
-	reg, err := providers.NewDNSProviderRegistry(s.cfg, providersList...)
+	if addr := s.cfg.Debug.ExpvarAddr; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error("Failed to start expvar server: %v", err)
+			return fmt.Errorf("failed to listen on debug.expvar_addr %s: %w", addr, err)
+		}
+		defer s.serveExpvar(ln)()
+	}
+	if addr := s.cfg.Debug.PprofAddr; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error("Failed to start pprof server: %v", err)
+			return fmt.Errorf("failed to listen on debug.pprof_addr %s: %w", addr, err)
+		}
+		defer s.serveProfiling(ln)()
+	}
+	if s.cfg.Metrics.StatsD.Enabled {
+		emitter, closeStatsD, err := newStatsDEmitter(s.cfg.Metrics.StatsD)
+		if err != nil {
+			logger.Error("Failed to start StatsD client: %v", err)
+			return fmt.Errorf("failed to set up StatsD client for metrics.statsd.addr: %w", err)
+		}
+		s.metrics.emitters = append(s.metrics.emitters, emitter)
+		defer closeStatsD()
+	}
+	if s.cfg.Metrics.DataDog.Enabled {
+		emitter, closeDataDog, err := newDataDogEmitter(s.cfg.Metrics.DataDog)
+		if err != nil {
+			logger.Error("Failed to start DogStatsD client: %v", err)
+			return fmt.Errorf("failed to set up DogStatsD client for metrics.datadog.addr: %w", err)
+		}
+		s.metrics.emitters = append(s.metrics.emitters, emitter)
+		defer closeDataDog()
+	}
+	if s.cfg.Metrics.InfluxDB.URL != "" {
+		influx, closeInflux := newInfluxWriter(s.cfg.Metrics.InfluxDB)
+		s.metrics.influx = influx
+		defer closeInflux()
+	}
+	if path := s.cfg.AuditLog; path != "" {
+		audit, err := logger.NewAuditLogger(path)
+		if err != nil {
+			logger.Error("Failed to open audit log: %v", err)
+			return fmt.Errorf("failed to open audit_log %s: %w", path, err)
+		}
+		s.audit = audit
+		defer audit.Close()
+	}
+	if path := s.cfg.HistoryDB; path != "" {
+		history, err := store.NewHistoryStore(path)
+		if err != nil {
+			logger.Error("Failed to open history database: %v", err)
+			return fmt.Errorf("failed to open history_db %s: %w", path, err)
+		}
+		s.history = history
+		defer history.Close()
+	}
+	var gw *pusher
+	if s.cfg.Metrics.Pushgateway.URL != "" {
+		gw = newPusher(s.cfg.Metrics.Pushgateway, s.metrics)
+	}
+	stopTelemetry, err := s.startTelemetry(s.cfg.Otel)
 	if err != nil {
-		logger.Error("No DNS providers enabled: %v", err)
-		return fmt.Errorf("failed to create DNS provider registry: %w", err)
+		logger.Error("Failed to start OpenTelemetry export: %v", err)
+		return fmt.Errorf("failed to set up OpenTelemetry export: %w", err)
+	}
+	defer stopTelemetry()
+
+	if s.cfg.RunOnStart {
+		if err := s.healthCheck(reg.Providers); err != nil {
+			return err
+		}
+	}
+	s.warnExpiringCredentials(reg.Providers, time.Now())
+	if s.cfg.ValidateCredentials {
+		if err := s.selfTest(reg.Providers); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.Once {
+		err := s.checkAndUpdate(reg)
+		gw.pushCycle(s.ctx, err)
+		return err
+	}
+
+	s.registry.Store(reg)
+	if addr := s.cfg.Probes.Addr; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			logger.Error("Failed to start probe server: %v", err)
+			return fmt.Errorf("failed to listen on probes.addr %s: %w", addr, err)
+		}
+		defer s.serveProbes(ln)()
 	}
 
-	interval := s.cfg.Interval
-	ipSource := s.cfg.IPSource
-	lastKnown := make(map[string]string) // providerName -> last IP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 
-	ticker := time.NewTicker(interval)
+	ticker := time.NewTicker(s.cfg.Interval)
 	defer ticker.Stop()
 
+	s.started.Store(true)
+	if s.cfg.RunOnStart {
+		gw.pushCycle(s.ctx, s.checkAndUpdate(reg))
+	}
 	for {
 		select {
 		case <-s.ctx.Done():
+			gw.shutdown()
 			logger.Info("DNSUpdateService stopped")
 			return nil
+		case <-hup:
+			if newReg, ok := s.reload(); ok {
+				closeProviders(removedProviders(reg.Providers, newReg.Providers))
+				reg = newReg
+				s.registry.Store(reg)
+				ticker.Reset(s.cfg.Interval)
+			}
 		case <-ticker.C:
-			currentIP, err := utils.GetCurrentIP(ipSource)
-			if err != nil {
-				logger.Error("Failed to get current IP: %v", err)
-				continue
+			gw.pushCycle(s.ctx, s.checkAndUpdate(reg))
+		}
+	}
+}
+
+// reload re-reads the config file the service was started with and rebuilds the provider registry.
+//
+// The swap is atomic: if the file cannot be loaded or enables no providers, the current config and
+// registry stay active and a warning is logged. Command-line overrides (dry-run, once, force, provider) are carried over.
+//
+// Returns the new registry and true on success.
+func (s *DNSUpdateService) reload() (*providers.DNSProviderRegistry, bool) {
+	if s.cfg.Path == "" {
+		logger.Warn("Received SIGHUP but the config was not loaded from a file; ignoring")
+		return nil, false
+	}
+	logger.Info("Received SIGHUP, reloading configuration from %s", s.cfg.Path)
+	cfg, err := config.LoadConfig(s.cfg.Path, config.WithAllowShortInterval(s.cfg.AllowShortInterval))
+	if err != nil {
+		logger.Warn("Config reload failed, keeping current configuration: %v", err)
+		return nil, false
+	}
+	cfg.OnlyProviders = s.cfg.OnlyProviders
+	providersList, err := s.providersFor(cfg)
+	if err != nil {
+		logger.Warn("Config reload failed, keeping current configuration: %v", err)
+		return nil, false
+	}
+	reg, err := providers.NewDNSProviderRegistry(cfg, providersList...)
+	if err != nil {
+		logger.Warn("Config reload failed, keeping current configuration: %v", err)
+		return nil, false
+	}
+	setProviderLoggers(reg.Providers)
+	cfg.DryRun = cfg.DryRun || s.cfg.DryRun
+	cfg.Once = s.cfg.Once
+	cfg.ForceUpdate = s.cfg.ForceUpdate
+	s.cfg = cfg
+	s.pending = make(map[string]pendingIP)
+	logger.Info("Configuration reloaded: %d provider(s), interval %s", len(reg.Providers), cfg.Interval)
+	if s.onReload != nil {
+		s.onReload(cfg)
+	}
+	return reg, true
+}
+
+// selfTest runs SelfTest on every provider, each bounded by SelfTestTimeout.
+//
+// Returns an error naming the first provider that fails.
+func (s *DNSUpdateService) selfTest(providersList []providers.DNSProvider) error {
+	for _, p := range providersList {
+		ctx, cancel := context.WithTimeout(s.ctx, SelfTestTimeout)
+		err := p.SelfTest(ctx)
+		cancel()
+		if err != nil {
+			logger.Error("%s: credential validation failed: %v", p.ProviderName(), err)
+			return fmt.Errorf("%s: credential validation failed: %w", p.ProviderName(), err)
+		}
+		logger.Info("%s: credentials validated", p.ProviderName())
+	}
+	return nil
+}
+
+// setProviderLoggers gives each provider implementing providers.LoggerSetter a logger that tags its
+// entries with the provider's name.
+func setProviderLoggers(ps []providers.DNSProvider) {
+	for _, p := range ps {
+		if ls, ok := p.(providers.LoggerSetter); ok {
+			l := logger.WithProvider(p.ProviderName())
+			ls.SetLogger(&l)
+		}
+	}
+}
+
+// closeProviders closes every provider in the list, logging any failures.
+func closeProviders(providersList []providers.DNSProvider) {
+	for _, p := range providersList {
+		if err := p.Close(); err != nil {
+			logger.Warn("%s: failed to close provider: %v", p.ProviderName(), err)
+		}
+	}
+}
+
+// removedProviders returns the providers in old that are not in current.
+func removedProviders(old, current []providers.DNSProvider) []providers.DNSProvider {
+	var removed []providers.DNSProvider
+	for _, p := range old {
+		if !slices.Contains(current, p) {
+			removed = append(removed, p)
+		}
+	}
+	return removed
+}
+
+// healthCheck runs HealthCheck on every provider, each bounded by SelfTestTimeout.
+//
+// Returns an error naming the first provider that fails.
+func (s *DNSUpdateService) healthCheck(providersList []providers.DNSProvider) error {
+	for _, p := range providersList {
+		ctx, cancel := context.WithTimeout(s.ctx, SelfTestTimeout)
+		err := p.HealthCheck(ctx)
+		cancel()
+		if err != nil {
+			logger.Error("%s: health check failed: %v", p.ProviderName(), err)
+			return fmt.Errorf("%s: health check failed: %w", p.ProviderName(), err)
+		}
+		logger.Debug("%s: health check passed", p.ProviderName())
+	}
+	return nil
+}
+
+// warnExpiringCredentials logs a warning for every provider implementing
+// providers.ExpiringCredentials whose credentials expire before the next cycle, one interval after now.
+func (s *DNSUpdateService) warnExpiringCredentials(providersList []providers.DNSProvider, now time.Time) {
+	next := now.Add(s.cfg.Interval)
+	for _, p := range providersList {
+		ec, ok := p.(providers.ExpiringCredentials)
+		if !ok {
+			continue
+		}
+		expiry, ok := ec.CredentialsExpiry()
+		switch {
+		case !ok || !expiry.Before(next):
+		case !expiry.After(now):
+			logger.Warn("%s: credentials expired at %s; updates will fail until they are renewed", p.ProviderName(), expiry.Format(time.RFC3339))
+		default:
+			logger.Warn("%s: credentials expire at %s, before the next update at about %s", p.ProviderName(), expiry.Format(time.RFC3339), next.Format(time.RFC3339))
+		}
+	}
+}
+
+// checkAndUpdate performs a single check-and-update cycle: it fetches the current public IP
+// and reconciles every provider's DNS record against it.
+//
+// With ip_source_v6 set, the public IPv6 address is fetched too, for AAAA records. The cycle only
+// stops early if no address could be fetched at all; records whose address is unavailable fail.
+//
+// The cycle is traced as an UpdateCycle span, parenting the spans of the IP lookups and provider calls.
+// Each cycle gets a fresh correlation ID, logged as the correlation_id field of every line it writes.
+//
+// Returns the first error encountered, or nil if every provider succeeded, which also marks the
+// service ready for /readyz.
+func (s *DNSUpdateService) checkAndUpdate(reg *providers.DNSProviderRegistry) (err error) {
+	ctx, end := startSpan(logger.ContextWithCorrelationID(s.ctx, logger.NewCorrelationID()), "UpdateCycle", "")
+	defer func() { end(err) }()
+	log := logger.WithCorrelationID(ctx)
+	currentIP, err := s.currentIP(ctx)
+	if err != nil {
+		log.Error().Msgf("Failed to get current IP: %v", err)
+		if s.cfg.IPSourceV6 == "" {
+			return fmt.Errorf("failed to get current IP: %w", err)
+		}
+	}
+	var currentIPv6 string
+	if s.cfg.IPSourceV6 != "" {
+		if currentIPv6, err = s.lookupIP(ctx, []string{s.cfg.IPSourceV6}); err != nil {
+			log.Error().Msgf("Failed to get current IPv6 address: %v", err)
+			if currentIP == "" {
+				return fmt.Errorf("failed to get current IP: %w", err)
 			}
-			for _, p := range reg.Providers {
-				providerName := p.ProviderName()
-				dnsIP, err := p.GetRecordIP()
-				if err != nil {
-					logger.Error("%s: failed to get DNS record IP: %v", providerName, err)
-					continue
-				}
-				if dnsIP != currentIP {
-					logger.Info("%s: IP mismatch (current: %s, DNS: %s), updating...", providerName, currentIP, dnsIP)
-					if err := p.UpdateRecordIP(currentIP); err != nil {
-						logger.Error("%s: failed to update DNS record: %v", providerName, err)
-						continue
-					}
-					lastKnown[providerName] = currentIP
-					logger.Info("%s: DNS record updated to %s", providerName, currentIP)
-				} else {
-					logger.Debug("%s: IP unchanged (%s)", providerName, currentIP)
-				}
+		}
+	}
+	err = s.runCycle(ctx, reg, currentIP, currentIPv6)
+	s.publishStatus(currentIP, currentIPv6)
+	if err != nil {
+		return err
+	}
+	s.ready.Store(true)
+	return nil
+}
+
+// currentIP looks up the public IP from every configured source using IPSourceFunc.
+func (s *DNSUpdateService) currentIP(ctx context.Context) (string, error) {
+	return s.lookupIP(ctx, s.cfg.AllIPSources())
+}
+
+// lookupIP fetches the public IP from sources using IPSourceFunc, or utils.GetCurrentIP if it is nil.
+// A result that is not a bare IP address is rejected (see utils.ParseIP).
+// The time taken is recorded in dynago_ip_fetch_duration_seconds and in the latency stats served on
+// /status, and the lookup is traced as a GetCurrentIP span under ctx.
+func (s *DNSUpdateService) lookupIP(ctx context.Context, sources []string) (ip string, err error) {
+	_, end := startSpan(ctx, "GetCurrentIP", "")
+	defer func(start time.Time) {
+		s.metrics.observeIPFetch(start)
+		s.latency.Record(time.Since(start))
+		end(err)
+	}(time.Now())
+	if s.IPSourceFunc != nil {
+		if ip, err = s.IPSourceFunc(sources); err != nil {
+			return "", err
+		}
+		return utils.ParseIP(ip)
+	}
+	return utils.GetCurrentIP(ctx, sources...)
+}
+
+// runCycle compares the DNS record of each provider against currentIP and updates it on mismatch.
+// AAAA records are compared against currentIPv6 instead (see reconcileFamilies).
+//
+// When DryRun is enabled in config, planned updates are logged but UpdateRecordIP is never called.
+// Providers are reconciled in parallel through DNSProviderRegistry.UpdateAllFunc, each bounded by
+// ProviderTimeout, so a slow provider does not hold up the others. A failing provider never cancels the rest; the first error in provider order
+// is returned. Providers whose circuit breaker is open are skipped without being called.
+// Each provider's outcome, duration, and breaker state are recorded in the metrics. ctx carries the
+// cycle's span, if any, to the provider calls.
+func (s *DNSUpdateService) runCycle(ctx context.Context, reg *providers.DNSProviderRegistry, currentIP, currentIPv6 string) error {
+	timeout := s.cfg.ProviderTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultProviderTimeout
+	}
+	results := reg.UpdateAllFunc(ctx, currentIP, func(ctx context.Context, p providers.DNSProvider, currentIP string) error {
+		cb := reg.Breaker(p)
+		start := time.Now()
+		defer func() { s.metrics.observeBreaker(p.ProviderName(), cb.State() == breaker.Open) }()
+		if !cb.Allow() {
+			log := providerLog(ctx, p.ProviderName())
+			log.Warn().Msgf("%s: circuit breaker open, skipping this cycle", p.ProviderName())
+			s.metrics.observeUpdate(p.ProviderName(), statusSkipped, start)
+			return fmt.Errorf("%s: circuit breaker open", p.ProviderName())
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err := s.reconcileFamilies(ctx, p, currentIP, currentIPv6); err != nil {
+			cb.RecordFailure()
+			s.metrics.observeUpdate(p.ProviderName(), statusError, start)
+			return err
+		}
+		cb.RecordSuccess()
+		s.metrics.observeUpdate(p.ProviderName(), statusSuccess, start)
+		return nil
+	})
+	for _, p := range reg.Providers {
+		if err := results[p.ProviderName()]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileFamilies reconciles p against currentIP, unless it implements providers.AddressFamilies.
+//
+// Such providers have their AAAA records reconciled against currentIPv6 and any other records
+// against currentIP. A provider managing several record types is reconciled once per type through
+// ForRecordType, so each type is debounced and reported separately.
+//
+// Returns the errors of every failed reconciliation joined together.
+func (s *DNSUpdateService) reconcileFamilies(ctx context.Context, p providers.DNSProvider, currentIP, currentIPv6 string) error {
+	af, ok := p.(providers.AddressFamilies)
+	if !ok {
+		return s.reconcileIP(ctx, p, "A", currentIP, currentIPv6)
+	}
+	types := af.RecordTypes()
+	if len(types) == 1 {
+		return s.reconcileIP(ctx, p, types[0], currentIP, currentIPv6)
+	}
+	var errs []error
+	for _, recordType := range types {
+		errs = append(errs, s.reconcileIP(ctx, af.ForRecordType(recordType), recordType, currentIP, currentIPv6))
+	}
+	return errors.Join(errs...)
+}
+
+// reconcileIP reconciles p against currentIPv6 if recordType is AAAA and against currentIP
+// otherwise, failing without calling p if that address is unavailable.
+//
+// Without ip_source_v6, AAAA records are reconciled against currentIP if it is an IPv6 address,
+// as they were before ip_source_v6 existed.
+func (s *DNSUpdateService) reconcileIP(ctx context.Context, p providers.DNSProvider, recordType, currentIP, currentIPv6 string) error {
+	ip, family := currentIP, "IPv4"
+	if recordType == "AAAA" {
+		ip, family = currentIPv6, "IPv6"
+		if s.cfg.IPSourceV6 == "" && isIPv6(currentIP) {
+			ip = currentIP
+		}
+	}
+	if ip != "" {
+		return s.reconcile(ctx, p, ip)
+	}
+	err := fmt.Errorf("%s: no public %s address for %s records", p.ProviderName(), family, recordType)
+	if family == "IPv6" && s.cfg.IPSourceV6 == "" {
+		err = fmt.Errorf("%s: %s records need ip_source_v6 to be set", p.ProviderName(), recordType)
+	}
+	s.recordError(p.ProviderName(), err)
+	log := providerLog(ctx, p.ProviderName())
+	log.Error().Msgf("%v", err)
+	return err
+}
+
+// isIPv6 reports whether ip is an IPv6 address.
+func isIPv6(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	return err == nil && addr.Is6() && !addr.Is4In6()
+}
+
+// reconcile brings a single provider's DNS record in line with currentIP.
+//
+// With DebounceCount > 1, a new IP must be observed on that many consecutive cycles before the record is updated.
+// With ForceUpdate, the record is not read and is always set to currentIP.
+// A record the provider reports as not found is set to currentIP without debouncing, so providers
+// that create missing records can do so.
+// pre_update_hook runs before the update and post_update_hook after a successful one.
+// Providers implementing providers.StaticTarget are compared against and set to their target
+// instead of currentIP.
+// ctx bounds the update, including retries.
+// Unchanged and updated records are written to InfluxDB; pending debounces and failures are not.
+// Applied updates are also appended to the audit log and the history database.
+func (s *DNSUpdateService) reconcile(ctx context.Context, p providers.DNSProvider, currentIP string) error {
+	providerName := p.ProviderName()
+	log := providerLog(ctx, providerName)
+	start := time.Now()
+	if st, ok := p.(providers.StaticTarget); ok {
+		if target, ok := st.StaticTarget(); ok {
+			currentIP = target
+		}
+	}
+	var dnsIP, prefix string
+	if s.cfg.ForceUpdate {
+		prefix = "[forced] "
+		log.Info().Msgf("[forced] %s: skipping DNS record check, updating to %s...", providerName, currentIP)
+	} else if record, err := s.getRecord(ctx, p); isNotFound(err) {
+		log.Info().Msgf("%s: DNS record does not exist yet, setting it to %s...", providerName, currentIP)
+	} else {
+		if err != nil {
+			s.recordError(providerName, err)
+			logProviderError(ctx, providerName, err)
+			log.Error().Msgf("%s: failed to get DNS record IP: %v", providerName, err)
+			return fmt.Errorf("%s: failed to get DNS record IP: %w", providerName, err)
+		}
+		dnsIP = record.Content
+		log.Debug().Msgf("%s: DNS record %s holds %s (TTL %ds)", providerName, record.Name, dnsIP, record.TTL)
+		if dnsIP == currentIP {
+			s.clearPending(providerName)
+			s.recordSuccess(providerName, dnsIP, false)
+			s.recordHistory(p, dnsIP, dnsIP, false, start)
+			observeExpvarIP(providerName, dnsIP)
+			log.Debug().Msgf("%s: IP unchanged (%s)", providerName, currentIP)
+			return nil
+		}
+		if count, confirmed := s.observeIP(providerName, currentIP); !confirmed {
+			log.Info().Msgf("%s: new IP %s observed %d/%d times, waiting before updating", providerName, currentIP, count, s.cfg.DebounceCount)
+			s.recordSuccess(providerName, dnsIP, false)
+			return nil
+		}
+		log.Info().Msgf("%s: IP mismatch (current: %s, DNS: %s), updating...", providerName, currentIP, dnsIP)
+	}
+
+	s.runHook(ctx, "pre_update_hook", s.cfg.PreUpdateHook, providerName, dnsIP, currentIP)
+	err := NewRetryPolicy(s.cfg.RetryPolicy).Do(ctx, func() error {
+		return s.updateRecord(ctx, p, currentIP, dnsIP)
+	}, func(attempt int, err error, delay time.Duration) {
+		log.Warn().Msgf("%s%s: update attempt %d failed: %v (retrying in %s)", prefix, providerName, attempt, err, delay)
+	})
+	if !s.cfg.DryRun {
+		s.emit(p, dnsIP, currentIP, err)
+	}
+	if err != nil {
+		s.recordError(providerName, err)
+		logProviderError(ctx, providerName, err)
+		var multi *providers.MultiError
+		if errors.As(err, &multi) {
+			for _, recErr := range multi.Errors {
+				log.Error().Msgf("%s: record update failed: %v", providerName, recErr)
 			}
 		}
+		log.Error().Msgf("%s%s: failed to update DNS record: %v", prefix, providerName, err)
+		return fmt.Errorf("%s: failed to update DNS record: %w", providerName, err)
+	}
+	s.clearPending(providerName)
+	if s.cfg.DryRun {
+		s.recordSuccess(providerName, dnsIP, false)
+	} else {
+		s.recordSuccess(providerName, currentIP, true)
 	}
+	if !s.cfg.DryRun {
+		s.metrics.observeIPChange(providerName, dnsIP, currentIP)
+		observeExpvarIP(providerName, currentIP)
+		s.recordChange(ctx, p, dnsIP, currentIP)
+	}
+	s.recordHistory(p, dnsIP, currentIP, true, start)
+	log.Info().Msgf("%s%s: DNS record updated to %s", prefix, providerName, currentIP)
+	s.runHook(ctx, "post_update_hook", s.cfg.PostUpdateHook, providerName, dnsIP, currentIP)
+	return nil
 }
 
+// recordChange appends the change of p's record from oldIP to newIP to the audit log and the
+// history database, if audit_log and history_db are set. A failed write is logged but does not
+// fail the update, which has already been applied.
+func (s *DNSUpdateService) recordChange(ctx context.Context, p providers.DNSProvider, oldIP, newIP string) {
+	if s.audit == nil && s.history == nil {
+		return
+	}
+	providerName, record, now := p.ProviderName(), recordName(p), time.Now().UTC()
+	log := providerLog(ctx, providerName)
+	if s.audit != nil {
+		err := s.audit.Append(logger.AuditEntry{
+			Time:     now.Format(time.RFC3339),
+			Provider: providerName,
+			Record:   record,
+			OldIP:    oldIP,
+			NewIP:    newIP,
+		})
+		if err != nil {
+			log.Error().Msgf("%s: failed to write audit log: %v", providerName, err)
+		}
+	}
+	if s.history != nil {
+		err := s.history.Insert(store.HistoryEntry{Time: now, Provider: providerName, Record: record, OldIP: oldIP, NewIP: newIP})
+		if err != nil {
+			log.Error().Msgf("%s: failed to record history: %v", providerName, err)
+		}
+	}
+}
 
+// isNotFound reports whether err matches providers.ErrRecordNotFound.
+func isNotFound(err error) bool {
+	return errors.Is(err, providers.ErrRecordNotFound)
+}
+
+// logProviderError emits an extra, actionable log line for classified provider errors.
+func logProviderError(ctx context.Context, providerName string, err error) {
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) {
+		return
+	}
+	log := providerLog(ctx, providerName)
+	switch {
+	case pe.Unauthorized:
+		log.Error().Msgf("%s: credentials were rejected; check the provider's API token or keys", providerName)
+	case pe.RateLimited:
+		log.Warn().Msgf("%s: rate limited by provider API, backing off until the next cycle", providerName)
+	}
+}
+
+// providerLog returns a logger for the named provider whose entries carry both the provider field
+// (see logger.WithProvider) and the correlation ID of the cycle in ctx, if any.
+func providerLog(ctx context.Context, providerName string) zerolog.Logger {
+	log := logger.WithCorrelationID(ctx)
+	return log.With().Str("provider", providerName).Logger()
+}
+
+// observeIP records that ip was seen for the named provider and reports whether it has now been
+// observed DebounceCount consecutive times. A different IP restarts the count.
+//
+// Returns the current consecutive count and whether the IP is confirmed.
+func (s *DNSUpdateService) observeIP(providerName, ip string) (int, bool) {
+	if s.cfg.DebounceCount <= 1 {
+		return 1, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obs := s.pending[providerName]
+	if obs.ip == ip {
+		obs.count++
+	} else {
+		obs = pendingIP{ip: ip, count: 1}
+	}
+	s.pending[providerName] = obs
+	return obs.count, obs.count >= s.cfg.DebounceCount
+}
+
+// clearPending forgets any IP awaiting debounce confirmation for the named provider.
+func (s *DNSUpdateService) clearPending(providerName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, providerName)
+}
+
+// getRecord reads the provider's DNS record, traced as a GetRecordIP span.
+func (s *DNSUpdateService) getRecord(ctx context.Context, p providers.DNSProvider) (record *providers.DNSRecord, err error) {
+	ctx, end := startSpan(ctx, "GetRecordIP", p.ProviderName())
+	defer func() { end(err) }()
+	return p.GetRecordIP(ctx)
+}
+
+// updateRecord sets the provider's DNS record to ip, or only logs the planned change in dry-run mode.
+// Real updates are traced as an UpdateRecordIP span.
+func (s *DNSUpdateService) updateRecord(ctx context.Context, p providers.DNSProvider, ip, oldIP string) (err error) {
+	if s.cfg.DryRun {
+		log := providerLog(ctx, p.ProviderName())
+		if oldIP == "" {
+			log.Info().Msgf("[dry-run] %s: would update DNS record to %s", p.ProviderName(), ip)
+			return nil
+		}
+		log.Info().Msgf("[dry-run] %s: would update DNS record from %s to %s", p.ProviderName(), oldIP, ip)
+		return nil
+	}
+	ctx, end := startSpan(ctx, "UpdateRecordIP", p.ProviderName())
+	defer func() { end(err) }()
+	return p.UpdateRecordIP(ctx, ip)
+}
@@ -20,38 +20,728 @@ package service
 
 import (
 	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/aaronlmathis/dynago/internal/store"
+	providers "github.com/aaronlmathis/dynago/providers"
 )
 
 type mockProvider struct {
-	name      string
-	getIP     string
-	updatedIP string
-	getErr    error
-	updateErr error
+	name        string
+	getIP       string
+	updatedIP   string
+	getErr      error
+	updateErr   error
+	getCalls    int
+	delay       time.Duration // Simulated latency for GetRecordIP
+	updateCalls int
+	selfTestErr error
+	healthErr   error
+	closed      bool
 }
 
-func (m *mockProvider) GetRecordIP() (string, error)   { return m.getIP, m.getErr }
-func (m *mockProvider) UpdateRecordIP(ip string) error { m.updatedIP = ip; return m.updateErr }
-func (m *mockProvider) ProviderName() string           { return m.name }
+func (m *mockProvider) GetRecordIP(ctx context.Context) (*providers.DNSRecord, error) {
+	m.getCalls++
+	time.Sleep(m.delay)
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	return &providers.DNSRecord{Name: m.name + ".example.com", Content: m.getIP, TTL: 300, Provider: m.name}, nil
+}
+func (m *mockProvider) UpdateRecordIP(ctx context.Context, ip string) error {
+	m.updateCalls++
+	m.updatedIP = ip
+	return m.updateErr
+}
+func (m *mockProvider) ProviderName() string { return m.name }
+func (m *mockProvider) GetRecordTTL(ctx context.Context) (int64, error) {
+	return 300, nil
+}
+func (m *mockProvider) DeleteRecord(ctx context.Context, name, recordType string) error {
+	return nil
+}
+func (m *mockProvider) ListManagedRecords(ctx context.Context) ([]providers.DNSRecord, error) {
+	return nil, nil
+}
+func (m *mockProvider) SelfTest(ctx context.Context) error { return m.selfTestErr }
+
+func (m *mockProvider) HealthCheck(ctx context.Context) error { return m.healthErr }
+
+func (m *mockProvider) Close() error {
+	m.closed = true
+	return nil
+}
+
+func (m *mockProvider) ProviderConfig() map[string]string { return map[string]string{"name": m.name} }
+
+// newTestRegistry wraps the given providers in a registry with fresh circuit breakers.
+func newTestRegistry(t *testing.T, ps ...providers.DNSProvider) *providers.DNSProviderRegistry {
+	t.Helper()
+	reg, err := providers.NewDNSProviderRegistry(nil, ps...)
+	if err != nil {
+		t.Fatalf("NewDNSProviderRegistry: %v", err)
+	}
+	return reg
+}
 
 func TestDNSUpdateService_Start(t *testing.T) {
-	cfg := &config.Config{Interval: 10 * time.Millisecond, IPSource: "mock", LogLevel: "debug"}
+	cfg := &config.Config{Interval: 10 * time.Millisecond, IPSource: "mock", LogLevel: "debug", Once: true}
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+	var gotSources []string
+	ipSource := func(sources []string) (string, error) {
+		gotSources = sources
+		return "5.6.7.8", nil
+	}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(mockProv), WithIPSourceFunc(ipSource))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotSources) != 1 || gotSources[0] != "mock" {
+		t.Errorf("expected IP source func to receive [mock], got %v", gotSources)
+	}
+	if mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected UpdateRecordIP to be called with 5.6.7.8, got %q", mockProv.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_StartUsesInjectedProviders(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", ValidateCredentials: true}
+	bad := &mockProvider{name: "injected", selfTestErr: errors.New("invalid token")}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(bad))
+
+	err := service.Start()
+	if err == nil || !strings.Contains(err.Error(), "injected") {
+		t.Errorf("expected the injected provider's self-test to fail Start, got %v", err)
+	}
+}
+
+func TestDNSUpdateService_StartExitsWithoutProviders(t *testing.T) {
+	var codes []int
+	logger.SetExitFunc(func(code int) { codes = append(codes, code) })
+	t.Cleanup(func() { logger.SetExitFunc(nil) })
+
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	service := NewDNSUpdateService(context.Background(), cfg)
+
+	if err := service.Start(); err == nil {
+		t.Error("expected Start to fail without any enabled providers")
+	}
+	if len(codes) != 1 || codes[0] != 1 {
+		t.Errorf("expected a single exit with status 1, got %v", codes)
+	}
+}
+
+func TestFilterProviders(t *testing.T) {
+	cf := &mockProvider{name: "cloudflare"}
+	r53 := &mockProvider{name: "route53"}
+	all := []providers.DNSProvider{cf, r53}
+
+	got, err := FilterProviders(all, nil)
+	if err != nil || len(got) != 2 {
+		t.Errorf("expected all providers without a filter, got %d (err %v)", len(got), err)
+	}
+	got, err = FilterProviders(all, []string{"route53"})
+	if err != nil || len(got) != 1 || got[0].ProviderName() != "route53" {
+		t.Errorf("expected only route53, got %v (err %v)", got, err)
+	}
+	got, err = FilterProviders(all, []string{"Route53", "CLOUDFLARE"})
+	if err != nil || len(got) != 2 || got[0].ProviderName() != "cloudflare" {
+		t.Errorf("expected case-insensitive match in config order, got %v (err %v)", got, err)
+	}
+	if _, err := FilterProviders(all, []string{"route53", "gandi"}); err == nil || !strings.Contains(err.Error(), "gandi") {
+		t.Errorf("expected error naming the unknown provider, got %v", err)
+	}
+}
+
+func TestDNSUpdateService_StartOnlySelectedProviders(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true, OnlyProviders: []string{"b"}}
+	a := &mockProvider{name: "a", getIP: "1.2.3.4"}
+	b := &mockProvider{name: "b", getIP: "1.2.3.4"}
+	ipSource := func([]string) (string, error) { return "5.6.7.8", nil }
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(a, b), WithIPSourceFunc(ipSource))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.getCalls != 0 || b.updatedIP != "5.6.7.8" {
+		t.Errorf("expected only provider b to run, got a.getCalls=%d b.updatedIP=%q", a.getCalls, b.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_DryRunSkipsUpdate(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", DryRun: true}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+	logs := captureLogs(t)
+
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	if mockProv.updateCalls != 0 {
+		t.Errorf("expected UpdateRecordIP not to be called in dry-run mode, got %d calls", mockProv.updateCalls)
+	}
+	if want := "[dry-run] mock: would update DNS record from 1.2.3.4 to 5.6.7.8"; !strings.Contains(logs.String(), want) {
+		t.Errorf("expected the planned update to be logged, got %q", logs.String())
+	}
+}
+
+func TestDNSUpdateService_RunCycleUpdatesOnMismatch(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	if mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected UpdateRecordIP to be called with 5.6.7.8, got %q", mockProv.updatedIP)
+	}
+}
+
+// TestDNSUpdateService_AuditLog checks that applied updates are appended to audit_log, and that
+// unchanged records and dry runs are not.
+func TestDNSUpdateService_AuditLog(t *testing.T) {
+	auditLog := filepath.Join(t.TempDir(), "audit.csv")
+	for _, c := range []struct {
+		dnsIP  string
+		dryRun bool
+	}{
+		{"1.2.3.4", false},
+		{"5.6.7.8", false},
+		{"1.2.3.4", true},
+	} {
+		cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true, DryRun: c.dryRun, AuditLog: auditLog}
+		service := NewDNSUpdateService(context.Background(), cfg, WithProviders(&mockProvider{name: "mock", getIP: c.dnsIP}),
+			WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+		if err := service.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[0] != "timestamp,provider,record,old_ip,new_ip" {
+		t.Fatalf("expected a header and one row, got %q", data)
+	}
+	if !strings.HasSuffix(lines[1], ",mock,,1.2.3.4,5.6.7.8") {
+		t.Errorf("unexpected audit row %q", lines[1])
+	}
+}
+
+// TestDNSUpdateService_HistoryDB checks that applied updates are recorded in history_db.
+func TestDNSUpdateService_HistoryDB(t *testing.T) {
+	historyDB := filepath.Join(t.TempDir(), "history.db")
+	for _, dnsIP := range []string{"1.2.3.4", "5.6.7.8"} {
+		cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true, HistoryDB: historyDB}
+		service := NewDNSUpdateService(context.Background(), cfg, WithProviders(&mockProvider{name: "mock", getIP: dnsIP}),
+			WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+		if err := service.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+	}
+
+	history, err := store.NewHistoryStore(historyDB)
+	if err != nil {
+		t.Fatalf("NewHistoryStore failed: %v", err)
+	}
+	defer history.Close()
+	entries, err := history.QueryHistory("mock", time.Time{})
+	if err != nil {
+		t.Fatalf("QueryHistory failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].OldIP != "1.2.3.4" || entries[0].NewIP != "5.6.7.8" {
+		t.Errorf("expected the single applied change, got %+v", entries)
+	}
+}
+
+func TestDNSUpdateService_RunCycleReturnsProviderError(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	failing := &mockProvider{name: "failing", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+	healthy := &mockProvider{name: "healthy", getIP: "1.2.3.4"}
+
+	err := service.runCycle(context.Background(), newTestRegistry(t, failing, healthy), "5.6.7.8", "")
+
+	if err == nil {
+		t.Fatalf("expected error when a provider update fails")
+	}
+	if healthy.updatedIP != "5.6.7.8" {
+		t.Errorf("expected remaining providers to be updated after a failure")
+	}
+}
+
+func TestDNSUpdateService_SelfTestFailure(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", ValidateCredentials: true}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	ok := &mockProvider{name: "ok"}
+	bad := &mockProvider{name: "bad", selfTestErr: errors.New("invalid token")}
+
+	if err := service.selfTest([]providers.DNSProvider{ok}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := service.selfTest([]providers.DNSProvider{ok, bad}); err == nil {
+		t.Errorf("expected error when a provider fails its self-test")
+	}
+}
+
+func TestDNSUpdateService_StartClosesProviders(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+	service := NewDNSUpdateService(context.Background(), cfg,
+		WithProviders(mockProv),
+		WithIPSourceFunc(func([]string) (string, error) { return "1.2.3.4", nil }))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !mockProv.closed {
+		t.Errorf("expected Start to close providers on return")
+	}
+}
+
+func TestRemovedProviders(t *testing.T) {
+	a, b, c := &mockProvider{name: "a"}, &mockProvider{name: "b"}, &mockProvider{name: "c"}
+	removed := removedProviders([]providers.DNSProvider{a, b}, []providers.DNSProvider{b, c})
+	if len(removed) != 1 || removed[0] != providers.DNSProvider(a) {
+		t.Errorf("expected only a to be removed, got %v", removed)
+	}
+}
+
+func TestDNSUpdateService_HealthCheckFailure(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", RunOnStart: true}
+	bad := &mockProvider{name: "bad", healthErr: errors.New("zone not found")}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(bad))
+
+	err := service.Start()
+	if err == nil || !strings.Contains(err.Error(), "health check failed") {
+		t.Fatalf("expected health check error from Start, got %v", err)
+	}
+	if bad.getCalls != 0 || bad.updateCalls != 0 {
+		t.Errorf("expected no update cycle after a failed health check")
+	}
+}
+
+func TestDNSUpdateService_HealthCheckOnlyWithRunOnStart(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	bad := &mockProvider{name: "bad", getIP: "1.2.3.4", healthErr: errors.New("zone not found")}
+	service := NewDNSUpdateService(context.Background(), cfg,
+		WithProviders(bad),
+		WithIPSourceFunc(func([]string) (string, error) { return "1.2.3.4", nil }))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("expected no health check without run_on_start, got %v", err)
+	}
+}
+
+// TestDNSUpdateService_RunOnStart checks that run_on_start runs the first cycle before the first
+// tick of the interval.
+func TestDNSUpdateService_RunOnStart(t *testing.T) {
+	cfg := &config.Config{Interval: time.Hour, IPSource: "mock", RunOnStart: true}
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
 	ctx, cancel := context.WithCancel(context.Background())
-	service := NewDNSUpdateService(ctx, cfg)
-
-	// Patch provider registry and utils for test
-	mockProv := &mockProvider{name: "mock", getIP: "4.3.2.1"}
-	_ = mockProv
-	_ = service
-	// TODO: Refactor service for better testability (dependency injection)
-
-	// Simulate one tick then cancel
-	go func() {
-		time.Sleep(20 * time.Millisecond)
-		cancel()
-	}()
+	defer cancel()
+	fetched := make(chan struct{}, 1)
+	service := NewDNSUpdateService(ctx, cfg,
+		WithProviders(mockProv),
+		WithIPSourceFunc(func([]string) (string, error) {
+			fetched <- struct{}{}
+			return "5.6.7.8", nil
+		}))
+
+	done := make(chan error, 1)
+	go func() { done <- service.Start() }()
+	select {
+	case <-fetched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a cycle to run at startup")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected the startup cycle to update the record, got %q", mockProv.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_DebounceAlternatingIPs(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", DebounceCount: 3}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	for i := 0; i < 6; i++ {
+		ip := "5.6.7.8"
+		if i%2 == 1 {
+			ip = "9.9.9.9"
+		}
+		service.runCycle(context.Background(), newTestRegistry(t, mockProv), ip, "")
+	}
+
+	if mockProv.updateCalls != 0 {
+		t.Errorf("expected no update for alternating IPs, got %d calls", mockProv.updateCalls)
+	}
+}
+
+func TestDNSUpdateService_DebounceConfirmsStableIP(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", DebounceCount: 3}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4"}
+
+	for i := 0; i < 2; i++ {
+		service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+	}
+	if mockProv.updateCalls != 0 {
+		t.Fatalf("expected no update before %d readings, got %d calls", cfg.DebounceCount, mockProv.updateCalls)
+	}
+	service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+	if mockProv.updateCalls != 1 || mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected one update to 5.6.7.8 after %d readings, got %d calls (%q)", cfg.DebounceCount, mockProv.updateCalls, mockProv.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_MissingRecordIsSet(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", DebounceCount: 3}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getErr: &providers.ProviderError{Provider: "mock", Op: "get record", Err: errors.New("record not found"), NotFound: true}}
+
+	if err := service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockProv.updateCalls != 1 || mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected missing record to be set to 5.6.7.8 without debouncing, got %d calls (%q)", mockProv.updateCalls, mockProv.updatedIP)
+	}
+}
+
+// staticTargetProvider is a mockProvider whose record points at a fixed target.
+type staticTargetProvider struct {
+	*mockProvider
+	target string
+}
+
+func (p *staticTargetProvider) StaticTarget() (string, bool) { return p.target, true }
+
+// dualStackProvider manages an A record through a and an AAAA record through aaaa.
+type dualStackProvider struct {
+	*mockProvider
+	a, aaaa *mockProvider
+}
+
+func (p *dualStackProvider) RecordTypes() []string { return []string{"A", "AAAA"} }
+
+func (p *dualStackProvider) ForRecordType(recordType string) providers.DNSProvider {
+	if recordType == "AAAA" {
+		return p.aaaa
+	}
+	return p.a
+}
+
+func TestDNSUpdateService_DualStack(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", IPSourceV6: "mock6", Once: true}
+	a := &mockProvider{name: "dual/A", getIP: "1.2.3.4"}
+	aaaa := &mockProvider{name: "dual/AAAA", getIP: "2001:db8::1"}
+	p := &dualStackProvider{mockProvider: &mockProvider{name: "dual"}, a: a, aaaa: aaaa}
+	ipSource := func(sources []string) (string, error) {
+		if sources[0] == "mock6" {
+			return "2001:db8::2", nil
+		}
+		return "5.6.7.8", nil
+	}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(p), WithIPSourceFunc(ipSource))
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.updatedIP != "5.6.7.8" || aaaa.updatedIP != "2001:db8::2" {
+		t.Errorf("expected A set to 5.6.7.8 and AAAA to 2001:db8::2, got %q and %q", a.updatedIP, aaaa.updatedIP)
+	}
+	if stats := service.GetStats(); len(stats) != 2 {
+		t.Errorf("expected stats for each record type, got %v", stats)
+	}
+}
+
+// aaaaProvider is a mockProvider that manages only AAAA records.
+type aaaaProvider struct {
+	*mockProvider
+}
+
+func (p *aaaaProvider) RecordTypes() []string { return []string{"AAAA"} }
+
+func (p *aaaaProvider) ForRecordType(string) providers.DNSProvider { return p }
+
+// TestDNSUpdateService_AAAAFromIPv6Source checks that without ip_source_v6, an AAAA record is
+// reconciled against ip_source when it returns an IPv6 address.
+func TestDNSUpdateService_AAAAFromIPv6Source(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg, WithIPSourceFunc(func([]string) (string, error) {
+		return "2001:db8::2", nil
+	}))
+	aaaa := &mockProvider{name: "aaaa", getIP: "2001:db8::1"}
+
+	if err := service.checkAndUpdate(newTestRegistry(t, &aaaaProvider{aaaa})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aaaa.updatedIP != "2001:db8::2" {
+		t.Errorf("expected AAAA set to 2001:db8::2, got %q", aaaa.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_AAAAWithoutIPv6Source(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	a := &mockProvider{name: "dual/A", getIP: "1.2.3.4"}
+	aaaa := &mockProvider{name: "dual/AAAA", getIP: "2001:db8::1"}
+	p := &dualStackProvider{mockProvider: &mockProvider{name: "dual"}, a: a, aaaa: aaaa}
+
+	err := service.runCycle(context.Background(), newTestRegistry(t, p), "5.6.7.8", "")
+	if err == nil || !strings.Contains(err.Error(), "ip_source_v6") {
+		t.Errorf("expected an error asking for ip_source_v6, got %v", err)
+	}
+	if a.updatedIP != "5.6.7.8" {
+		t.Errorf("expected the A record to be updated regardless, got %q", a.updatedIP)
+	}
+	if aaaa.getCalls != 0 || aaaa.updateCalls != 0 {
+		t.Errorf("expected the AAAA record not to be touched without an IPv6 address")
+	}
+}
+
+// expiringProvider is a mockProvider whose credentials expire at expiry.
+type expiringProvider struct {
+	*mockProvider
+	expiry time.Time
+	calls  int
+}
+
+func (p *expiringProvider) CredentialsExpiry() (time.Time, bool) {
+	p.calls++
+	return p.expiry, true
+}
+
+func TestDNSUpdateService_WarnExpiringCredentials(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", Once: true}
+	p := &expiringProvider{mockProvider: &mockProvider{name: "mock", getIP: "5.6.7.8"}, expiry: time.Now().Add(30 * time.Second)}
+	service := NewDNSUpdateService(context.Background(), cfg, WithProviders(p),
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+	logs := captureLogs(t)
+
+	if err := service.Start(); err != nil {
+		t.Fatalf("expected expiring credentials to only warn, got %v", err)
+	}
+	if p.calls != 1 {
+		t.Errorf("expected credentials expiry to be checked once at startup, got %d", p.calls)
+	}
+	if !strings.Contains(logs.String(), "mock: credentials expire at") {
+		t.Errorf("expected a warning about the expiring credentials, got %q", logs.String())
+	}
+}
+
+func TestDNSUpdateService_StaticTarget(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "old-lb.example.com"}
+	p := &staticTargetProvider{mockProvider: mockProv, target: "new-lb.example.com"}
+
+	service.runCycle(context.Background(), newTestRegistry(t, p), "5.6.7.8", "")
+	if mockProv.updateCalls != 1 || mockProv.updatedIP != "new-lb.example.com" {
+		t.Fatalf("expected record to be set to its static target, got %d calls (%q)", mockProv.updateCalls, mockProv.updatedIP)
+	}
+	mockProv.getIP = "new-lb.example.com"
+	service.runCycle(context.Background(), newTestRegistry(t, p), "5.6.7.8", "")
+	if mockProv.updateCalls != 1 {
+		t.Errorf("expected no update once the record holds its target, got %d calls", mockProv.updateCalls)
+	}
+}
+
+func TestDNSUpdateService_CircuitBreakerSkipsFailingProvider(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	healthy := &mockProvider{name: "healthy", getIP: "5.6.7.8"}
+	reg := newTestRegistry(t, failing, healthy)
+	reg.Breaker(failing).FailureThreshold = 2
+
+	for i := 0; i < 4; i++ {
+		service.runCycle(context.Background(), reg, "5.6.7.8", "")
+	}
+
+	if failing.getCalls != 2 {
+		t.Errorf("expected failing provider to be called 2 times before the breaker opened, got %d", failing.getCalls)
+	}
+	if healthy.getCalls != 4 {
+		t.Errorf("expected healthy provider to be called every cycle, got %d", healthy.getCalls)
+	}
+}
+
+func TestDNSUpdateService_RunCycleUpdatesProvidersInParallel(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", ProviderTimeout: time.Second}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	a := &mockProvider{name: "a", getIP: "1.2.3.4", delay: 100 * time.Millisecond}
+	b := &mockProvider{name: "b", getIP: "1.2.3.4", delay: 100 * time.Millisecond}
+
+	start := time.Now()
+	if err := service.runCycle(context.Background(), newTestRegistry(t, a, b), "5.6.7.8", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 180*time.Millisecond {
+		t.Errorf("expected providers to run in parallel, cycle took %s", elapsed)
+	}
+	if a.updatedIP != "5.6.7.8" || b.updatedIP != "5.6.7.8" {
+		t.Errorf("expected both providers to be updated, got %q and %q", a.updatedIP, b.updatedIP)
+	}
+}
+
+func TestDNSUpdateService_RunCycleReportsPartialFailure(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	multi := &providers.MultiError{Errors: []error{errors.New("b.example.com: boom")}, Total: 2}
+	mockProv := &mockProvider{name: "mock", getIP: "1.2.3.4", updateErr: multi}
+
+	err := service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", "")
+
+	var got *providers.MultiError
+	if !errors.As(err, &got) || len(got.Errors) != 1 {
+		t.Errorf("expected the provider's MultiError to be returned, got %v", err)
+	}
+}
+
+func TestDNSUpdateService_ForceUpdateSkipsGetRecordIP(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock", ForceUpdate: true, DebounceCount: 3}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	mockProv := &mockProvider{name: "mock", getIP: "5.6.7.8"}
+
+	if err := service.runCycle(context.Background(), newTestRegistry(t, mockProv), "5.6.7.8", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mockProv.getCalls != 0 {
+		t.Errorf("expected GetRecordIP not to be called in force mode, got %d calls", mockProv.getCalls)
+	}
+	if mockProv.updateCalls != 1 || mockProv.updatedIP != "5.6.7.8" {
+		t.Errorf("expected one forced update to 5.6.7.8, got %d calls (%q)", mockProv.updateCalls, mockProv.updatedIP)
+	}
+}
+
+// reloadTestConfig returns a minimal config file body with the given interval.
+// The probes listen on a free port.
+func reloadTestConfig(interval string) string {
+	return `
+interval: ` + interval + `
+ip_source: "https://api.ipify.org"
+probes:
+  addr: "127.0.0.1:0"
+providers:
+  cloudflare:
+    enabled: true
+    api_token: "token"
+    zone_id: "zone"
+    record_name: "home.example.com"
+    record_type: "A"
+`
+}
+
+func TestDNSUpdateService_ReloadKeepsShortIntervalAllowance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte(reloadTestConfig("10s")), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(path, config.WithAllowShortInterval(true))
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	if err := os.WriteFile(path, []byte(reloadTestConfig("20s")), 0600); err != nil {
+		t.Fatalf("failed to rewrite config: %v", err)
+	}
+
+	if _, ok := service.reload(); !ok {
+		t.Fatal("expected the reload to accept a short interval")
+	}
+	if service.cfg.Interval != 20*time.Second || !service.cfg.AllowShortInterval {
+		t.Errorf("expected interval 20s with the allowance kept, got %s (allow %v)", service.cfg.Interval, service.cfg.AllowShortInterval)
+	}
+}
+
+func TestDNSUpdateService_ReloadOnSIGHUP(t *testing.T) {
+	// Keep SIGHUP from terminating the test binary before the service registers its handler.
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte(reloadTestConfig("1h")), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewDNSUpdateService(ctx, cfg, WithProviders(&mockProvider{name: "mock"}))
+	reloaded := make(chan *config.Config, 1)
+	service.onReload = func(c *config.Config) { reloaded <- c }
+
+	done := make(chan error, 1)
+	go func() { done <- service.Start() }()
+
+	if err := os.WriteFile(path, []byte(reloadTestConfig("2h")), 0600); err != nil {
+		t.Fatalf("failed to rewrite config: %v", err)
+	}
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatalf("FindProcess failed: %v", err)
+	}
+	deadline := time.After(5 * time.Second)
+	for {
+		if err := proc.Signal(syscall.SIGHUP); err != nil {
+			t.Skipf("SIGHUP not supported on this platform: %v", err)
+		}
+		select {
+		case newCfg := <-reloaded:
+			if newCfg.Interval != 2*time.Hour {
+				t.Errorf("expected reloaded interval 2h, got %s", newCfg.Interval)
+			}
+			cancel()
+			if err := <-done; err != nil {
+				t.Errorf("Start returned error: %v", err)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("service did not reload after SIGHUP")
+		}
+	}
+}
+
+func TestDNSUpdateService_ReloadKeepsConfigOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynago.yml")
+	if err := os.WriteFile(path, []byte(reloadTestConfig("1h")), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	service := NewDNSUpdateService(context.Background(), cfg)
+
+	if err := os.WriteFile(path, []byte("interval: not-a-duration\n"), 0600); err != nil {
+		t.Fatalf("failed to rewrite config: %v", err)
+	}
+	if _, ok := service.reload(); ok {
+		t.Fatalf("expected reload to fail for invalid config")
+	}
+	if service.cfg != cfg {
+		t.Errorf("expected previous config to remain active after failed reload")
+	}
 }
@@ -0,0 +1,159 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"maps"
+	"sync/atomic"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/utils"
+)
+
+// ProviderStats holds running counters for a single provider.
+type ProviderStats struct {
+	TotalUpdates      uint64    // DNS record updates applied
+	TotalErrors       uint64    // Failed checks or updates
+	ConsecutiveErrors int       // Failures since the last successful check or update
+	DNSIP             string    // IP the record held at the last successful check, or was updated to
+	LastUpdated       time.Time // Time of the last applied update
+	LastError         time.Time // Time of the last failure
+	LastErrorMsg      string    // Message of the last failure
+}
+
+// GetStats returns a snapshot of the statistics for every provider that has been checked.
+//
+// The returned map and values are copies and safe to use after the service moves on.
+func (s *DNSUpdateService) GetStats() map[string]ProviderStats {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	out := make(map[string]ProviderStats, len(s.stats))
+	for name, st := range s.stats {
+		snap := *st
+		snap.TotalUpdates = atomic.LoadUint64(&st.TotalUpdates)
+		snap.TotalErrors = atomic.LoadUint64(&st.TotalErrors)
+		out[name] = snap
+	}
+	return out
+}
+
+// statsFor returns the stats entry for the named provider, creating it if needed.
+// The caller must hold s.statsMu.
+func (s *DNSUpdateService) statsFor(providerName string) *ProviderStats {
+	st, ok := s.stats[providerName]
+	if !ok {
+		st = &ProviderStats{}
+		s.stats[providerName] = st
+	}
+	return st
+}
+
+// recordSuccess notes a successful check for the named provider, and an applied update if updated is true.
+// dnsIP is the IP the record now holds; it is left unchanged if empty, as for a forced dry run.
+func (s *DNSUpdateService) recordSuccess(providerName, dnsIP string, updated bool) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsFor(providerName)
+	st.ConsecutiveErrors = 0
+	if dnsIP != "" {
+		st.DNSIP = dnsIP
+	}
+	if updated {
+		atomic.AddUint64(&st.TotalUpdates, 1)
+		st.LastUpdated = time.Now()
+	}
+}
+
+// recordError notes a failed check or update for the named provider.
+func (s *DNSUpdateService) recordError(providerName string, err error) {
+	s.statsMu.Lock()
+	defer s.statsMu.Unlock()
+	st := s.statsFor(providerName)
+	atomic.AddUint64(&st.TotalErrors, 1)
+	st.ConsecutiveErrors++
+	st.LastError = time.Now()
+	st.LastErrorMsg = err.Error()
+}
+
+// Status is the JSON document served on /status by the probe server.
+type Status struct {
+	PublicIP        string                    `json:"public_ip,omitempty"`   // Public IPv4 address fetched by the last cycle
+	PublicIPv6      string                    `json:"public_ipv6,omitempty"` // Public IPv6 address fetched by the last cycle
+	IPSourceLatency LatencySummary            `json:"ip_source_latency_ms"`  // Durations of recent public IP lookups
+	Providers       map[string]ProviderStatus `json:"providers"`             // Provider name -> state after the last cycle
+}
+
+// ProviderStatus is the JSON form of a provider's ProviderStats.
+type ProviderStatus struct {
+	DNSIP        string    `json:"dns_ip,omitempty"`
+	LastUpdate   time.Time `json:"last_update,omitzero"`
+	Errors       int       `json:"errors"` // Failures since the last successful check or update
+	LastError    string    `json:"last_error,omitempty"`
+	TotalUpdates uint64    `json:"total_updates"`
+	TotalErrors  uint64    `json:"total_errors"`
+}
+
+// LatencySummary reports latency statistics in milliseconds.
+type LatencySummary struct {
+	Samples int     `json:"samples"` // Number of lookups the statistics cover
+	Mean    float64 `json:"mean"`
+	P50     float64 `json:"p50"`
+	P95     float64 `json:"p95"`
+	P99     float64 `json:"p99"`
+}
+
+// publishStatus replaces the snapshot served on /status with the public addresses of the cycle that
+// just ran and the state of every provider checked so far (see GetStats), so /status never mixes
+// two cycles.
+func (s *DNSUpdateService) publishStatus(publicIP, publicIPv6 string) {
+	status := Status{PublicIP: publicIP, PublicIPv6: publicIPv6, Providers: make(map[string]ProviderStatus)}
+	for name, st := range s.GetStats() {
+		status.Providers[name] = ProviderStatus{
+			DNSIP:        st.DNSIP,
+			LastUpdate:   st.LastUpdated,
+			Errors:       st.ConsecutiveErrors,
+			LastError:    st.LastErrorMsg,
+			TotalUpdates: st.TotalUpdates,
+			TotalErrors:  st.TotalErrors,
+		}
+	}
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status = status
+}
+
+// Status returns the service status: the snapshot published after the last cycle (see
+// publishStatus), with statistics over the last utils.DefaultLatencyWindow public IP lookups.
+func (s *DNSUpdateService) Status() Status {
+	s.statusMu.RLock()
+	status := s.status
+	s.statusMu.RUnlock()
+	status.Providers = maps.Clone(status.Providers)
+	if status.Providers == nil {
+		status.Providers = make(map[string]ProviderStatus)
+	}
+	status.IPSourceLatency = summarizeLatency(s.latency.Stats())
+	return status
+}
+
+// summarizeLatency converts st to milliseconds.
+func summarizeLatency(st utils.LatencyStats) LatencySummary {
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	return LatencySummary{Samples: st.Count, Mean: ms(st.Mean), P50: ms(st.P50), P95: ms(st.P95), P99: ms(st.P99)}
+}
@@ -0,0 +1,74 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+func TestDNSUpdateService_GetStats(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	ok := &mockProvider{name: "ok", getIP: "1.2.3.4"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	reg := newTestRegistry(t, ok, failing)
+
+	service.runCycle(context.Background(), reg, "5.6.7.8", "")
+	ok.getIP = "5.6.7.8"
+	service.runCycle(context.Background(), reg, "5.6.7.8", "")
+
+	stats := service.GetStats()
+	okStats := stats["ok"]
+	if okStats.TotalUpdates != 1 || okStats.TotalErrors != 0 || okStats.LastUpdated.IsZero() || okStats.DNSIP != "5.6.7.8" {
+		t.Errorf("unexpected stats for ok provider: %+v", okStats)
+	}
+	failStats := stats["failing"]
+	if failStats.TotalErrors != 2 || failStats.ConsecutiveErrors != 2 || failStats.LastErrorMsg != "boom" {
+		t.Errorf("unexpected stats for failing provider: %+v", failStats)
+	}
+}
+
+func TestDNSUpdateService_StatsResetConsecutiveErrors(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{})
+	service.recordError("mock", errors.New("boom"))
+	service.recordError("mock", errors.New("boom"))
+	service.recordSuccess("mock", "1.2.3.4", false)
+
+	st := service.GetStats()["mock"]
+	if st.ConsecutiveErrors != 0 || st.TotalErrors != 2 || st.TotalUpdates != 0 {
+		t.Errorf("unexpected stats after recovery: %+v", st)
+	}
+}
+
+func TestDNSUpdateService_GetStatsReturnsCopy(t *testing.T) {
+	service := NewDNSUpdateService(context.Background(), &config.Config{})
+	service.recordSuccess("mock", "1.2.3.4", true)
+
+	snap := service.GetStats()
+	service.recordSuccess("mock", "1.2.3.4", true)
+
+	if snap["mock"].TotalUpdates != 1 {
+		t.Errorf("expected snapshot to be unaffected by later updates, got %d", snap["mock"].TotalUpdates)
+	}
+}
@@ -0,0 +1,83 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"github.com/cactus/go-statsd-client/v5/statsd"
+)
+
+// statsdFlushInterval is how often buffered stats are sent to the StatsD daemon.
+const statsdFlushInterval = time.Second
+
+// statsdEmitter sends stats to a plain StatsD daemon, which has no tags: a provider's stats are
+// named <prefix>.<provider>.<name>. Events are dropped.
+type statsdEmitter struct {
+	statter statsd.Statter
+}
+
+// Record sends stat unsampled.
+func (e statsdEmitter) Record(stat Stat) error {
+	name := stat.Name
+	if stat.Provider != "" {
+		name = stat.Provider + "." + name
+	}
+	switch stat.Kind {
+	case StatCounter:
+		return e.statter.Inc(name, stat.Value, 1)
+	case StatTimer:
+		return e.statter.TimingDuration(name, stat.Duration, 1)
+	case StatGauge:
+		return e.statter.Gauge(name, stat.Value, 1)
+	}
+	return nil
+}
+
+// newStatsDEmitter creates a buffered StatsD client for cfg, defaulting the address to
+// config.DefaultStatsDAddr and the prefix to config.DefaultStatsDPrefix.
+//
+// Returns the emitter and a function closing the client, which flushes any buffered stats.
+func newStatsDEmitter(cfg config.StatsDConfig) (Emitter, func(), error) {
+	addr := cfg.Addr
+	if addr == "" {
+		addr = config.DefaultStatsDAddr
+	}
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = config.DefaultStatsDPrefix
+	}
+	client, err := statsd.NewClientWithConfig(&statsd.ClientConfig{
+		Address:       addr,
+		Prefix:        prefix,
+		UseBuffered:   true,
+		FlushInterval: statsdFlushInterval,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Info("Sending StatsD metrics to %s with prefix %q", addr, prefix)
+	return statsdEmitter{statter: client}, func() {
+		if err := client.Close(); err != nil {
+			logger.Warn("Failed to close StatsD client: %v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,81 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// listenUDP opens a UDP socket standing in for a StatsD daemon.
+func listenUDP(t *testing.T) net.PacketConn {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// expectPackets reads packets from conn until their contents include every wanted line, failing
+// the test if they have not all arrived within five seconds. It returns everything received.
+func expectPackets(t *testing.T, conn net.PacketConn, want ...string) string {
+	t.Helper()
+	var received strings.Builder
+	buf := make([]byte, 8192)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for _, line := range want {
+		for !strings.Contains(received.String(), line) {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				t.Fatalf("packets %q do not contain %q: %v", received.String(), line, err)
+			}
+			received.Write(buf[:n])
+			received.WriteByte('\n')
+		}
+	}
+	return received.String()
+}
+
+func TestNewStatsDEmitter(t *testing.T) {
+	conn := listenUDP(t)
+	emitter, closeClient, err := newStatsDEmitter(config.StatsDConfig{Enabled: true, Addr: conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("newStatsDEmitter: %v", err)
+	}
+	emitter.Record(Stat{Kind: StatCounter, Name: "update.success", Provider: "cloudflare", Value: 1})
+	emitter.Record(Stat{Kind: StatTimer, Name: "ip_fetch.duration_ms", Duration: 250 * time.Millisecond})
+	emitter.Record(Stat{Kind: StatEvent, Name: "ip.changed", Provider: "cloudflare", Text: "dropped"})
+	emitter.Record(Stat{Kind: StatGauge, Name: "circuit_open", Provider: "cloudflare", Value: 1})
+	closeClient() // Flushes the buffered stats
+
+	received := expectPackets(t, conn,
+		"dynago.cloudflare.update.success:1|c",
+		"dynago.ip_fetch.duration_ms:250|ms",
+		"dynago.cloudflare.circuit_open:1|g",
+	)
+	if strings.Contains(received, "ip.changed") {
+		t.Errorf("expected events not to be sent to plain StatsD, got %q", received)
+	}
+}
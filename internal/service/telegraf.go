@@ -0,0 +1,109 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// telegrafBufferSize is the number of provider updates kept for /metrics/telegraf.
+const telegrafBufferSize = 100
+
+// telegrafMetric is one element of the /metrics/telegraf array, in the shape read by Telegraf's
+// inputs.http plugin with data_format = "json".
+type telegrafMetric struct {
+	Name      string            `json:"name"`
+	Tags      map[string]string `json:"tags"`
+	Fields    map[string]int64  `json:"fields"`
+	Timestamp int64             `json:"timestamp"` // Unix seconds
+}
+
+// telegrafBuffer is a ring buffer of the most recent provider updates, served as JSON on
+// /metrics/telegraf. It is safe for concurrent use.
+type telegrafBuffer struct {
+	mu      sync.Mutex
+	metrics [telegrafBufferSize]telegrafMetric
+	next    int // Index the next update is written to
+	full    bool
+}
+
+// add records the outcome of one provider's update that took d, overwriting the oldest update
+// once the buffer is full.
+func (b *telegrafBuffer) add(providerName string, success bool, d time.Duration) {
+	var ok int64
+	if success {
+		ok = 1
+	}
+	m := telegrafMetric{
+		Name:      "dns_update",
+		Tags:      map[string]string{"provider": providerName},
+		Fields:    map[string]int64{"success": ok, "duration_ms": d.Milliseconds()},
+		Timestamp: time.Now().Unix(),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.metrics[b.next] = m
+	b.next = (b.next + 1) % telegrafBufferSize
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// snapshot returns the buffered updates, oldest first.
+func (b *telegrafBuffer) snapshot() []telegrafMetric {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.full {
+		return append([]telegrafMetric{}, b.metrics[:b.next]...)
+	}
+	return append(append([]telegrafMetric{}, b.metrics[b.next:]...), b.metrics[:b.next]...)
+}
+
+// ServeHTTP writes the buffered updates as a JSON array, gzip-compressed if the client accepts it.
+func (b *telegrafBuffer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Encoding")
+	out := json.NewEncoder(w)
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = json.NewEncoder(gz)
+	}
+	if err := out.Encode(b.snapshot()); err != nil {
+		logger.Warn("Failed to write /metrics/telegraf response: %v", err)
+	}
+}
+
+// serveTelegraf serves /metrics/telegraf on ln until the service context is cancelled or stop is
+// called (see serveHTTP).
+func (s *DNSUpdateService) serveTelegraf(ln net.Listener) (stop func()) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics/telegraf", s.metrics.telegraf)
+	logger.Info("Serving Telegraf metrics on http://%s/metrics/telegraf", ln.Addr())
+	return s.serveHTTP("Telegraf metrics", ln, mux)
+}
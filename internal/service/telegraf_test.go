@@ -0,0 +1,154 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+)
+
+// getTelegraf requests /metrics/telegraf from srv, with acceptEncoding unless it is empty.
+func getTelegraf(t *testing.T, srv *httptest.Server, acceptEncoding string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/metrics/telegraf", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	// Without DisableCompression the client would decompress gzip responses itself.
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET /metrics/telegraf: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", got)
+	}
+	return resp
+}
+
+func TestTelegraf_ServesUpdates(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	service := NewDNSUpdateService(context.Background(), cfg)
+	ok := &mockProvider{name: "ok", getIP: "1.2.3.4"}
+	failing := &mockProvider{name: "failing", getIP: "1.2.3.4", updateErr: errors.New("boom")}
+	service.runCycle(context.Background(), newTestRegistry(t, ok, failing), "5.6.7.8", "")
+
+	srv := httptest.NewServer(service.metrics.telegraf)
+	defer srv.Close()
+	resp := getTelegraf(t, srv, "")
+	var got []struct {
+		Name      string            `json:"name"`
+		Tags      map[string]string `json:"tags"`
+		Fields    map[string]int64  `json:"fields"`
+		Timestamp int64             `json:"timestamp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 metrics, got %+v", got)
+	}
+	success := map[string]int64{}
+	for _, m := range got {
+		if m.Name != "dns_update" || len(m.Tags) != 1 || m.Timestamp < time.Now().Add(-time.Minute).Unix() {
+			t.Errorf("unexpected metric %+v", m)
+		}
+		if _, ok := m.Fields["duration_ms"]; !ok {
+			t.Errorf("expected a duration_ms field, got %v", m.Fields)
+		}
+		success[m.Tags["provider"]] = m.Fields["success"]
+	}
+	if success["ok"] != 1 || success["failing"] != 0 {
+		t.Errorf("expected success 1 for ok and 0 for failing, got %v", success)
+	}
+}
+
+func TestTelegraf_Gzip(t *testing.T) {
+	buf := &telegrafBuffer{}
+	buf.add("cloudflare", true, 120*time.Millisecond)
+	srv := httptest.NewServer(buf)
+	defer srv.Close()
+
+	resp := getTelegraf(t, srv, "gzip, deflate")
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected Content-Encoding gzip, got %q", got)
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	var got []telegrafMetric
+	if err := json.NewDecoder(gz).Decode(&got); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(got) != 1 || got[0].Fields["duration_ms"] != 120 || got[0].Fields["success"] != 1 {
+		t.Errorf("unexpected metrics %+v", got)
+	}
+
+	plain := getTelegraf(t, srv, "")
+	body, _ := io.ReadAll(plain.Body)
+	if plain.Header.Get("Content-Encoding") != "" || len(body) == 0 || body[0] != '[' {
+		t.Errorf("expected an uncompressed JSON array without gzip, got %q", body)
+	}
+}
+
+func TestTelegrafBuffer_KeepsLatest(t *testing.T) {
+	buf := &telegrafBuffer{}
+	for i := range telegrafBufferSize + 5 {
+		buf.add("p", true, time.Duration(i)*time.Millisecond)
+	}
+	got := buf.snapshot()
+	if len(got) != telegrafBufferSize {
+		t.Fatalf("expected %d metrics, got %d", telegrafBufferSize, len(got))
+	}
+	if first, last := got[0].Fields["duration_ms"], got[len(got)-1].Fields["duration_ms"]; first != 5 || last != telegrafBufferSize+4 {
+		t.Errorf("expected updates 5 to %d oldest first, got %d to %d", telegrafBufferSize+4, first, last)
+	}
+}
+
+func TestTelegraf_SharesMetricsServer(t *testing.T) {
+	cfg := &config.Config{Interval: time.Minute, IPSource: "mock"}
+	cfg.Metrics.PrometheusAddr, cfg.Metrics.TelegrafAddr = ":9090", ":9090"
+	service := NewDNSUpdateService(context.Background(), cfg)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer service.serveMetrics(ln)()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/metrics/telegraf")
+	if err != nil {
+		t.Fatalf("GET /metrics/telegraf: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected /metrics/telegraf on the metrics server, got %s", resp.Status)
+	}
+}
@@ -0,0 +1,53 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// telemetryShutdownTimeout bounds how long buffered spans and metrics may take to flush on shutdown.
+const telemetryShutdownTimeout = 5 * time.Second
+
+// startTelemetry sets up OpenTelemetry export for cfg when its trace or metrics endpoint is set
+// (see initTelemetry). Exported metrics are recorded alongside the Prometheus ones.
+//
+// Returns a function that flushes and stops the exporters; it never blocks for longer than
+// telemetryShutdownTimeout, even once the service context is cancelled.
+func (s *DNSUpdateService) startTelemetry(cfg config.OtelConfig) (stop func(), err error) {
+	if cfg.TraceEndpoint == "" && cfg.MetricsEndpoint == "" {
+		return func() {}, nil
+	}
+	shutdown, instruments, err := initTelemetry(s.ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.otel = instruments
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), telemetryShutdownTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.Warn("Failed to flush telemetry: %v", err)
+		}
+	}, nil
+}
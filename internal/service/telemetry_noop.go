@@ -0,0 +1,51 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !otel
+
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+)
+
+// otelMetrics stands in for the OpenTelemetry instruments in builds without the otel tag; it is
+// always nil and records nothing.
+type otelMetrics struct{}
+
+func (*otelMetrics) recordUpdate(string, string, time.Duration) {}
+func (*otelMetrics) recordIPFetch(time.Duration)                {}
+
+// initTelemetry warns that OpenTelemetry is unavailable: dynago was built without the otel tag.
+func initTelemetry(context.Context, config.OtelConfig) (func(context.Context) error, *otelMetrics, error) {
+	logger.Warn("otel endpoints are set but dynago was built without the otel tag; OpenTelemetry export is disabled")
+	return func(context.Context) error { return nil }, nil, nil
+}
+
+// startSpan returns ctx unchanged and a no-op end function; spans are only recorded in builds
+// with the otel tag.
+func startSpan(ctx context.Context, _, _ string) (context.Context, func(err error)) {
+	return ctx, endNoop
+}
+
+// endNoop is returned by startSpan so that untraced builds allocate nothing per span.
+func endNoop(error) {}
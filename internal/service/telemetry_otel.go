@@ -0,0 +1,145 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build otel
+
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// defaultOTLPPort is used for OTLP endpoints given without a port.
+const defaultOTLPPort = "4317"
+
+// initTelemetry installs global trace and meter providers exporting over OTLP gRPC to the endpoints
+// in cfg that are set. When both endpoints are the same, the exporters share one gRPC connection.
+//
+// Returns a function that shuts the providers down, flushing buffered spans and metrics, and then
+// closes the connections, along with the instruments to record metrics with (nil without a
+// metrics endpoint).
+func initTelemetry(ctx context.Context, cfg config.OtelConfig) (func(context.Context) error, *otelMetrics, error) {
+	name := cfg.ServiceName
+	if name == "" {
+		name = config.DefaultOtelServiceName
+	}
+	res := resource.NewSchemaless(semconv.ServiceName(name))
+
+	var shutdowns []func(context.Context) error
+	conns := make(map[string]*grpc.ClientConn)
+	shutdown := func(ctx context.Context) error {
+		var errs []error
+		for _, f := range shutdowns {
+			errs = append(errs, f(ctx))
+		}
+		for _, conn := range conns {
+			errs = append(errs, conn.Close())
+		}
+		return errors.Join(errs...)
+	}
+	dial := func(endpoint string) (*grpc.ClientConn, error) {
+		if conn, ok := conns[endpoint]; ok {
+			return conn, nil
+		}
+		conn, err := dialOTLP(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		conns[endpoint] = conn
+		return conn, nil
+	}
+	fail := func(err error) (func(context.Context) error, *otelMetrics, error) {
+		shutdown(ctx)
+		return nil, nil, err
+	}
+
+	if endpoint := cfg.TraceEndpoint; endpoint != "" {
+		conn, err := dial(endpoint)
+		if err != nil {
+			return fail(err)
+		}
+		exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithGRPCConn(conn))
+		if err != nil {
+			return fail(err)
+		}
+		tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		otel.SetTracerProvider(tp)
+		shutdowns = append(shutdowns, tp.Shutdown)
+		logger.Info("Exporting traces to %s as %s", endpoint, name)
+	}
+
+	var instruments *otelMetrics
+	if endpoint := cfg.MetricsEndpoint; endpoint != "" {
+		conn, err := dial(endpoint)
+		if err != nil {
+			return fail(err)
+		}
+		exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithGRPCConn(conn))
+		if err != nil {
+			return fail(err)
+		}
+		mp := sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(cfg.MetricsInterval))),
+			sdkmetric.WithResource(res),
+		)
+		shutdowns = append(shutdowns, mp.Shutdown)
+		if instruments, err = newOtelMetrics(mp); err != nil {
+			return fail(err)
+		}
+		otel.SetMeterProvider(mp)
+		logger.Info("Exporting metrics to %s every %s as %s", endpoint, cfg.MetricsInterval, name)
+	}
+
+	return shutdown, instruments, nil
+}
+
+// dialOTLP creates a gRPC client for the OTLP endpoint URL. http:// endpoints are dialled without
+// TLS and endpoints without a port use defaultOTLPPort.
+func dialOTLP(endpoint string) (*grpc.ClientConn, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: %w", endpoint, err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), defaultOTLPPort)
+	}
+	creds := insecure.NewCredentials()
+	if u.Scheme == "https" {
+		creds = credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	return grpc.NewClient(host, grpc.WithTransportCredentials(creds))
+}
@@ -0,0 +1,53 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build otel
+
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by the service.
+const tracerName = "github.com/aaronlmathis/dynago/internal/service"
+
+// startSpan starts a span called name as a child of any span in ctx, tagged with the provider
+// name unless it is empty.
+//
+// Returns the span's context, to be passed to the traced call, and a function ending the span that
+// records err, if non-nil, as the span's error status.
+func startSpan(ctx context.Context, name, provider string) (context.Context, func(err error)) {
+	var opts []trace.SpanStartOption
+	if provider != "" {
+		opts = append(opts, trace.WithAttributes(attribute.String("dynago.provider", provider)))
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, opts...)
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,93 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build otel
+
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCheckAndUpdate_Spans(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	cfg := &config.Config{IPSource: "mock"}
+	changed := &mockProvider{name: "changed", getIP: "1.1.1.1"}
+	failing := &mockProvider{name: "failing", getErr: errors.New("boom")}
+	service := NewDNSUpdateService(context.Background(), cfg,
+		WithIPSourceFunc(func([]string) (string, error) { return "5.6.7.8", nil }))
+	if err := service.checkAndUpdate(newTestRegistry(t, changed, failing)); err == nil {
+		t.Fatal("checkAndUpdate() = nil, want the failing provider's error")
+	}
+
+	spans := rec.Ended()
+	var root sdktrace.ReadOnlySpan
+	var names []string
+	for _, span := range spans {
+		if span.Name() == "UpdateCycle" {
+			root = span
+			continue
+		}
+		names = append(names, span.Name())
+	}
+	if root == nil {
+		t.Fatal("no UpdateCycle span recorded")
+	}
+	if root.Status().Code != codes.Error {
+		t.Errorf("UpdateCycle status = %v, want Error", root.Status().Code)
+	}
+	slices.Sort(names)
+	want := []string{"GetCurrentIP", "GetRecordIP", "GetRecordIP", "UpdateRecordIP"}
+	if !slices.Equal(names, want) {
+		t.Errorf("child spans = %v, want %v", names, want)
+	}
+	for _, span := range spans {
+		if span != root && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Errorf("%s span is not a child of UpdateCycle", span.Name())
+		}
+		if span.Name() == "GetRecordIP" {
+			failed := span.Status().Code == codes.Error
+			if provider := providerAttr(span); failed != (provider == "failing") {
+				t.Errorf("GetRecordIP span for %q has status %v", provider, span.Status().Code)
+			}
+		}
+	}
+}
+
+// providerAttr returns the dynago.provider attribute of span.
+func providerAttr(span sdktrace.ReadOnlySpan) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == "dynago.provider" {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package store keeps the history of DNS record changes in a SQLite database, so past IP changes
+// can be queried with `dynago history`.
+//
+// It uses github.com/mattn/go-sqlite3, which needs cgo; in a build without cgo, NewHistoryStore
+// returns an error.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // Registers the sqlite3 driver
+)
+
+// schema creates the history table if it does not exist yet.
+const schema = `CREATE TABLE IF NOT EXISTS ip_history (id INTEGER PRIMARY KEY AUTOINCREMENT, ts TEXT NOT NULL, provider TEXT NOT NULL, record TEXT NOT NULL, old_ip TEXT, new_ip TEXT NOT NULL)`
+
+// tsLayout is the fixed-width UTC format of the ts column, so that timestamps sort and compare as text.
+const tsLayout = "2006-01-02T15:04:05.000Z"
+
+// HistoryEntry is one row of the history: an IP change applied to a provider's record.
+type HistoryEntry struct {
+	ID       int64     `json:"id"`               // Row ID, assigned by Insert
+	Time     time.Time `json:"time"`             // When the change was applied (stored in UTC, to the millisecond)
+	Provider string    `json:"provider"`         // Provider name
+	Record   string    `json:"record"`           // Record name(s) that changed
+	OldIP    string    `json:"old_ip,omitempty"` // Previous value, empty if the record did not exist
+	NewIP    string    `json:"new_ip"`           // New value
+}
+
+// HistoryStore records HistoryEntry rows in a SQLite database. It is safe for concurrent use.
+type HistoryStore struct {
+	db *sql.DB
+}
+
+// NewHistoryStore opens the SQLite database at path, creating it and the ip_history table if needed.
+//
+// Returns an error if the database cannot be opened or the table cannot be created.
+func NewHistoryStore(path string) (*HistoryStore, error) {
+	// Wait for a writer in another process, such as the service while `dynago history` reads. The
+	// path is escaped so that a "?", "#" or "%" in it is not read as part of the URI syntax.
+	db, err := sql.Open("sqlite3", "file:"+url.PathEscape(path)+"?_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create ip_history table: %w", err)
+	}
+	return &HistoryStore{db: db}, nil
+}
+
+// Insert adds entry to the history. A zero Time means now.
+func (h *HistoryStore) Insert(entry HistoryEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	_, err := h.db.Exec(`INSERT INTO ip_history (ts, provider, record, old_ip, new_ip) VALUES (?, ?, ?, ?, ?)`,
+		entry.Time.UTC().Format(tsLayout), entry.Provider, entry.Record, nullIfEmpty(entry.OldIP), entry.NewIP)
+	return err
+}
+
+// QueryHistory returns the changes applied at or after since, oldest first. An empty provider
+// matches every provider, and a zero since every change.
+func (h *HistoryStore) QueryHistory(provider string, since time.Time) ([]HistoryEntry, error) {
+	query := `SELECT id, ts, provider, record, old_ip, new_ip FROM ip_history WHERE ts >= ?`
+	args := []any{since.UTC().Format(tsLayout)}
+	if provider != "" {
+		query += ` AND provider = ?`
+		args = append(args, provider)
+	}
+	return h.query(query+` ORDER BY ts, id`, args...)
+}
+
+// query runs a SELECT of every ip_history column and returns the rows as entries.
+func (h *HistoryStore) query(query string, args ...any) ([]HistoryEntry, error) {
+	rows, err := h.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var e HistoryEntry
+		var ts string
+		var oldIP sql.NullString
+		if err := rows.Scan(&e.ID, &ts, &e.Provider, &e.Record, &oldIP, &e.NewIP); err != nil {
+			return nil, err
+		}
+		if e.Time, err = time.Parse(tsLayout, ts); err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q in row %d: %w", ts, e.ID, err)
+		}
+		e.OldIP = oldIP.String
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Latest returns the most recent change to provider's records, and false if there is none.
+func (h *HistoryStore) Latest(provider string) (HistoryEntry, bool, error) {
+	entries, err := h.query(`SELECT id, ts, provider, record, old_ip, new_ip FROM ip_history WHERE provider = ? ORDER BY ts DESC, id DESC LIMIT 1`, provider)
+	if err != nil || len(entries) == 0 {
+		return HistoryEntry{}, false, err
+	}
+	return entries[0], true, nil
+}
+
+// Close closes the database.
+func (h *HistoryStore) Close() error {
+	return h.db.Close()
+}
+
+// nullIfEmpty returns s, or nil so that an empty string is stored as NULL.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
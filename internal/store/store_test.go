@@ -0,0 +1,124 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestStore opens a history store in a temporary directory, closed when the test ends.
+func newTestStore(t *testing.T) (*HistoryStore, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "history.db")
+	h, err := NewHistoryStore(path)
+	if err != nil {
+		t.Fatalf("NewHistoryStore failed: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h, path
+}
+
+func TestHistoryStore_InsertAndQuery(t *testing.T) {
+	h, _ := newTestStore(t)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, e := range []HistoryEntry{
+		{Provider: "cloudflare", Record: "home.example.com", NewIP: "1.1.1.1"},
+		{Provider: "route53", Record: "vpn.example.com", OldIP: "9.9.9.9", NewIP: "2.2.2.2"},
+		{Provider: "cloudflare", Record: "home.example.com", OldIP: "1.1.1.1", NewIP: "3.3.3.3"},
+	} {
+		e.Time = start.Add(time.Duration(i) * 24 * time.Hour)
+		if err := h.Insert(e); err != nil {
+			t.Fatalf("Insert failed: %v", err)
+		}
+	}
+
+	all, err := h.QueryHistory("", time.Time{})
+	if err != nil {
+		t.Fatalf("QueryHistory failed: %v", err)
+	}
+	if len(all) != 3 || all[0].NewIP != "1.1.1.1" || all[2].NewIP != "3.3.3.3" {
+		t.Fatalf("expected every entry oldest first, got %+v", all)
+	}
+	if all[0].OldIP != "" || all[1].OldIP != "9.9.9.9" || !all[1].Time.Equal(start.Add(24*time.Hour)) || all[1].ID == 0 {
+		t.Errorf("unexpected round trip %+v", all[1])
+	}
+
+	cf, err := h.QueryHistory("cloudflare", start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("QueryHistory failed: %v", err)
+	}
+	if len(cf) != 1 || cf[0].NewIP != "3.3.3.3" {
+		t.Errorf("expected only the later cloudflare change, got %+v", cf)
+	}
+
+	latest, ok, err := h.Latest("cloudflare")
+	if err != nil || !ok || latest.NewIP != "3.3.3.3" {
+		t.Errorf("Latest(cloudflare) = %+v, %v, %v; want the 3.3.3.3 change", latest, ok, err)
+	}
+	if _, ok, err := h.Latest("other"); ok || err != nil {
+		t.Errorf("Latest(other) = %v, %v; want no entry", ok, err)
+	}
+
+	// since is inclusive, whatever its time zone.
+	exact, err := h.QueryHistory("", start.Add(48*time.Hour).In(time.FixedZone("UTC+2", 2*60*60)))
+	if err != nil || len(exact) != 1 {
+		t.Errorf("expected the change at since to be included, got %+v (%v)", exact, err)
+	}
+}
+
+// TestHistoryStore_Reopen verifies that the history survives reopening the database.
+func TestHistoryStore_Reopen(t *testing.T) {
+	h, path := newTestStore(t)
+	if err := h.Insert(HistoryEntry{Provider: "route53", Record: "home.example.com", NewIP: "1.1.1.1"}); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	h.Close()
+
+	reopened, err := NewHistoryStore(path)
+	if err != nil {
+		t.Fatalf("NewHistoryStore failed: %v", err)
+	}
+	defer reopened.Close()
+	entries, err := reopened.QueryHistory("route53", time.Now().Add(-time.Minute))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected the entry inserted before reopening, got %+v (%v)", entries, err)
+	}
+}
+
+func TestHistoryStore_PathWithURIDelimiters(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "what?#100% sure")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "history.db")
+	h, err := NewHistoryStore(path)
+	if err != nil {
+		t.Fatalf("NewHistoryStore failed: %v", err)
+	}
+	defer h.Close()
+	if err := h.Insert(HistoryEntry{Time: time.Now(), Provider: "cloudflare", Record: "home.example.com", NewIP: "1.1.1.1"}); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("expected the database at %s: %v", path, err)
+	}
+}
@@ -0,0 +1,124 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyWindow is the number of samples a LatencyTracker keeps when no size is given.
+const DefaultLatencyWindow = 100
+
+// LatencyTracker keeps the most recent durations in a fixed-size ring buffer and reports
+// percentiles and the mean over them. It is safe for concurrent use.
+type LatencyTracker struct {
+	mu      sync.Mutex
+	samples []time.Duration // Ring buffer of recorded durations
+	next    int             // Index the next sample is written to
+	full    bool            // Whether samples has wrapped around
+}
+
+// LatencyStats is a snapshot of the samples held by a LatencyTracker.
+type LatencyStats struct {
+	Count int           // Number of samples, at most the tracker's size
+	Mean  time.Duration // Arithmetic mean
+	P50   time.Duration // Median
+	P95   time.Duration // 95th percentile
+	P99   time.Duration // 99th percentile
+}
+
+// NewLatencyTracker returns a tracker keeping the last size samples, or DefaultLatencyWindow if
+// size is not positive.
+func NewLatencyTracker(size int) *LatencyTracker {
+	if size <= 0 {
+		size = DefaultLatencyWindow
+	}
+	return &LatencyTracker{samples: make([]time.Duration, size)}
+}
+
+// Record adds d, replacing the oldest sample once the buffer is full.
+func (t *LatencyTracker) Record(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[t.next] = d
+	t.next = (t.next + 1) % len(t.samples)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// P50 returns the median of the recorded samples, or 0 if there are none.
+func (t *LatencyTracker) P50() time.Duration { return percentile(t.sorted(), 50) }
+
+// P95 returns the 95th percentile of the recorded samples, or 0 if there are none.
+func (t *LatencyTracker) P95() time.Duration { return percentile(t.sorted(), 95) }
+
+// P99 returns the 99th percentile of the recorded samples, or 0 if there are none.
+func (t *LatencyTracker) P99() time.Duration { return percentile(t.sorted(), 99) }
+
+// Mean returns the mean of the recorded samples, or 0 if there are none.
+func (t *LatencyTracker) Mean() time.Duration { return mean(t.sorted()) }
+
+// Stats returns the count, mean, and percentiles of the recorded samples in one pass.
+func (t *LatencyTracker) Stats() LatencyStats {
+	s := t.sorted()
+	return LatencyStats{
+		Count: len(s),
+		Mean:  mean(s),
+		P50:   percentile(s, 50),
+		P95:   percentile(s, 95),
+		P99:   percentile(s, 99),
+	}
+}
+
+// sorted returns a sorted copy of the recorded samples.
+func (t *LatencyTracker) sorted() []time.Duration {
+	t.mu.Lock()
+	n := t.next
+	if t.full {
+		n = len(t.samples)
+	}
+	s := slices.Clone(t.samples[:n])
+	t.mu.Unlock()
+	slices.Sort(s)
+	return s
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank, 1)-1]
+}
+
+// mean returns the arithmetic mean of samples.
+func mean(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range samples {
+		sum += d
+	}
+	return sum / time.Duration(len(samples))
+}
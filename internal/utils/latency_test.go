@@ -0,0 +1,83 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLatencyTracker checks the percentiles and mean over 1ms..100ms recorded out of order.
+func TestLatencyTracker(t *testing.T) {
+	tracker := NewLatencyTracker(0)
+	for i := 100; i >= 1; i-- {
+		tracker.Record(time.Duration(i) * time.Millisecond)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{"P50", tracker.P50(), 50 * time.Millisecond},
+		{"P95", tracker.P95(), 95 * time.Millisecond},
+		{"P99", tracker.P99(), 99 * time.Millisecond},
+		{"Mean", tracker.Mean(), 50500 * time.Microsecond},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %s, want %s", c.name, c.got, c.want)
+		}
+	}
+	if stats := tracker.Stats(); stats.Count != DefaultLatencyWindow || stats.P95 != 95*time.Millisecond {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestLatencyTracker_Window checks that only the most recent samples are kept.
+func TestLatencyTracker_Window(t *testing.T) {
+	tracker := NewLatencyTracker(3)
+	if got := tracker.Stats(); got != (LatencyStats{}) {
+		t.Errorf("expected zero stats without samples, got %+v", got)
+	}
+	for _, ms := range []int{500, 1, 2, 3} {
+		tracker.Record(time.Duration(ms) * time.Millisecond)
+	}
+	stats := tracker.Stats()
+	if stats.Count != 3 || stats.P99 != 3*time.Millisecond || stats.Mean != 2*time.Millisecond {
+		t.Errorf("expected the 500ms sample to be dropped, got %+v", stats)
+	}
+}
+
+func TestLatencyTracker_Concurrent(t *testing.T) {
+	tracker := NewLatencyTracker(10)
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				tracker.Record(time.Millisecond)
+				tracker.P50()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := tracker.Stats(); got.Count != 10 || got.P99 != time.Millisecond {
+		t.Errorf("unexpected stats %+v", got)
+	}
+}
@@ -22,26 +22,62 @@
 package utils
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
+	"strings"
+	"time"
 )
 
-// GetCurrentIP fetches the current public IP address from the specified source URL.
+// IPFetchTimeout bounds the request to each IP source.
+const IPFetchTimeout = 10 * time.Second
+
+// ipClient is the HTTP client used to query IP sources.
+var ipClient = &http.Client{Timeout: IPFetchTimeout}
+
+// GetCurrentIP fetches the current public IP address, trying each source URL in order.
 //
-// ipSource: The URL of an external service that returns the public IP as plain text (e.g., https://api.ipify.org).
+// sources: URLs of external services that return the public IP as plain text (e.g., https://api.ipify.org).
+// Later sources are only tried if earlier ones fail. Each request is bounded by IPFetchTimeout, and
+// all of them by ctx.
 //
-// Returns the IP address as a string, or an error if the request fails or the response is not HTTP 200.
+// Returns the IP address as a string, or an error if every source fails. A response that is not a
+// bare IP address (see ParseIP) counts as a failure of that source.
 //
 // Example:
 //
-//	ip, err := utils.GetCurrentIP("https://api.ipify.org")
+//	ip, err := utils.GetCurrentIP(ctx, "https://api.ipify.org", "https://icanhazip.com")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	fmt.Println("Current IP:", ip)
-func GetCurrentIP(ipSource string) (string, error) {
-	resp, err := http.Get(ipSource)
+func GetCurrentIP(ctx context.Context, sources ...string) (string, error) {
+	if len(sources) == 0 {
+		return "", errors.New("no IP source configured")
+	}
+	var errs []error
+	for _, source := range sources {
+		ip, err := fetchIP(ctx, source)
+		if err == nil {
+			return ip, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", source, err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// fetchIP fetches the public IP from a single source URL.
+//
+// Returns an error if the request fails or the response is not HTTP 200.
+func fetchIP(ctx context.Context, ipSource string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ipSource, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := ipClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -53,5 +89,26 @@ func GetCurrentIP(ipSource string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return string(body), nil
+	// Some services (e.g. icanhazip.com) terminate the address with a newline.
+	return ParseIP(strings.TrimSpace(string(body)))
+}
+
+// ParseIP returns s if it is a bare IPv4 or IPv6 address, without a zone.
+//
+// Fetched addresses end up in DNS records and hook command lines, so anything else returned by an
+// IP source, such as an HTML error page or shell syntax, is rejected with an error.
+func ParseIP(s string) (string, error) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil || addr.Zone() != "" {
+		return "", fmt.Errorf("invalid IP address %q", truncate(s, 64))
+	}
+	return s, nil
+}
+
+// truncate shortens s to at most n bytes for use in error messages.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
 }
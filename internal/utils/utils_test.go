@@ -19,9 +19,11 @@
 package utils
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 // TestGetCurrentIP checks that GetCurrentIP fetches the IP from a mock HTTP server.
@@ -32,7 +34,7 @@ func TestGetCurrentIP(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	ip, err := GetCurrentIP(ts.URL)
+	ip, err := GetCurrentIP(context.Background(), ts.URL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -40,3 +42,73 @@ func TestGetCurrentIP(t *testing.T) {
 		t.Errorf("expected %s, got %s", mockIP, ip)
 	}
 }
+
+// TestGetCurrentIP_Fallback checks that later sources are tried when earlier ones fail.
+func TestGetCurrentIP_Fallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("5.6.7.8\n"))
+	}))
+	defer ok.Close()
+
+	ip, err := GetCurrentIP(context.Background(), failing.URL, ok.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ip != "5.6.7.8" {
+		t.Errorf("expected 5.6.7.8, got %q", ip)
+	}
+	if _, err := GetCurrentIP(context.Background(), failing.URL); err == nil {
+		t.Errorf("expected error when every source fails")
+	}
+	if _, err := GetCurrentIP(context.Background()); err == nil {
+		t.Errorf("expected error when no source is given")
+	}
+}
+
+// TestGetCurrentIP_Context checks that a source that never answers is abandoned when ctx expires.
+func TestGetCurrentIP_Context(t *testing.T) {
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := GetCurrentIP(ctx, hung.URL); err == nil {
+		t.Fatal("expected an error from a source that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > IPFetchTimeout/2 {
+		t.Errorf("GetCurrentIP took %s, want it to stop at the context deadline", elapsed)
+	}
+}
+
+// TestGetCurrentIP_RejectsNonIP checks that a source returning anything but a bare IP address fails.
+func TestGetCurrentIP_RejectsNonIP(t *testing.T) {
+	for _, body := range []string{"1.2.3.4; rm -rf ~", "$(id)", "<html>error</html>", "fe80::1%eth0", ""} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		if ip, err := GetCurrentIP(context.Background(), ts.URL); err == nil {
+			t.Errorf("GetCurrentIP with body %q = %q, want an error", body, ip)
+		}
+		ts.Close()
+	}
+}
+
+func TestParseIP(t *testing.T) {
+	for _, ip := range []string{"1.2.3.4", "2001:db8::1"} {
+		if got, err := ParseIP(ip); err != nil || got != ip {
+			t.Errorf("ParseIP(%q) = %q, %v; want %q", ip, got, err, ip)
+		}
+	}
+	for _, bad := range []string{"1.2.3.4 && reboot", "'1.2.3.4'", "example.com", "1.2.3.4/24"} {
+		if _, err := ParseIP(bad); err == nil {
+			t.Errorf("ParseIP(%q) succeeded, want an error", bad)
+		}
+	}
+}
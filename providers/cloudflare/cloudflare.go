@@ -25,31 +25,275 @@ package cloudflare
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	providers "github.com/aaronlmathis/dynago/providers"
 	cf "github.com/cloudflare/cloudflare-go"
+	"github.com/rs/zerolog"
 )
 
+// errRecordNotFound is returned when the configured record does not exist in the zone.
+var errRecordNotFound = errors.New("record not found")
+
 // CloudflareConfig holds Cloudflare-specific configuration.
+//
+// Records lists every record to keep updated. The top-level zone_id/zone_name, record_name (or
+// record_names), record_type, and proxied fields are shorthand for a Records list with one entry
+// per name; when Records is set, entries without a zone, record type, or proxied flag inherit the
+// top-level ones.
 type CloudflareConfig struct {
-	Enabled    bool   `yaml:"enabled"`
-	APIToken   string `yaml:"api_token"`
+	Enabled    bool               `yaml:"enabled"`
+	APIToken   string             `yaml:"api_token"`
+	Email      string             `yaml:"email"`     // Account email for api_key authentication
+	APIEmail   string             `yaml:"api_email"` // Alias for email
+	APIKey     string             `yaml:"api_key"`   // Legacy Global API Key, used instead of api_token
+	ZoneID     string             `yaml:"zone_id"`
+	ZoneName   string             `yaml:"zone_name"` // Looked up to find the zone ID when zone_id is empty
+	RecordName string             `yaml:"record_name"`
+	RecordType string             `yaml:"record_type"`
+	Proxied    bool               `yaml:"proxied"` // Default for records entries that do not set proxied
+	Records    []CloudflareRecord `yaml:"records"` // Multiple zone+record pairs managed by this provider
+	// RecordNames lists several records in the top-level zone, kept at the same IP. GetRecordIP
+	// compares the first against the current IP.
+	RecordNames []string `yaml:"record_names"`
+	// DualStack manages an A and an AAAA record for every configured name, instead of one record
+	// of record_type. The service keeps them at the public IPv4 and IPv6 address respectively.
+	DualStack bool `yaml:"dual_stack"`
+	// ToggleDevMode turns on development mode (cache bypass) for each updated zone, then turns it
+	// off again after DevModeDuration (default 3m).
+	ToggleDevMode   bool          `yaml:"toggle_dev_mode"`
+	DevModeDuration time.Duration `yaml:"dev_mode_duration"`
+	// RecordCommentTemplate is a text/template rendered into each updated record's comment
+	// (default DefaultCommentTemplate). RecordTags are merged into each updated record's tags.
+	RecordCommentTemplate string   `yaml:"record_comment_template"`
+	RecordTags            []string `yaml:"record_tags"`
+	// CreateIfMissing makes UpdateRecordIP create configured records that do not exist yet
+	// (default true). When false, a missing record is an error.
+	CreateIfMissing *bool `yaml:"create_if_missing"`
+	// AutoCreate is an alias for CreateIfMissing. When only auto_create is set, it decides;
+	// when neither is set, records are created (the create_if_missing default).
+	AutoCreate *bool `yaml:"auto_create"`
+}
+
+// CloudflareRecord identifies a single DNS record managed by the Cloudflare provider.
+type CloudflareRecord struct {
 	ZoneID     string `yaml:"zone_id"`
+	ZoneName   string `yaml:"zone_name"` // Looked up to find the zone ID when zone_id is empty
 	RecordName string `yaml:"record_name"`
 	RecordType string `yaml:"record_type"`
-	Proxied    bool   `yaml:"proxied"`
+	Proxied    *bool  `yaml:"proxied"` // Overrides the top-level proxied flag when set
+	TTL        int    `yaml:"ttl"`     // TTL in seconds; 0 keeps the current TTL, 1 means automatic
 }
 
 // CloudflareProvider implements the DNSProvider interface for Cloudflare.
 //
-// It uses the Cloudflare Go SDK to query and update DNS records in a specified zone.
+// It uses the Cloudflare Go SDK to query and update DNS records in one or more zones.
 type CloudflareProvider struct {
 	Cfg    *CloudflareConfig // Provider-specific configuration
 	Client *cf.API           // Cached Cloudflare API client
+
+	httpClient *http.Client // HTTP client owned by Client, so Close can release its idle connections
+
+	recordMu sync.Mutex                 // Guards records
+	records  map[recordKey]cachedRecord // Records found by the last listing, so updates can skip it
+
+	rateMu         sync.Mutex // Guards rateLimitUntil
+	rateLimitUntil time.Time  // API calls are refused until this Retry-After deadline
+
+	rateLimitBackoff time.Duration // First retry delay after a 429 without Retry-After; zero means one second
+
+	zoneMu  sync.Mutex        // Serializes zone_name lookups
+	zoneIDs map[string]string // zone_name -> resolved zone ID
+
+	devMu     sync.Mutex          // Guards devTimers against concurrent updates and timer callbacks
+	devTimers map[string]devTimer // zone ID -> pending development mode disable
+
+	logger *zerolog.Logger // Set by SetLogger; nil logs through logger.WithProvider
+}
+
+// SetLogger makes the provider log through l, such as a logger from logger.WithProvider; nil
+// restores the default. It must not be called while the provider is in use.
+func (c *CloudflareProvider) SetLogger(l *zerolog.Logger) { c.logger = l }
+
+// log returns the logger set with SetLogger, or by default a package-level logger tagged with the
+// provider's name.
+func (c *CloudflareProvider) log() *zerolog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	l := logger.WithProvider(c.ProviderName())
+	return &l
+}
+
+// ManagedRecords returns the records this config manages, with top-level defaults applied.
+// Proxied is always non-nil in the result. With dual_stack, every record is listed twice, as an A
+// and as an AAAA record.
+//
+// Returns nil if none of records, record_names, or record_name is set.
+func (cfg *CloudflareConfig) ManagedRecords() []CloudflareRecord {
+	records := cfg.configuredRecords()
+	if !cfg.DualStack {
+		return records
+	}
+	var dual []CloudflareRecord
+	for _, rec := range records {
+		for _, recordType := range []string{"A", "AAAA"} {
+			rec.RecordType = recordType
+			dual = append(dual, rec)
+		}
+	}
+	return dual
+}
+
+// configuredRecords returns the records as configured, with top-level defaults applied.
+func (cfg *CloudflareConfig) configuredRecords() []CloudflareRecord {
+	proxied := cfg.Proxied
+	if len(cfg.Records) == 0 {
+		names := cfg.RecordNames
+		if len(names) == 0 && cfg.RecordName != "" {
+			names = []string{cfg.RecordName}
+		}
+		if len(names) == 0 {
+			return nil
+		}
+		records := make([]CloudflareRecord, len(names))
+		for i, name := range names {
+			records[i] = CloudflareRecord{
+				ZoneID:     cfg.ZoneID,
+				ZoneName:   cfg.ZoneName,
+				RecordName: name,
+				RecordType: cfg.RecordType,
+				Proxied:    &proxied,
+			}
+		}
+		return records
+	}
+	records := make([]CloudflareRecord, len(cfg.Records))
+	for i, rec := range cfg.Records {
+		if rec.ZoneID == "" && rec.ZoneName == "" {
+			rec.ZoneID, rec.ZoneName = cfg.ZoneID, cfg.ZoneName
+		}
+		if rec.RecordType == "" {
+			rec.RecordType = cfg.RecordType
+		}
+		if rec.Proxied == nil {
+			rec.Proxied = &proxied
+		}
+		records[i] = rec
+	}
+	return records
+}
+
+// ValidateConfig checks that exactly one kind of credentials is set, that at least one record is configured, that
+// each identifies a zone and, unless dual_stack is set, a record_type, and that email and create_if_missing do not disagree with their aliases api_email and
+// auto_create.
+//
+// At least one of zone_id or zone_name must apply to every record; zone_id takes precedence
+// when both are set.
+func (cfg *CloudflareConfig) ValidateConfig() error {
+	switch {
+	case cfg.APIToken == "" && cfg.APIKey == "":
+		return errors.New("cloudflare: one of api_token or api_key (with email) is required")
+	case cfg.APIToken != "" && cfg.APIKey != "":
+		return errors.New("cloudflare: set either api_token or api_key, not both")
+	}
+	if cfg.Email != "" && cfg.APIEmail != "" && cfg.Email != cfg.APIEmail {
+		return errors.New("cloudflare: email and api_email disagree; set only one")
+	}
+	if cfg.usesAPIKey() && cfg.accountEmail() == "" {
+		return errors.New("cloudflare: email (or api_email) is required with api_key")
+	}
+	set := 0
+	for _, ok := range []bool{cfg.RecordName != "", len(cfg.RecordNames) > 0, len(cfg.Records) > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set > 1 {
+		return errors.New("cloudflare: set only one of record_name, record_names, or records")
+	}
+	records := cfg.ManagedRecords()
+	if len(records) == 0 {
+		return errors.New("cloudflare: at least one record is required (record_name, record_names, or records)")
+	}
+	if cfg.DualStack {
+		for _, rec := range cfg.configuredRecords() {
+			if rec.RecordType != "" && rec.RecordType != "A" && rec.RecordType != "AAAA" {
+				return fmt.Errorf("cloudflare: dual_stack manages A and AAAA records, but %s has record_type %s", rec.RecordName, rec.RecordType)
+			}
+		}
+	}
+	for i, rec := range records {
+		prefix := "cloudflare"
+		if len(cfg.Records) > 0 {
+			prefix = fmt.Sprintf("cloudflare: records[%d]", i)
+		}
+		switch {
+		case rec.RecordName == "":
+			return fmt.Errorf("%s: record_name is required", prefix)
+		case rec.ZoneID == "" && rec.ZoneName == "":
+			return fmt.Errorf("%s: one of zone_id or zone_name is required", prefix)
+		case rec.RecordType == "" && !cfg.DualStack:
+			return fmt.Errorf("%s: record_type is required", prefix)
+		}
+	}
+	if cfg.CreateIfMissing != nil && cfg.AutoCreate != nil && *cfg.CreateIfMissing != *cfg.AutoCreate {
+		return errors.New("cloudflare: create_if_missing and auto_create disagree; set only one")
+	}
+	if _, err := cfg.commentTemplate(); err != nil {
+		return fmt.Errorf("cloudflare: invalid record_comment_template: %w", err)
+	}
+	return nil
+}
+
+// createIfMissing reports whether missing records are created: create_if_missing if set, else
+// its alias auto_create, defaulting to true.
+func (cfg *CloudflareConfig) createIfMissing() bool {
+	switch {
+	case cfg.CreateIfMissing != nil:
+		return *cfg.CreateIfMissing
+	case cfg.AutoCreate != nil:
+		return *cfg.AutoCreate
+	}
+	return true
+}
+
+// accountEmail returns the account email for api_key authentication: email if set, else its alias
+// api_email.
+func (cfg *CloudflareConfig) accountEmail() string {
+	if cfg.Email != "" {
+		return cfg.Email
+	}
+	return cfg.APIEmail
+}
+
+// usesAPIKey reports whether the legacy email and Global API Key authenticate API calls.
+func (cfg *CloudflareConfig) usesAPIKey() bool {
+	return cfg.APIToken == "" && cfg.APIKey != ""
+}
+
+func init() {
+	config.RegisterProviderValidator("cloudflare", func(section map[string]any) error {
+		var cfg CloudflareConfig
+		if err := config.ConfigFromMap(section, &cfg); err != nil {
+			return fmt.Errorf("cloudflare: %w", err)
+		}
+		return cfg.ValidateConfig()
+	})
 }
 
 // New creates a new CloudflareProvider from a generic config map.
 //
+// The config is validated only if the provider is enabled.
+//
 // Usage: cfprovider.New(configMap)
 func New(raw any) (*CloudflareProvider, error) {
 	var cfg CloudflareConfig
@@ -57,79 +301,619 @@ func New(raw any) (*CloudflareProvider, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &CloudflareProvider{Cfg: &cfg}, nil
+	c := &CloudflareProvider{Cfg: &cfg}
+	if cfg.Enabled {
+		if err := cfg.ValidateConfig(); err != nil {
+			return nil, err
+		}
+		for _, rec := range cfg.ManagedRecords() {
+			if rec.ZoneID != "" && rec.ZoneName != "" {
+				c.log().Warn().Msgf("cloudflare: %s has both zone_id and zone_name set; using zone_id %s", rec.RecordName, rec.ZoneID)
+			}
+		}
+	}
+	return c, nil
 }
 
-// getClient initializes and returns the Cloudflare API client using the API token from config.
+// getClient initializes and returns the Cloudflare API client using the API token from config,
+// or the email and Global API Key if no token is set.
 func (c *CloudflareProvider) getClient() (*cf.API, error) {
 	if c.Client != nil {
 		return c.Client, nil
 	}
-	api, err := cf.NewWithAPIToken(c.Cfg.APIToken)
+	httpClient := c.newHTTPClient()
+	var (
+		api *cf.API
+		err error
+	)
+	if c.Cfg.usesAPIKey() {
+		api, err = cf.New(c.Cfg.APIKey, c.Cfg.accountEmail(), cf.HTTPClient(httpClient))
+	} else {
+		api, err = cf.NewWithAPIToken(c.Cfg.APIToken, cf.HTTPClient(httpClient))
+	}
 	if err != nil {
 		return nil, err
 	}
 	c.Client = api
+	c.httpClient = httpClient
 	return c.Client, nil
 }
 
+// Close turns off development mode for any zone still waiting for its timer (see toggle_dev_mode),
+// then releases the API client and its idle HTTP connections.
+//
+// The provider remains usable; the next call creates a new client.
+func (c *CloudflareProvider) Close() error {
+	c.disablePendingDevMode()
+	if c.httpClient != nil {
+		c.httpClient.CloseIdleConnections()
+	}
+	c.Client = nil
+	c.httpClient = nil
+	return nil
+}
+
+// zoneID returns the record's zone ID, looking it up from zone_name on first use. zone_id is
+// used without a lookup when set.
+//
+// A successful lookup is cached per zone name so it happens at most once; a failed lookup is
+// retried on the next call rather than leaving the provider permanently unusable.
+func (c *CloudflareProvider) zoneID(ctx context.Context, rec CloudflareRecord) (string, error) {
+	if rec.ZoneID != "" {
+		return rec.ZoneID, nil
+	}
+	c.zoneMu.Lock()
+	defer c.zoneMu.Unlock()
+	if id, ok := c.zoneIDs[rec.ZoneName]; ok {
+		return id, nil
+	}
+	client, err := c.getClient()
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.ListZonesContext(ctx, cf.WithZoneFilters(rec.ZoneName, "", ""))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up zone %q: %w", rec.ZoneName, err)
+	}
+	if len(resp.Result) == 0 {
+		return "", fmt.Errorf("no zone named %q is visible to this API token", rec.ZoneName)
+	}
+	if len(resp.Result) > 1 {
+		ids := make([]string, len(resp.Result))
+		for i, zone := range resp.Result {
+			ids[i] = zone.ID
+		}
+		c.log().Warn().Msgf("cloudflare: %d zones named %s found (%s); using %s, set zone_id to choose another",
+			len(ids), rec.ZoneName, strings.Join(ids, ", "), ids[0])
+	}
+	if c.zoneIDs == nil {
+		c.zoneIDs = make(map[string]string)
+	}
+	c.zoneIDs[rec.ZoneName] = resp.Result[0].ID
+	return resp.Result[0].ID, nil
+}
+
 // ProviderName returns the string "cloudflare" for Cloudflare providers.
 func (c *CloudflareProvider) ProviderName() string { return "cloudflare" }
 
-// GetRecordIP fetches the current IP address for the Cloudflare DNS record.
+// RecordNames returns the names of the managed records (see providers.RecordNamer).
+func (c *CloudflareProvider) RecordNames() []string {
+	return recordNames(c.Cfg.ManagedRecords())
+}
+
+// recordNames returns the distinct names of records, in order. With dual_stack, each name appears
+// once although it has an A and an AAAA record.
+func recordNames(records []CloudflareRecord) []string {
+	var names []string
+	for _, rec := range records {
+		if !slices.Contains(names, rec.RecordName) {
+			names = append(names, rec.RecordName)
+		}
+	}
+	return names
+}
+
+// ProviderConfig returns the configured zones and record names with the API token and key redacted.
 //
-// Returns the IP address as a string, or an error if the record is not found or the API call fails.
-func (c *CloudflareProvider) GetRecordIP() (string, error) {
+// Multiple records are joined with commas.
+func (c *CloudflareProvider) ProviderConfig() map[string]string {
+	var zoneIDs, zoneNames, names []string
+	for _, rec := range c.Cfg.ManagedRecords() {
+		if rec.ZoneID != "" && !slices.Contains(zoneIDs, rec.ZoneID) {
+			zoneIDs = append(zoneIDs, rec.ZoneID)
+		}
+		if rec.ZoneName != "" && !slices.Contains(zoneNames, rec.ZoneName) {
+			zoneNames = append(zoneNames, rec.ZoneName)
+		}
+		names = append(names, rec.RecordName)
+	}
+	cfg := map[string]string{
+		"api_token":   providers.Redact(c.Cfg.APIToken),
+		"record_name": strings.Join(names, ","),
+	}
+	if c.Cfg.APIKey != "" {
+		cfg["api_key"] = providers.Redact(c.Cfg.APIKey)
+		cfg["email"] = c.Cfg.accountEmail()
+	}
+	if len(zoneIDs) > 0 {
+		cfg["zone_id"] = strings.Join(zoneIDs, ",")
+	}
+	if len(zoneNames) > 0 {
+		cfg["zone_name"] = strings.Join(zoneNames, ",")
+	}
+	return cfg
+}
+
+// recordFor returns the configured record with the given name and type. If none matches,
+// the first configured record's zone is used with the given name and type.
+func (c *CloudflareProvider) recordFor(name, recordType string) CloudflareRecord {
+	records := c.Cfg.ManagedRecords()
+	for _, rec := range records {
+		if rec.RecordName == name && rec.RecordType == recordType {
+			return rec
+		}
+	}
+	var rec CloudflareRecord
+	if len(records) > 0 {
+		rec = records[0]
+	}
+	rec.RecordName, rec.RecordType = name, recordType
+	return rec
+}
+
+// listRecords returns the zone ID and the DNS records in that zone matching rec's name and type exactly.
+//
+// The query is filtered by name and type server-side, and leaving Page and PerPage unset makes the
+// SDK follow ResultInfo through every page, so matches past the first page are not missed.
+// When exactly one record matches, it is cached for UpdateRecordIP.
+func (c *CloudflareProvider) listRecords(ctx context.Context, rec CloudflareRecord) (string, []cf.DNSRecord, error) {
 	client, err := c.getClient()
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	zone := cf.ZoneIdentifier(c.Cfg.ZoneID)
-	records, _, err := client.ListDNSRecords(context.Background(), zone, cf.ListDNSRecordsParams{
-		Name: c.Cfg.RecordName,
-		Type: c.Cfg.RecordType,
+	zoneID, err := c.zoneID(ctx, rec)
+	if err != nil {
+		return "", nil, err
+	}
+	records, _, err := client.ListDNSRecords(ctx, cf.ZoneIdentifier(zoneID), cf.ListDNSRecordsParams{
+		Name: rec.RecordName,
+		Type: rec.RecordType,
 	})
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
+	matched := records[:0]
 	for _, record := range records {
-		if record.Name == c.Cfg.RecordName && record.Type == c.Cfg.RecordType {
-			return record.Content, nil
+		if record.Name == rec.RecordName && record.Type == rec.RecordType {
+			matched = append(matched, record)
+		}
+	}
+	if len(matched) == 1 {
+		c.rememberRecord(rec, zoneID, matched[0])
+	} else {
+		c.forgetRecord(rec)
+	}
+	return zoneID, matched, nil
+}
+
+// findRecord looks up the DNS record described by rec.
+//
+// Returns the zone ID and matching record, or an error if the record is not found or the API call fails.
+func (c *CloudflareProvider) findRecord(ctx context.Context, rec CloudflareRecord) (string, cf.DNSRecord, error) {
+	zoneID, records, err := c.listRecords(ctx, rec)
+	if err != nil {
+		return "", cf.DNSRecord{}, err
+	}
+	if len(records) == 0 {
+		return "", cf.DNSRecord{}, errRecordNotFound
+	}
+	return zoneID, records[0], nil
+}
+
+// GetRecordIP fetches the current IP address of the first configured record.
+//
+// When several records are configured, the others are also read. If any of them holds a different
+// IP, the returned record's Content is empty so it never matches the current IP and every record is
+// updated. A record that cannot be read is only logged.
+//
+// A rate-limited lookup is retried up to three times, waiting for Cloudflare's Retry-After deadline
+// or backing off exponentially from one second. If the deadline is too far away to wait for, no
+// further API call is made and an error matching providers.ErrRateLimited is returned.
+//
+// Returns the first record, or an error if it is not found or the API call fails.
+func (c *CloudflareProvider) GetRecordIP(ctx context.Context) (*providers.DNSRecord, error) {
+	return c.recordIP(ctx, c.Cfg.ManagedRecords())
+}
+
+// recordIP implements GetRecordIP for the given records.
+func (c *CloudflareProvider) recordIP(ctx context.Context, records []CloudflareRecord) (*providers.DNSRecord, error) {
+	var record *providers.DNSRecord
+	err := c.retryRateLimited(ctx, "get record", func() error {
+		var err error
+		record, err = c.getRecordIP(ctx, records)
+		return err
+	})
+	return record, err
+}
+
+// getRecordIP performs a single GetRecordIP attempt.
+func (c *CloudflareProvider) getRecordIP(ctx context.Context, records []CloudflareRecord) (*providers.DNSRecord, error) {
+	if err := c.checkRateLimit("get record"); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, c.wrapError("get record", errors.New("no records configured"))
+	}
+	_, first, err := c.findRecord(ctx, records[0])
+	if err != nil {
+		return nil, c.wrapError("get record", err)
+	}
+	record := c.toDNSRecord(first)
+	for _, rec := range records[1:] {
+		_, other, err := c.findRecord(ctx, rec)
+		if err != nil {
+			c.log().Warn().Msgf("cloudflare: could not read %s %s: %v", rec.RecordType, rec.RecordName, err)
+			continue
+		}
+		if other.Content != first.Content {
+			c.log().Warn().Msgf("cloudflare: %s holds %s but %s holds %s; records disagree, updating all of them",
+				other.Name, other.Content, first.Name, first.Content)
+			record.Content = ""
 		}
 	}
-	return "", errors.New("record not found")
+	return &record, nil
+}
+
+// GetRecordTTL fetches the current TTL (in seconds) of the first configured record.
+//
+// Cloudflare reports a TTL of 1 for records using "automatic" TTL. Rate limits are handled as in
+// GetRecordIP.
+func (c *CloudflareProvider) GetRecordTTL(ctx context.Context) (int64, error) {
+	return c.recordTTL(ctx, c.Cfg.ManagedRecords())
+}
+
+// recordTTL implements GetRecordTTL for the given records.
+func (c *CloudflareProvider) recordTTL(ctx context.Context, records []CloudflareRecord) (int64, error) {
+	var ttl int64
+	err := c.retryRateLimited(ctx, "get record TTL", func() error {
+		var err error
+		ttl, err = c.getRecordTTL(ctx, records)
+		return err
+	})
+	return ttl, err
+}
+
+// getRecordTTL performs a single GetRecordTTL attempt.
+func (c *CloudflareProvider) getRecordTTL(ctx context.Context, records []CloudflareRecord) (int64, error) {
+	if err := c.checkRateLimit("get record TTL"); err != nil {
+		return 0, err
+	}
+	if len(records) == 0 {
+		return 0, c.wrapError("get record TTL", errors.New("no records configured"))
+	}
+	_, record, err := c.findRecord(ctx, records[0])
+	if err != nil {
+		return 0, c.wrapError("get record TTL", err)
+	}
+	return int64(record.TTL), nil
 }
 
-// UpdateRecordIP updates the Cloudflare DNS record to the given IP address.
+// UpdateRecordIP updates every configured Cloudflare DNS record to the given IP address.
+//
+// ip: The new IP address to set in the DNS records. Proxied status and TTL are set per record from config.
+//
+// Every existing record matching a configured name and type is updated, so round-robin records
+// sharing a name are all changed. If only some records fail, a *providers.MultiError listing each
+// failed record is returned.
+//
+// Records found by an earlier GetRecordIP or UpdateRecordIP are updated directly by ID without
+// listing the zone again; if such a record was deleted externally, it is looked up afresh.
+//
+// Each updated record is stamped with the rendered comment template, and the configured tags are
+// merged into its existing tags.
+//
+// Configured records that do not exist yet are created, unless create_if_missing is false.
 //
-// ip: The new IP address to set in the DNS record. The proxied status is set according to config.
+// With toggle_dev_mode, development mode is enabled for each zone before its records are updated.
 //
-// Returns an error if the update fails or the record is not found.
-func (c *CloudflareProvider) UpdateRecordIP(ip string) error {
+// Rate limits are retried and reported the same way as in GetRecordIP.
+//
+// Returns an error if any update fails, or if a configured record is not found and create_if_missing is false.
+func (c *CloudflareProvider) UpdateRecordIP(ctx context.Context, ip string) error {
+	return c.updateRecords(ctx, c.Cfg.ManagedRecords(), ip)
+}
+
+// updateRecords implements UpdateRecordIP for the given records.
+func (c *CloudflareProvider) updateRecords(ctx context.Context, managed []CloudflareRecord, ip string) error {
+	return c.retryRateLimited(ctx, "update record", func() error {
+		return c.updateRecordIP(ctx, managed, ip)
+	})
+}
+
+// updateRecordIP performs a single UpdateRecordIP attempt.
+func (c *CloudflareProvider) updateRecordIP(ctx context.Context, managed []CloudflareRecord, ip string) error {
+	if err := c.checkRateLimit("update record"); err != nil {
+		return err
+	}
 	client, err := c.getClient()
+	if err != nil {
+		return c.wrapError("update record", err)
+	}
+	multi := &providers.MultiError{}
+	devModeZones := make(map[string]bool)
+	for _, rec := range managed {
+		zoneID, records, cached, err := c.recordsToUpdate(ctx, rec)
+		if err != nil {
+			multi.Total++
+			multi.Errors = append(multi.Errors, c.wrapError("update record", fmt.Errorf("%s: %w", rec.RecordName, err)))
+			continue
+		}
+		if len(records) == 0 && !c.Cfg.createIfMissing() {
+			multi.Total++
+			multi.Errors = append(multi.Errors, c.wrapError("update record", fmt.Errorf("%s: %w for update", rec.RecordName, errRecordNotFound)))
+			continue
+		}
+		if c.Cfg.ToggleDevMode && !devModeZones[zoneID] {
+			devModeZones[zoneID] = true
+			c.enableDevMode(ctx, client, zoneID)
+		}
+		if len(records) == 0 {
+			multi.Total++
+			if err := c.createRecord(ctx, client, zoneID, rec, ip); err != nil {
+				multi.Errors = append(multi.Errors, c.wrapError("create record", fmt.Errorf("%s: %w", rec.RecordName, err)))
+			}
+			continue
+		}
+		for _, record := range records {
+			multi.Total++
+			err := c.updateRecord(ctx, client, zoneID, rec, record, ip)
+			var notFound *cf.NotFoundError
+			if cached && errors.As(err, &notFound) {
+				c.log().Info().Msgf("cloudflare: cached record %s (id %s) no longer exists, looking it up again", record.Name, record.ID)
+				c.forgetRecord(rec)
+				zoneID, record, err = c.findRecord(ctx, rec)
+				if err == nil {
+					err = c.updateRecord(ctx, client, zoneID, rec, record, ip)
+				}
+			}
+			if err != nil {
+				multi.Errors = append(multi.Errors, c.wrapError("update record", fmt.Errorf("%s (id %s): %w", record.Name, record.ID, err)))
+			}
+		}
+	}
+	switch {
+	case len(multi.Errors) == 0:
+		return nil
+	case multi.Total == 1:
+		return multi.Errors[0]
+	}
+	return multi
+}
+
+// recordsToUpdate returns the zone ID and records to update for rec.
+//
+// A record cached by an earlier listing is returned without an API call and cached is set, so the
+// caller can fall back to a fresh listing if the record has since been deleted.
+func (c *CloudflareProvider) recordsToUpdate(ctx context.Context, rec CloudflareRecord) (zoneID string, records []cf.DNSRecord, cached bool, err error) {
+	if entry, ok := c.cachedRecordFor(rec); ok {
+		return entry.zoneID, []cf.DNSRecord{entry.record}, true, nil
+	}
+	zoneID, records, err = c.listRecords(ctx, rec)
+	return zoneID, records, false, err
+}
+
+// updateRecord sets a single Cloudflare record to ip, stamping the comment and merging tags.
+func (c *CloudflareProvider) updateRecord(ctx context.Context, client *cf.API, zoneID string, rec CloudflareRecord, record cf.DNSRecord, ip string) error {
+	comment, err := c.Cfg.renderComment(ip, record.Name)
 	if err != nil {
 		return err
 	}
-	zone := cf.ZoneIdentifier(c.Cfg.ZoneID)
-	records, _, err := client.ListDNSRecords(context.Background(), zone, cf.ListDNSRecordsParams{
-		Name: c.Cfg.RecordName,
-		Type: c.Cfg.RecordType,
+	edit := cf.UpdateDNSRecordParams{
+		ID:      record.ID,
+		Type:    rec.RecordType,
+		Name:    rec.RecordName,
+		Content: ip,
+		Proxied: rec.Proxied,
+		TTL:     rec.TTL,
+		Comment: &comment,
+		Tags:    mergeTags(record.Tags, c.Cfg.RecordTags),
+	}
+	if _, err := client.UpdateDNSRecord(ctx, cf.ZoneIdentifier(zoneID), edit); err != nil {
+		return err
+	}
+	record.Content, record.Comment, record.Tags = ip, comment, edit.Tags
+	c.refreshRecord(rec, record)
+	return nil
+}
+
+// createRecord creates rec in the zone pointing at ip, with the rendered comment and configured tags.
+func (c *CloudflareProvider) createRecord(ctx context.Context, client *cf.API, zoneID string, rec CloudflareRecord, ip string) error {
+	comment, err := c.Cfg.renderComment(ip, rec.RecordName)
+	if err != nil {
+		return err
+	}
+	c.log().Info().Msgf("cloudflare: creating new Cloudflare record %s %s -> %s", rec.RecordType, rec.RecordName, ip)
+	record, err := client.CreateDNSRecord(ctx, cf.ZoneIdentifier(zoneID), cf.CreateDNSRecordParams{
+		Type:    rec.RecordType,
+		Name:    rec.RecordName,
+		Content: ip,
+		Proxied: rec.Proxied,
+		TTL:     rec.TTL,
+		Comment: comment,
+		Tags:    mergeTags(nil, c.Cfg.RecordTags),
 	})
 	if err != nil {
 		return err
 	}
-	for _, record := range records {
-		if record.Name == c.Cfg.RecordName && record.Type == c.Cfg.RecordType {
-			edit := cf.UpdateDNSRecordParams{
-				ID:      record.ID,
-				Type:    c.Cfg.RecordType,
-				Name:    c.Cfg.RecordName,
-				Content: ip,
-				Proxied: &c.Cfg.Proxied,
-			}
-			_, err := client.UpdateDNSRecord(context.Background(), zone, edit)
-			return err
+	c.rememberRecord(rec, zoneID, record)
+	return nil
+}
+
+// DeleteRecord removes the Cloudflare DNS record with the given name and type.
+//
+// The zone of the matching configured record is used, or the first configured zone otherwise.
+// Returns an error if the record is not found or the API call fails.
+func (c *CloudflareProvider) DeleteRecord(ctx context.Context, name, recordType string) error {
+	client, err := c.getClient()
+	if err != nil {
+		return c.wrapError("delete record", err)
+	}
+	rec := c.recordFor(name, recordType)
+	zoneID, record, err := c.findRecord(ctx, rec)
+	if err != nil {
+		return c.wrapError("delete record", err)
+	}
+	if err := client.DeleteDNSRecord(ctx, cf.ZoneIdentifier(zoneID), record.ID); err != nil {
+		return c.wrapError("delete record", err)
+	}
+	c.forgetRecord(rec)
+	return nil
+}
+
+// ListManagedRecords returns the configured Cloudflare DNS records as currently held in their zones.
+//
+// Configured records that do not exist yet are omitted.
+func (c *CloudflareProvider) ListManagedRecords(ctx context.Context) ([]providers.DNSRecord, error) {
+	return c.listManagedRecords(ctx, c.Cfg.ManagedRecords())
+}
+
+// listManagedRecords implements ListManagedRecords for the given records.
+func (c *CloudflareProvider) listManagedRecords(ctx context.Context, configured []CloudflareRecord) ([]providers.DNSRecord, error) {
+	managed := []providers.DNSRecord{}
+	for _, rec := range configured {
+		_, records, err := c.listRecords(ctx, rec)
+		if err != nil {
+			return nil, c.wrapError("list records", err)
+		}
+		for _, record := range records {
+			managed = append(managed, c.toDNSRecord(record))
+		}
+	}
+	return managed, nil
+}
+
+// toDNSRecord converts a Cloudflare API record to a providers.DNSRecord.
+func (c *CloudflareProvider) toDNSRecord(record cf.DNSRecord) providers.DNSRecord {
+	return providers.DNSRecord{
+		ID:          record.ID,
+		Name:        record.Name,
+		Type:        record.Type,
+		Content:     record.Content,
+		TTL:         int64(record.TTL),
+		Proxied:     record.Proxied,
+		Provider:    c.ProviderName(),
+		LastUpdated: record.ModifiedOn,
+	}
+}
+
+// HealthCheck verifies that the API token, or the email and API key, are valid.
+func (c *CloudflareProvider) HealthCheck(ctx context.Context) error {
+	return c.ValidateToken(ctx)
+}
+
+// ValidateToken checks that the API token is active using the token verification endpoint.
+//
+// With api_key authentication there is no token to verify, so the user details are fetched instead.
+//
+// The credential is named in the log, masked with logger.Redact.
+//
+// Returns an error matching providers.ErrUnauthorized if the token is inactive or rejected.
+func (c *CloudflareProvider) ValidateToken(ctx context.Context) error {
+	credential := "API token " + logger.Redact(c.Cfg.APIToken)
+	if c.Cfg.usesAPIKey() {
+		credential = "API key " + logger.Redact(c.Cfg.APIKey)
+	}
+	err := c.validateToken(ctx)
+	if err != nil {
+		c.log().Error().Msgf("cloudflare: %s invalid or insufficient permissions: %v", credential, err)
+		return err
+	}
+	c.log().Info().Msgf("cloudflare: %s active", credential)
+	return nil
+}
+
+// validateToken implements ValidateToken without logging.
+func (c *CloudflareProvider) validateToken(ctx context.Context) error {
+	client, err := c.getClient()
+	if err != nil {
+		return c.wrapError("validate token", err)
+	}
+	if c.Cfg.usesAPIKey() {
+		if _, err := client.UserDetails(ctx); err != nil {
+			return c.wrapError("validate token", fmt.Errorf("failed to verify API key: %w", err))
+		}
+		return nil
+	}
+	token, err := client.VerifyAPIToken(ctx)
+	if err != nil {
+		return c.wrapError("validate token", fmt.Errorf("failed to verify API token: %w", err))
+	}
+	if token.Status != "active" {
+		return &providers.ProviderError{
+			Provider:     c.ProviderName(),
+			Op:           "validate token",
+			Err:          fmt.Errorf("%w: API token status is %q", providers.ErrUnauthorized, token.Status),
+			Unauthorized: true,
 		}
 	}
-	return errors.New("record not found for update")
+	return nil
+}
+
+// SelfTest verifies that the API token is active and that every configured zone is accessible.
+//
+// Only read-only API calls are made; DNS records are never modified.
+func (c *CloudflareProvider) SelfTest(ctx context.Context) error {
+	client, err := c.getClient()
+	if err != nil {
+		return c.wrapError("self test", err)
+	}
+	if err := c.ValidateToken(ctx); err != nil {
+		return err
+	}
+	checked := make(map[string]bool)
+	for _, rec := range c.Cfg.ManagedRecords() {
+		zoneID, err := c.zoneID(ctx, rec)
+		if err != nil {
+			return c.wrapError("self test", err)
+		}
+		if checked[zoneID] {
+			continue
+		}
+		checked[zoneID] = true
+		if _, err := client.ZoneDetails(ctx, zoneID); err != nil {
+			return c.wrapError("self test", fmt.Errorf("failed to look up zone %s: %w", zoneID, err))
+		}
+	}
+	return nil
+}
+
+// wrapError wraps err in a providers.ProviderError classified from the Cloudflare SDK error type.
+// 429 and 5xx responses get their types from rateLimitTransport.
+//
+// Returns nil if err is nil.
+func (c *CloudflareProvider) wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	pe := &providers.ProviderError{Provider: c.ProviderName(), Op: op, Err: err}
+	var (
+		authzErr   *cf.AuthorizationError
+		authnErr   *cf.AuthenticationError
+		notFound   *cf.NotFoundError
+		rateLimit  *cf.RatelimitError
+		serviceErr *cf.ServiceError
+		netErr     net.Error
+	)
+	switch {
+	case errors.As(err, &authzErr), errors.As(err, &authnErr):
+		pe.Unauthorized = true
+	case errors.As(err, &notFound), errors.Is(err, errRecordNotFound):
+		pe.NotFound = true
+	case errors.As(err, &rateLimit):
+		pe.RateLimited = true
+		pe.Temporary = true
+	case errors.As(err, &serviceErr), errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		pe.Temporary = true
+	}
+	return pe
 }
@@ -20,9 +20,41 @@
 package cloudflare
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	providers "github.com/aaronlmathis/dynago/providers"
+	cf "github.com/cloudflare/cloudflare-go"
+	"github.com/rs/zerolog"
 )
 
+// newTestProvider returns a CloudflareProvider whose client talks to a mock API server.
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *CloudflareProvider {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	p := &CloudflareProvider{
+		Cfg:              &CloudflareConfig{Enabled: true, ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"},
+		rateLimitBackoff: time.Millisecond,
+	}
+	client, err := cf.NewWithAPIToken("token", cf.BaseURL(ts.URL), cf.UsingRetryPolicy(0, 0, 0), cf.HTTPClient(p.newHTTPClient()))
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	p.Client = client
+	return p
+}
+
 func TestCloudflareProvider_New_Unmarshal(t *testing.T) {
 	cfgMap := map[string]any{
 		"enabled":     true,
@@ -47,3 +79,799 @@ func TestCloudflareProvider_ProviderName(t *testing.T) {
 		t.Errorf("expected provider name 'cloudflare'")
 	}
 }
+
+// listResponse is a single-record ListDNSRecords response body.
+const listResponse = `{"success":true,"result":[{"id":"rec1","name":"home.example.com","type":"A","content":"1.2.3.4","ttl":120,"modified_on":"2025-01-02T03:04:05Z"}],"result_info":{"page":1,"per_page":100,"count":1,"total_count":1,"total_pages":1}}`
+
+// emptyListResponse is a ListDNSRecords response body with no records.
+const emptyListResponse = `{"success":true,"result":[],"result_info":{"page":1,"per_page":100,"count":0,"total_count":0,"total_pages":0}}`
+
+func TestCloudflareProvider_CloseReinitializesClient(t *testing.T) {
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{APIToken: "token"}}
+	first, err := p.getClient()
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if p.Client != nil || p.httpClient != nil {
+		t.Errorf("expected Close to release the client")
+	}
+	second, err := p.getClient()
+	if err != nil {
+		t.Fatalf("getClient after Close failed: %v", err)
+	}
+	if second == nil || second == first {
+		t.Errorf("expected getClient to create a new client after Close")
+	}
+}
+
+func TestCloudflareProvider_GetRecordIP(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(strings.Replace(listResponse, `"ttl":120`, `"ttl":120,"proxied":true`, 1)))
+	})
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "1.2.3.4" || record.ID != "rec1" || record.TTL != 120 || record.Proxied == nil || !*record.Proxied {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestCloudflareProvider_GetRecordIP_Paginated(t *testing.T) {
+	var pages []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		result := `[{"id":"other` + page + `","name":"home.example.com","type":"TXT","content":"x"}]`
+		if page == "3" {
+			result = `[{"id":"rec3","name":"home.example.com","type":"A","content":"9.9.9.9"}]`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"success":true,"result":%s,"result_info":{"page":%s,"per_page":1,"count":1,"total_count":3,"total_pages":3}}`, result, page)
+	})
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ID != "rec3" || record.Content != "9.9.9.9" {
+		t.Errorf("expected the record from page 3, got %+v", record)
+	}
+	if strings.Join(pages, ",") != "1,2,3" {
+		t.Errorf("expected pages 1,2,3 to be requested, got %v", pages)
+	}
+}
+
+func TestCloudflareProvider_GetRecordTTL(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(listResponse))
+	})
+	ttl, err := p.GetRecordTTL(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl != 120 {
+		t.Errorf("expected TTL 120, got %d", ttl)
+	}
+}
+
+func TestCloudflareProvider_DeleteRecord(t *testing.T) {
+	var deletedPath string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(listResponse))
+		case http.MethodDelete:
+			deletedPath = r.URL.Path
+			w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	if err := p.DeleteRecord(context.Background(), "home.example.com", "A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(deletedPath, "/zones/zone/dns_records/rec1") {
+		t.Errorf("expected DELETE for record rec1, got path %q", deletedPath)
+	}
+}
+
+func TestCloudflareProvider_DeleteRecord_NotFound(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(emptyListResponse))
+	})
+	if err := p.DeleteRecord(context.Background(), "missing.example.com", "A"); err == nil {
+		t.Errorf("expected error when record does not exist")
+	}
+}
+
+func TestCloudflareProvider_ListManagedRecords(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(listResponse))
+	})
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 managed record, got %d", len(records))
+	}
+	got := records[0]
+	if got.Name != "home.example.com" || got.Content != "1.2.3.4" || got.TTL != 120 || got.Provider != "cloudflare" {
+		t.Errorf("unexpected record: %+v", got)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !got.LastUpdated.Equal(want) {
+		t.Errorf("expected LastUpdated %s, got %s", want, got.LastUpdated)
+	}
+}
+
+func TestCloudflareProvider_SelfTest(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenStatus string
+		wantErr     bool
+	}{
+		{name: "active token", tokenStatus: "active"},
+		{name: "disabled token", tokenStatus: "disabled", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					t.Errorf("SelfTest must only make read-only calls, got %s %s", r.Method, r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/user/tokens/verify"):
+					w.Write([]byte(`{"success":true,"result":{"id":"tok","status":"` + tt.tokenStatus + `"}}`))
+				case strings.HasSuffix(r.URL.Path, "/zones/zone"):
+					w.Write([]byte(`{"success":true,"result":{"id":"zone","name":"example.com"}}`))
+				default:
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+			})
+			err := p.SelfTest(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelfTest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCloudflareProvider_HealthCheck(t *testing.T) {
+	for _, status := range []string{"active", "expired"} {
+		p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasSuffix(r.URL.Path, "/user/tokens/verify") {
+				t.Errorf("unexpected request: %s", r.URL.Path)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"success":true,"result":{"id":"tok","status":"` + status + `"}}`))
+		})
+		err := p.HealthCheck(context.Background())
+		if (err != nil) != (status != "active") {
+			t.Errorf("token %s: HealthCheck() error = %v", status, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), status) {
+			t.Errorf("token %s: expected HealthCheck() error to name the token status, got %v", status, err)
+		}
+	}
+}
+
+func TestCloudflareProvider_ValidateToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr bool
+	}{
+		{"active", http.StatusOK, `{"success":true,"result":{"id":"tok","status":"active"}}`, false},
+		{"disabled", http.StatusOK, `{"success":true,"result":{"id":"tok","status":"disabled"}}`, true},
+		{"rejected", http.StatusUnauthorized, `{"success":false,"errors":[{"code":1000,"message":"Invalid API Token"}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				if !strings.HasSuffix(r.URL.Path, "/user/tokens/verify") {
+					t.Errorf("unexpected request: %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			err := p.ValidateToken(context.Background())
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, providers.ErrUnauthorized) {
+				t.Errorf("expected ErrUnauthorized, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudflareProvider_APIEmail(t *testing.T) {
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{
+		APIEmail: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A",
+	}}
+	client, err := p.getClient()
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	if client.APIKey != "key" || client.APIEmail != "user@example.com" {
+		t.Errorf("expected client to use api_email and API key, got key %q email %q", client.APIKey, client.APIEmail)
+	}
+	if got := p.ProviderConfig()["email"]; got != "user@example.com" {
+		t.Errorf("expected ProviderConfig to report the api_email, got %q", got)
+	}
+}
+
+func TestCloudflareProvider_APIKeyAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Auth-Key") != "key" || r.Header.Get("X-Auth-Email") != "user@example.com" {
+			t.Errorf("expected email and API key headers, got %v", r.Header)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("expected no bearer token with api_key auth")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/user"):
+			w.Write([]byte(`{"success":true,"result":{"id":"user","email":"user@example.com"}}`))
+		case strings.HasSuffix(r.URL.Path, "/dns_records"):
+			w.Write([]byte(listResponse))
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	}))
+	defer ts.Close()
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{
+		Email: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A",
+	}}
+	client, err := p.getClient()
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	if client.APIKey != "key" || client.APIEmail != "user@example.com" || client.APIToken != "" {
+		t.Fatalf("expected client to use email and API key, got key %q email %q", client.APIKey, client.APIEmail)
+	}
+	client.BaseURL = ts.URL
+	if err := p.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck() error = %v", err)
+	}
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil || record.Content != "1.2.3.4" {
+		t.Errorf("GetRecordIP() = %+v, %v", record, err)
+	}
+}
+
+func TestCloudflareProvider_ProviderConfig(t *testing.T) {
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{
+		APIToken: "secret",
+		ZoneID:   "zone",
+		Records: []CloudflareRecord{
+			{RecordName: "home.example.com"},
+			{RecordName: "vpn.example.com"},
+		},
+	}}
+	got := p.ProviderConfig()
+	if got["api_token"] != providers.Redacted {
+		t.Errorf("expected api_token to be redacted, got %q", got["api_token"])
+	}
+	if got["zone_id"] != "zone" || got["record_name"] != "home.example.com,vpn.example.com" {
+		t.Errorf("unexpected config: %v", got)
+	}
+	if _, ok := got["zone_name"]; ok {
+		t.Errorf("expected no zone_name when only zone_id is set")
+	}
+	if _, ok := got["api_key"]; ok {
+		t.Errorf("expected no api_key when only api_token is set")
+	}
+}
+
+func TestCloudflareProvider_ProviderErrorFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(*providers.ProviderError) bool
+	}{
+		{"unauthorized", http.StatusUnauthorized, `{"success":false,"errors":[{"code":10000,"message":"auth"}]}`, func(pe *providers.ProviderError) bool { return pe.Unauthorized }},
+		{"forbidden", http.StatusForbidden, `{"success":false,"errors":[{"code":10000,"message":"forbidden"}]}`, func(pe *providers.ProviderError) bool { return pe.Unauthorized }},
+		{"not found", http.StatusNotFound, `{"success":false,"errors":[{"code":7003,"message":"no zone"}]}`, func(pe *providers.ProviderError) bool { return pe.NotFound }},
+		{"missing record", http.StatusOK, emptyListResponse, func(pe *providers.ProviderError) bool { return pe.NotFound }},
+		{"rate limited", http.StatusTooManyRequests, `{}`, func(pe *providers.ProviderError) bool { return pe.RateLimited && pe.Temporary }},
+		{"server error", http.StatusInternalServerError, `{}`, func(pe *providers.ProviderError) bool { return pe.Temporary && !pe.RateLimited }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			_, err := p.GetRecordIP(context.Background())
+			var pe *providers.ProviderError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ProviderError, got %T: %v", err, err)
+			}
+			if pe.Provider != "cloudflare" || !tt.check(pe) {
+				t.Errorf("unexpected classification: %+v", pe)
+			}
+		})
+	}
+}
+
+// TestCloudflareProvider_WrapErrorTypes checks that errors are classified by their cloudflare-go
+// type, whatever their message says.
+func TestCloudflareProvider_WrapErrorTypes(t *testing.T) {
+	rateLimit := cf.NewRatelimitError(&cf.Error{StatusCode: http.StatusTooManyRequests, Errors: []cf.ResponseInfo{{Message: "slow down"}}})
+	service := cf.NewServiceError(&cf.Error{StatusCode: http.StatusBadGateway, Errors: []cf.ResponseInfo{{Message: "upstream unavailable"}}})
+	tests := []struct {
+		name  string
+		err   error
+		check func(*providers.ProviderError) bool
+	}{
+		{"rate limit type", fmt.Errorf("HTTP request failed: %w", &rateLimit), func(pe *providers.ProviderError) bool { return pe.RateLimited && pe.Temporary }},
+		{"service type", fmt.Errorf("HTTP request failed: %w", &service), func(pe *providers.ProviderError) bool { return pe.Temporary && !pe.RateLimited }},
+		{"rate limit text", errors.New("exceeded available rate limit retries"), func(pe *providers.ProviderError) bool { return !pe.RateLimited && !pe.Temporary }},
+		{"retry text", errors.New("received bad gateway response (HTTP 502), please try again later"), func(pe *providers.ProviderError) bool { return !pe.Temporary }},
+	}
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pe *providers.ProviderError
+			if err := p.wrapError("get record", tt.err); !errors.As(err, &pe) || !tt.check(pe) {
+				t.Errorf("unexpected classification of %v: %+v", tt.err, pe)
+			}
+		})
+	}
+}
+
+func TestCloudflareProvider_UpdateRecordIP_PartialFailure(t *testing.T) {
+	const twoRecords = `{"success":true,"result":[` +
+		`{"id":"rec1","name":"home.example.com","type":"A","content":"1.2.3.4","ttl":120},` +
+		`{"id":"rec2","name":"home.example.com","type":"A","content":"1.2.3.5","ttl":120}],` +
+		`"result_info":{"page":1,"per_page":100,"count":2,"total_count":2,"total_pages":1}}`
+	var updated []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			w.Write([]byte(twoRecords))
+		case strings.HasSuffix(r.URL.Path, "/rec1"):
+			updated = append(updated, "rec1")
+			w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"errors":[{"code":9005,"message":"bad content"}]}`))
+		}
+	})
+
+	err := p.UpdateRecordIP(context.Background(), "5.6.7.8")
+
+	var multi *providers.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected *MultiError, got %T: %v", err, err)
+	}
+	if multi.Total != 2 || len(multi.Errors) != 1 {
+		t.Errorf("expected 1 of 2 records to fail, got %d of %d", len(multi.Errors), multi.Total)
+	}
+	if !strings.Contains(multi.Errors[0].Error(), "rec2") {
+		t.Errorf("expected failure to name record rec2, got %v", multi.Errors[0])
+	}
+	if len(updated) != 1 {
+		t.Errorf("expected rec1 to be updated despite rec2 failing, got %v", updated)
+	}
+}
+
+func TestCloudflareProvider_RecordNames(t *testing.T) {
+	ids := map[string]string{"a.example.com": "recA", "b.example.com": "recB", "c.example.com": "recC"}
+	var updated []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			name := r.URL.Query().Get("name")
+			body := strings.Replace(listResponse, "home.example.com", name, 1)
+			if name == "b.example.com" {
+				body = strings.Replace(body, "1.2.3.4", "1.2.3.5", 1)
+			}
+			w.Write([]byte(strings.Replace(body, "rec1", ids[name], 1)))
+		case strings.HasSuffix(r.URL.Path, "/recC"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"errors":[{"code":9005,"message":"bad content"}]}`))
+		default:
+			id := path.Base(r.URL.Path)
+			updated = append(updated, id)
+			w.Write([]byte(`{"success":true,"result":{"id":"` + id + `"}}`))
+		}
+	})
+	p.Cfg.RecordName = ""
+	p.Cfg.RecordNames = []string{"a.example.com", "b.example.com", "c.example.com"}
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// b.example.com holds 1.2.3.5, so the IP is left empty to force an update of every record.
+	if record.Name != "a.example.com" || record.Content != "" {
+		t.Errorf("expected the first record name with an empty IP, got %+v", record)
+	}
+
+	err = p.UpdateRecordIP(context.Background(), "5.6.7.8")
+	var multi *providers.MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected *MultiError, got %T: %v", err, err)
+	}
+	if multi.Total != 3 || len(multi.Errors) != 1 || !strings.Contains(multi.Errors[0].Error(), "recC") {
+		t.Errorf("expected only recC of 3 records to fail, got %v", multi)
+	}
+	if strings.Join(updated, ",") != "recA,recB" {
+		t.Errorf("expected recA and recB to be updated, got %v", updated)
+	}
+}
+
+func TestCloudflareProvider_UpdateRecordIP_CreateIfMissing(t *testing.T) {
+	var created map[string]any
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(emptyListResponse))
+		case http.MethodPost:
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("failed to decode create request: %v", err)
+			}
+			w.Write([]byte(`{"success":true,"result":{"id":"new","name":"home.example.com","type":"A","content":"5.6.7.8"}}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	p.Cfg.Proxied = true
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created["name"] != "home.example.com" || created["type"] != "A" || created["content"] != "5.6.7.8" || created["proxied"] != true {
+		t.Errorf("unexpected create request: %v", created)
+	}
+	if entry, ok := p.cachedRecordFor(p.Cfg.ManagedRecords()[0]); !ok || entry.record.ID != "new" {
+		t.Errorf("expected created record to be cached, got %+v", entry)
+	}
+}
+
+func TestCloudflareProvider_UpdateRecordIP_StrictMissing(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected no record to be created, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(emptyListResponse))
+	})
+	createIfMissing := false
+	p.Cfg.CreateIfMissing = &createIfMissing
+
+	err := p.UpdateRecordIP(context.Background(), "5.6.7.8")
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || !pe.NotFound || !strings.Contains(err.Error(), "record not found for update") {
+		t.Errorf("expected record not found error, got %v", err)
+	}
+}
+
+func TestCloudflareConfig_AutoCreate(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name                        string
+		createIfMissing, autoCreate *bool
+		want                        bool
+	}{
+		{"default", nil, nil, true},
+		{"auto_create false", nil, &no, false},
+		{"auto_create true", nil, &yes, true},
+		{"create_if_missing false", &no, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := CloudflareConfig{CreateIfMissing: tt.createIfMissing, AutoCreate: tt.autoCreate}
+			if got := cfg.createIfMissing(); got != tt.want {
+				t.Errorf("createIfMissing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	p, err := New(map[string]any{"enabled": true, "api_token": "token", "zone_id": "zone", "record_name": "home.example.com", "record_type": "A", "auto_create": false})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if p.Cfg.createIfMissing() {
+		t.Error("expected auto_create: false to be read from the config map")
+	}
+}
+
+func TestCloudflareProvider_GetRecordIP_ContextCanceled(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(listResponse))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.GetRecordIP(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCloudflareConfig_ValidateConfig(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name    string
+		cfg     CloudflareConfig
+		wantErr bool
+	}{
+		{"zone id", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"zone name", CloudflareConfig{APIToken: "token", ZoneName: "example.com", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"neither", CloudflareConfig{APIToken: "token", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"both", CloudflareConfig{APIToken: "token", ZoneID: "zone", ZoneName: "example.com", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"no records", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordType: "A"}, true},
+		{"records", CloudflareConfig{APIToken: "token", Records: []CloudflareRecord{{ZoneID: "a", RecordName: "a.example.com"}, {ZoneName: "b.example", RecordName: "b.example"}}, RecordType: "A"}, false},
+		{"records inherit zone", CloudflareConfig{APIToken: "token", ZoneID: "zone", Records: []CloudflareRecord{{RecordName: "a.example.com"}}, RecordType: "A"}, false},
+		{"record without zone", CloudflareConfig{APIToken: "token", Records: []CloudflareRecord{{RecordName: "a.example.com"}}, RecordType: "A"}, true},
+		{"record without name", CloudflareConfig{APIToken: "token", Records: []CloudflareRecord{{ZoneID: "zone"}}, RecordType: "A"}, true},
+		{"record names", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordNames: []string{"a.example.com", "b.example.com"}, RecordType: "A"}, false},
+		{"record names and record_name", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "a.example.com", RecordNames: []string{"b.example.com"}, RecordType: "A"}, true},
+		{"record names and records", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordNames: []string{"a.example.com"}, Records: []CloudflareRecord{{RecordName: "b.example.com"}}, RecordType: "A"}, true},
+		{"records and record_name", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "x", Records: []CloudflareRecord{{RecordName: "a"}}, RecordType: "A"}, true},
+		{"no credentials", CloudflareConfig{ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"api key", CloudflareConfig{Email: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"api key without email", CloudflareConfig{APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"api key with api_email", CloudflareConfig{APIEmail: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"email and api_email agree", CloudflareConfig{Email: "user@example.com", APIEmail: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"email and api_email disagree", CloudflareConfig{Email: "user@example.com", APIEmail: "other@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"api key and token", CloudflareConfig{APIToken: "token", Email: "user@example.com", APIKey: "key", ZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"auto_create matches create_if_missing", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "home.example.com", CreateIfMissing: &no, AutoCreate: &no, RecordType: "A"}, false},
+		{"auto_create disagrees with create_if_missing", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "home.example.com", CreateIfMissing: &yes, AutoCreate: &no, RecordType: "A"}, true},
+		{"no record type", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "home.example.com"}, true},
+		{"records entry without type", CloudflareConfig{APIToken: "token", ZoneID: "zone", Records: []CloudflareRecord{{RecordName: "a.example.com", RecordType: "A"}, {RecordName: "b.example.com"}}}, true},
+		{"records entries with own type", CloudflareConfig{APIToken: "token", ZoneID: "zone", Records: []CloudflareRecord{{RecordName: "a.example.com", RecordType: "A"}, {RecordName: "b.example.com", RecordType: "AAAA"}}}, false},
+		{"dual stack without type", CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordName: "home.example.com", DualStack: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.ValidateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := New(map[string]any{"enabled": true, "api_token": "token"}); err == nil {
+		t.Errorf("expected New to reject an enabled provider without a zone")
+	}
+	if _, err := New(map[string]any{"enabled": false}); err != nil {
+		t.Errorf("expected New to skip validation for a disabled provider, got %v", err)
+	}
+}
+
+func TestCloudflareConfig_ValidatedByConfigPackage(t *testing.T) {
+	cfg := &config.Config{Interval: time.Hour, IPSource: "https://api.ipify.org", Providers: map[string]any{
+		"cloudflare": map[string]any{"enabled": true, "api_key": "key", "zone_id": "zone", "record_name": "home.example.com"},
+	}}
+	if err := config.ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "cloudflare: email (or api_email) is required with api_key") {
+		t.Errorf("expected config.ValidateConfig to apply CloudflareConfig.ValidateConfig, got %v", err)
+	}
+}
+
+func TestCloudflareProvider_ZoneNameLookupOnce(t *testing.T) {
+	zoneLookups := 0
+	var recordPaths []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/zones") {
+			zoneLookups++
+			if got := r.URL.Query().Get("name"); got != "example.com" {
+				t.Errorf("expected zone lookup by name example.com, got %q", got)
+			}
+			w.Write([]byte(`{"success":true,"result":[{"id":"zone-from-name","name":"example.com"}],"result_info":{"page":1,"per_page":50,"count":1,"total_count":1,"total_pages":1}}`))
+			return
+		}
+		recordPaths = append(recordPaths, r.URL.Path)
+		w.Write([]byte(listResponse))
+	})
+	p.Cfg.ZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+
+	for i := 0; i < 2; i++ {
+		if _, err := p.GetRecordIP(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if zoneLookups != 1 {
+		t.Errorf("expected exactly one zone lookup, got %d", zoneLookups)
+	}
+	if p.zoneIDs["example.com"] != "zone-from-name" {
+		t.Errorf("expected resolved zone ID to be cached, got %q", p.zoneIDs["example.com"])
+	}
+	for _, path := range recordPaths {
+		if !strings.Contains(path, "/zones/zone-from-name/") {
+			t.Errorf("expected record request for the resolved zone, got %q", path)
+		}
+	}
+}
+
+func TestCloudflareProvider_ZoneIDTakesPrecedence(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/zones") {
+			t.Errorf("expected no zone lookup when zone_id is set")
+		}
+		if !strings.Contains(r.URL.Path, "/zones/zone/") {
+			t.Errorf("expected record request for zone_id, got %q", r.URL.Path)
+		}
+		w.Write([]byte(listResponse))
+	})
+	p.Cfg.ZoneName = "example.com"
+	if _, err := p.GetRecordIP(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCloudflareProvider_ZoneNameLookupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no zones", `{"success":true,"result":[],"result_info":{"page":1,"per_page":50,"count":0,"total_count":0,"total_pages":0}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			})
+			p.Cfg.ZoneID = ""
+			p.Cfg.ZoneName = "example.com"
+			if _, err := p.GetRecordIP(context.Background()); err == nil || !strings.Contains(err.Error(), "example.com") {
+				t.Errorf("expected error naming the zone, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCloudflareProvider_ZoneNameSeveralMatches(t *testing.T) {
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"result":[{"id":"a","name":"example.com"},{"id":"b","name":"example.com"}],"result_info":{"page":1,"per_page":50,"count":2,"total_count":2,"total_pages":1}}`))
+	})
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	p.SetLogger(&l)
+
+	id, err := p.zoneID(context.Background(), CloudflareRecord{ZoneName: "example.com"})
+	if err != nil || id != "a" {
+		t.Errorf("expected the first matching zone, got %q (err %v)", id, err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"level":"warn"`) || !strings.Contains(out, "a, b") {
+		t.Errorf("expected a warning listing both zones, got %q", out)
+	}
+}
+
+func TestCloudflareConfig_ManagedRecords(t *testing.T) {
+	single := CloudflareConfig{ZoneID: "zone", RecordName: "home.example.com", RecordType: "A", Proxied: true}
+	got := single.ManagedRecords()
+	if len(got) != 1 || got[0].ZoneID != "zone" || got[0].RecordName != "home.example.com" || got[0].RecordType != "A" || !*got[0].Proxied {
+		t.Errorf("unexpected single-record alias: %+v", got)
+	}
+
+	multi := CloudflareConfig{ZoneName: "example.com", RecordType: "A", Records: []CloudflareRecord{
+		{RecordName: "a.example.com"},
+		{ZoneID: "other", RecordName: "b.example.org", RecordType: "AAAA", TTL: 120},
+	}}
+	got = multi.ManagedRecords()
+	if got[0].ZoneName != "example.com" || got[0].RecordType != "A" || *got[0].Proxied {
+		t.Errorf("expected first record to inherit zone, type, and proxied, got %+v", got[0])
+	}
+	if got[1].ZoneID != "other" || got[1].ZoneName != "" || got[1].RecordType != "AAAA" || got[1].TTL != 120 {
+		t.Errorf("expected second record to keep its own settings, got %+v", got[1])
+	}
+}
+
+func TestCloudflareProvider_MultipleZones(t *testing.T) {
+	const zoneB = `{"success":true,"result":[{"id":"recB","name":"b.example.org","type":"A","content":"1.2.3.4","ttl":120}],"result_info":{"page":1,"per_page":100,"count":1,"total_count":1,"total_pages":1}}`
+	var updates []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/zones/zoneA/"):
+			w.Write([]byte(listResponse))
+		case r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/zones/zoneB/"):
+			w.Write([]byte(zoneB))
+		case strings.HasSuffix(r.URL.Path, "/zones/zoneA/dns_records/rec1"):
+			updates = append(updates, "zoneA/rec1")
+			w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+		case strings.HasSuffix(r.URL.Path, "/zones/zoneB/dns_records/recB"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"success":false,"errors":[{"code":9005,"message":"bad content"}]}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	p.Cfg = &CloudflareConfig{Enabled: true, RecordType: "A", Records: []CloudflareRecord{
+		{ZoneID: "zoneA", RecordName: "home.example.com"},
+		{ZoneID: "zoneB", RecordName: "b.example.org"},
+	}}
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil || record.Content != "1.2.3.4" {
+		t.Fatalf("expected first record's IP 1.2.3.4, got %+v (err %v)", record, err)
+	}
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected 2 managed records, got %d (err %v)", len(records), err)
+	}
+
+	err = p.UpdateRecordIP(context.Background(), "5.6.7.8")
+	var multi *providers.MultiError
+	if !errors.As(err, &multi) || multi.Total != 2 || len(multi.Errors) != 1 {
+		t.Fatalf("expected 1 of 2 records to fail, got %v", err)
+	}
+	if !strings.Contains(multi.Errors[0].Error(), "b.example.org") {
+		t.Errorf("expected failure to name b.example.org, got %v", multi.Errors[0])
+	}
+	if len(updates) != 1 {
+		t.Errorf("expected zoneA record to be updated, got %v", updates)
+	}
+}
+
+func TestCloudflareProvider_PerRecordProxied(t *testing.T) {
+	const twoNames = `{"success":true,"result":[` +
+		`{"id":"%s","name":"%s","type":"A","content":"1.2.3.4","ttl":120}],` +
+		`"result_info":{"page":1,"per_page":100,"count":1,"total_count":1,"total_pages":1}}`
+	proxiedByID := map[string]*bool{}
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			name := r.URL.Query().Get("name")
+			id := strings.SplitN(name, ".", 2)[0]
+			w.Write([]byte(fmt.Sprintf(twoNames, id, name)))
+			return
+		}
+		var body struct {
+			Proxied *bool `json:"proxied"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode update body: %v", err)
+		}
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		proxiedByID[id] = body.Proxied
+		w.Write([]byte(`{"success":true,"result":{"id":"` + id + `"}}`))
+	})
+	proxied, direct := true, false
+	p.Cfg = &CloudflareConfig{Enabled: true, ZoneID: "zone", RecordType: "A", Proxied: true, Records: []CloudflareRecord{
+		{RecordName: "home.example.com", Proxied: &direct},
+		{RecordName: "cdn.example.com", Proxied: &proxied},
+		{RecordName: "www.example.com"}, // Inherits the top-level default
+	}}
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]bool{"home": false, "cdn": true, "www": true}
+	for id, w := range want {
+		got := proxiedByID[id]
+		if got == nil || *got != w {
+			t.Errorf("record %s: expected proxied=%v, got %v", id, w, got)
+		}
+	}
+}
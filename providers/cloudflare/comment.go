@@ -0,0 +1,77 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultCommentTemplate is the record comment used when record_comment_template is not set.
+const DefaultCommentTemplate = "managed by dynago; last updated {{.Timestamp}} to {{.IP}}"
+
+// CommentData is the data passed to record_comment_template.
+type CommentData struct {
+	Timestamp string // Time of the update in RFC 3339 format (UTC)
+	IP        string // New record content
+	Provider  string // Provider name ("cloudflare")
+	Record    string // Fully qualified record name
+}
+
+// commentTemplate parses the configured comment template, or DefaultCommentTemplate if unset.
+func (cfg *CloudflareConfig) commentTemplate() (*template.Template, error) {
+	text := cfg.RecordCommentTemplate
+	if text == "" {
+		text = DefaultCommentTemplate
+	}
+	return template.New("comment").Option("missingkey=error").Parse(text)
+}
+
+// renderComment renders the record comment for an update of record to ip.
+func (cfg *CloudflareConfig) renderComment(ip, record string) (string, error) {
+	tmpl, err := cfg.commentTemplate()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	err = tmpl.Execute(&b, CommentData{
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		IP:        ip,
+		Provider:  "cloudflare",
+		Record:    record,
+	})
+	return b.String(), err
+}
+
+// mergeTags returns the existing tags followed by any configured tags not already present.
+//
+// Existing tags are always preserved so tags added outside dynago are not removed.
+func mergeTags(existing, configured []string) []string {
+	merged := make([]string, 0, len(existing)+len(configured))
+	seen := make(map[string]bool, len(existing)+len(configured))
+	for _, tag := range append(append([]string{}, existing...), configured...) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
@@ -0,0 +1,91 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCloudflareConfig_RenderComment(t *testing.T) {
+	cfg := &CloudflareConfig{}
+	got, err := cfg.renderComment("5.6.7.8", "home.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(got, "managed by dynago; last updated ") || !strings.HasSuffix(got, " to 5.6.7.8") {
+		t.Errorf("unexpected default comment: %q", got)
+	}
+
+	cfg.RecordCommentTemplate = "{{.Provider}} set {{.Record}} to {{.IP}}"
+	got, err = cfg.renderComment("5.6.7.8", "home.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "cloudflare set home.example.com to 5.6.7.8" {
+		t.Errorf("unexpected comment: %q", got)
+	}
+}
+
+func TestCloudflareConfig_ValidateCommentTemplate(t *testing.T) {
+	cfg := &CloudflareConfig{ZoneID: "zone", RecordName: "home.example.com", RecordCommentTemplate: "{{.IP"}
+	if err := cfg.ValidateConfig(); err == nil {
+		t.Errorf("expected error for malformed record_comment_template")
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := mergeTags([]string{"env:home", "owner:me"}, []string{"owner:me", "managed:dynago"})
+	want := []string{"env:home", "owner:me", "managed:dynago"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestCloudflareProvider_UpdateRecordIP_CommentAndTags(t *testing.T) {
+	var body struct {
+		Comment string   `json:"comment"`
+		Tags    []string `json:"tags"`
+	}
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"success":true,"result":[{"id":"rec1","name":"home.example.com","type":"A","content":"1.2.3.4","tags":["env:home"]}],"result_info":{"page":1,"per_page":100,"count":1,"total_count":1,"total_pages":1}}`))
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+	})
+	p.Cfg.RecordCommentTemplate = "dynago {{.IP}}"
+	p.Cfg.RecordTags = []string{"managed:dynago"}
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.Comment != "dynago 5.6.7.8" {
+		t.Errorf("expected comment %q, got %q", "dynago 5.6.7.8", body.Comment)
+	}
+	if want := []string{"env:home", "managed:dynago"}; !reflect.DeepEqual(body.Tags, want) {
+		t.Errorf("expected tags %v, got %v", want, body.Tags)
+	}
+}
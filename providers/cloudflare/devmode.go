@@ -0,0 +1,111 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"time"
+
+	cf "github.com/cloudflare/cloudflare-go"
+)
+
+// DefaultDevModeDuration is how long development mode stays on when dev_mode_duration is not set.
+const DefaultDevModeDuration = 3 * time.Minute
+
+// devModeTimeout bounds the API call that turns development mode back off.
+const devModeTimeout = 30 * time.Second
+
+// devTimer is a pending development mode disable for a zone.
+type devTimer struct {
+	timer  *time.Timer // Fires disableDevMode after the development mode duration
+	client *cf.API     // Client that enabled development mode
+}
+
+// devModeDuration returns the configured development mode duration or the default.
+func (c *CloudflareProvider) devModeDuration() time.Duration {
+	if c.Cfg.DevModeDuration > 0 {
+		return c.Cfg.DevModeDuration
+	}
+	return DefaultDevModeDuration
+}
+
+// enableDevMode turns on development mode for the zone and schedules it to be turned off.
+//
+// If a disable is already pending for the zone, it is rescheduled from now. Failures are logged
+// and never block the DNS update.
+func (c *CloudflareProvider) enableDevMode(ctx context.Context, client *cf.API, zoneID string) {
+	_, err := client.UpdateZoneSetting(ctx, cf.ZoneIdentifier(zoneID), cf.UpdateZoneSettingParams{
+		Name:  "development_mode",
+		Value: "on",
+	})
+	if err != nil {
+		c.log().Warn().Msgf("cloudflare: failed to enable development mode for zone %s: %v", zoneID, err)
+		return
+	}
+	duration := c.devModeDuration()
+	c.log().Info().Msgf("cloudflare: development mode enabled for zone %s", zoneID)
+
+	c.devMu.Lock()
+	defer c.devMu.Unlock()
+	if c.devTimers == nil {
+		c.devTimers = make(map[string]devTimer)
+	}
+	if t, ok := c.devTimers[zoneID]; ok {
+		t.timer.Stop()
+	}
+	timer := time.AfterFunc(duration, func() {
+		c.devMu.Lock()
+		delete(c.devTimers, zoneID)
+		c.devMu.Unlock()
+		c.disableDevMode(client, zoneID)
+	})
+	c.devTimers[zoneID] = devTimer{timer: timer, client: client}
+	c.log().Info().Msgf("cloudflare: development mode for zone %s will be disabled in %s", zoneID, duration)
+}
+
+// disablePendingDevMode stops every pending development mode timer and turns development mode off
+// for those zones right away, so it is not left on when dynago exits or drops the provider.
+func (c *CloudflareProvider) disablePendingDevMode() {
+	c.devMu.Lock()
+	pending := c.devTimers
+	c.devTimers = nil
+	c.devMu.Unlock()
+	for zoneID, t := range pending {
+		// A timer that already fired is disabling development mode itself.
+		if t.timer.Stop() {
+			c.disableDevMode(t.client, zoneID)
+		}
+	}
+}
+
+// disableDevMode turns development mode off for the zone. It runs from the timer set by
+// enableDevMode, or from Close.
+func (c *CloudflareProvider) disableDevMode(client *cf.API, zoneID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), devModeTimeout)
+	defer cancel()
+	_, err := client.UpdateZoneSetting(ctx, cf.ZoneIdentifier(zoneID), cf.UpdateZoneSettingParams{
+		Name:  "development_mode",
+		Value: "off",
+	})
+	if err != nil {
+		c.log().Warn().Msgf("cloudflare: failed to disable development mode for zone %s: %v", zoneID, err)
+		return
+	}
+	c.log().Info().Msgf("cloudflare: development mode disabled for zone %s", zoneID)
+}
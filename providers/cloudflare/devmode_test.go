@@ -0,0 +1,118 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloudflareProvider_ToggleDevMode(t *testing.T) {
+	settings := make(chan string, 4)
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/settings/development_mode"):
+			var body struct {
+				Value string `json:"value"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			settings <- body.Value
+			w.Write([]byte(`{"success":true,"result":{"id":"development_mode","value":"` + body.Value + `"}}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(listResponse))
+		default:
+			w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+		}
+	})
+	p.Cfg.ToggleDevMode = true
+	p.Cfg.DevModeDuration = 50 * time.Millisecond
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"on", "off"} {
+		select {
+		case got := <-settings:
+			if got != want {
+				t.Errorf("expected development_mode %q, got %q", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for development_mode %q", want)
+		}
+	}
+}
+
+// TestCloudflareProvider_CloseDisablesDevMode checks that Close turns development mode off
+// without waiting for the timer.
+func TestCloudflareProvider_CloseDisablesDevMode(t *testing.T) {
+	settings := make(chan string, 4)
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/settings/development_mode"):
+			var body struct {
+				Value string `json:"value"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			settings <- body.Value
+			w.Write([]byte(`{"success":true,"result":{"id":"development_mode","value":"` + body.Value + `"}}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(listResponse))
+		default:
+			w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+		}
+	})
+	p.Cfg.ToggleDevMode = true
+	p.Cfg.DevModeDuration = time.Hour
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-settings; got != "on" {
+		t.Fatalf("expected development_mode on, got %q", got)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case got := <-settings:
+		if got != "off" {
+			t.Errorf("expected development_mode off, got %q", got)
+		}
+	default:
+		t.Fatal("Close did not disable development mode")
+	}
+	if len(p.devTimers) != 0 {
+		t.Errorf("expected no pending timers after Close, got %d", len(p.devTimers))
+	}
+}
+
+func TestCloudflareProvider_DevModeDuration(t *testing.T) {
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{}}
+	if got := p.devModeDuration(); got != DefaultDevModeDuration {
+		t.Errorf("expected default %s, got %s", DefaultDevModeDuration, got)
+	}
+	p.Cfg.DevModeDuration = time.Minute
+	if got := p.devModeDuration(); got != time.Minute {
+		t.Errorf("expected 1m, got %s", got)
+	}
+}
@@ -0,0 +1,91 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"slices"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// RecordTypes returns the distinct types of the managed records, in configuration order.
+func (c *CloudflareProvider) RecordTypes() []string {
+	var types []string
+	for _, rec := range c.Cfg.ManagedRecords() {
+		if !slices.Contains(types, rec.RecordType) {
+			types = append(types, rec.RecordType)
+		}
+	}
+	return types
+}
+
+// ForRecordType returns a *RecordTypeView of the provider restricted to records of recordType.
+func (c *CloudflareProvider) ForRecordType(recordType string) providers.DNSProvider {
+	return &RecordTypeView{CloudflareProvider: c, RecordType: recordType}
+}
+
+// RecordTypeView is a CloudflareProvider that reads and updates only the records of one type,
+// such as the AAAA records of a dual_stack config.
+//
+// It shares the provider's client, record cache, and rate limit state. Its ProviderName includes
+// the record type, e.g. "cloudflare/AAAA", so each type is reported separately.
+type RecordTypeView struct {
+	*CloudflareProvider
+	RecordType string
+}
+
+// records returns the managed records of the view's type.
+func (v *RecordTypeView) records() []CloudflareRecord {
+	var records []CloudflareRecord
+	for _, rec := range v.Cfg.ManagedRecords() {
+		if rec.RecordType == v.RecordType {
+			records = append(records, rec)
+		}
+	}
+	return records
+}
+
+// ProviderName returns "cloudflare/" followed by the record type.
+func (v *RecordTypeView) ProviderName() string {
+	return v.CloudflareProvider.ProviderName() + "/" + v.RecordType
+}
+
+// RecordNames returns the names of the managed records of the view's type.
+func (v *RecordTypeView) RecordNames() []string { return recordNames(v.records()) }
+
+// GetRecordIP fetches the current IP address of the first record of the view's type.
+func (v *RecordTypeView) GetRecordIP(ctx context.Context) (*providers.DNSRecord, error) {
+	return v.recordIP(ctx, v.records())
+}
+
+// GetRecordTTL fetches the current TTL (in seconds) of the first record of the view's type.
+func (v *RecordTypeView) GetRecordTTL(ctx context.Context) (int64, error) {
+	return v.recordTTL(ctx, v.records())
+}
+
+// UpdateRecordIP updates every record of the view's type to ip.
+func (v *RecordTypeView) UpdateRecordIP(ctx context.Context, ip string) error {
+	return v.updateRecords(ctx, v.records(), ip)
+}
+
+// ListManagedRecords returns the records of the view's type as currently held in their zones.
+func (v *RecordTypeView) ListManagedRecords(ctx context.Context) ([]providers.DNSRecord, error) {
+	return v.listManagedRecords(ctx, v.records())
+}
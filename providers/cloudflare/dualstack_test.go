@@ -0,0 +1,97 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+func TestCloudflareConfig_DualStack(t *testing.T) {
+	cfg := CloudflareConfig{APIToken: "token", ZoneID: "zone", RecordNames: []string{"a.example.com", "b.example.com"}, DualStack: true}
+	var got []string
+	for _, rec := range cfg.ManagedRecords() {
+		got = append(got, rec.RecordType+" "+rec.RecordName)
+	}
+	want := []string{"A a.example.com", "AAAA a.example.com", "A b.example.com", "AAAA b.example.com"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ManagedRecords() = %v, want %v", got, want)
+	}
+	p := &CloudflareProvider{Cfg: &cfg}
+	view := &RecordTypeView{CloudflareProvider: p, RecordType: "AAAA"}
+	for _, namer := range []providers.RecordNamer{p, view} {
+		if names := namer.RecordNames(); !slices.Equal(names, cfg.RecordNames) {
+			t.Errorf("RecordNames() = %v, want %v", names, cfg.RecordNames)
+		}
+	}
+	if err := cfg.ValidateConfig(); err != nil {
+		t.Errorf("ValidateConfig() error = %v", err)
+	}
+	cfg.RecordType = "CNAME"
+	if err := cfg.ValidateConfig(); err == nil {
+		t.Error("expected dual_stack with record_type CNAME to be rejected")
+	}
+}
+
+func TestCloudflareProvider_RecordTypeView(t *testing.T) {
+	var updated []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			recordType := r.URL.Query().Get("type")
+			body := strings.Replace(listResponse, `"type":"A"`, `"type":"`+recordType+`"`, 1)
+			if recordType == "AAAA" {
+				body = strings.Replace(body, "1.2.3.4", "2001:db8::1", 1)
+			}
+			w.Write([]byte(strings.Replace(body, "rec1", "rec"+recordType, 1)))
+			return
+		}
+		id := path.Base(r.URL.Path)
+		updated = append(updated, id)
+		w.Write([]byte(`{"success":true,"result":{"id":"` + id + `"}}`))
+	})
+	p.Cfg.DualStack = true
+
+	if got := p.RecordTypes(); !slices.Equal(got, []string{"A", "AAAA"}) {
+		t.Fatalf("RecordTypes() = %v, want [A AAAA]", got)
+	}
+	view := p.ForRecordType("AAAA")
+	if view.ProviderName() != "cloudflare/AAAA" {
+		t.Errorf("expected view name cloudflare/AAAA, got %s", view.ProviderName())
+	}
+	record, err := view.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Type != "AAAA" || record.Content != "2001:db8::1" {
+		t.Errorf("expected the AAAA record, got %+v", record)
+	}
+	if err := view.UpdateRecordIP(context.Background(), "2001:db8::2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(updated, []string{"recAAAA"}) {
+		t.Errorf("expected only the AAAA record to be updated, got %v", updated)
+	}
+}
@@ -0,0 +1,225 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+	cf "github.com/cloudflare/cloudflare-go"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate-limited GetRecordIP or UpdateRecordIP is retried.
+	maxRateLimitRetries = 3
+
+	// maxRateLimitWait is the longest Retry-After deadline waited for within a single call. Longer
+	// deadlines fail fast, and later calls are refused until the deadline passes.
+	maxRateLimitWait = 10 * time.Second
+)
+
+// RateLimitError reports that Cloudflare was still rate limiting requests after every retry.
+type RateLimitError struct {
+	Attempts int   // Number of API attempts made
+	Err      error // Error from the last attempt
+}
+
+// Error returns the number of attempts and the last error.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("still rate limited after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the error from the last attempt.
+func (e *RateLimitError) Unwrap() error { return e.Err }
+
+// retryRateLimited calls fn, retrying up to maxRateLimitRetries times while it fails with a
+// rate-limited ProviderError, which may be wrapped.
+//
+// Each retry waits until the recorded Retry-After deadline, or backs off exponentially from
+// rateLimitBackoff if Cloudflare gave none. The original error is returned as is if the deadline is
+// beyond maxRateLimitWait or ctx is cancelled; once retries are exhausted, a rate-limited
+// ProviderError wrapping a *RateLimitError is returned.
+//
+// A *providers.MultiError from a partially failed update is never retried, so records that were
+// already updated are not written again.
+func (c *CloudflareProvider) retryRateLimited(ctx context.Context, op string, fn func() error) error {
+	backoff := c.rateLimitBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		// Check for a MultiError first: errors.As would otherwise find a rate-limited record in it.
+		var multi *providers.MultiError
+		var pe *providers.ProviderError
+		if errors.As(err, &multi) || !errors.As(err, &pe) || !pe.RateLimited {
+			return err
+		}
+		if attempt > maxRateLimitRetries {
+			return &providers.ProviderError{
+				Provider:    c.ProviderName(),
+				Op:          op,
+				Err:         &RateLimitError{Attempts: attempt, Err: pe.Err},
+				Temporary:   true,
+				RateLimited: true,
+			}
+		}
+		wait := c.rateLimitRemaining()
+		if wait <= 0 {
+			wait = backoff
+			backoff *= 2
+		}
+		if wait > maxRateLimitWait {
+			return err
+		}
+		c.log().Warn().Msgf("cloudflare: %s rate limited, retrying in %s (retry %d of %d)", op, wait, attempt, maxRateLimitRetries)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// rateLimitRemaining returns how long until the recorded Retry-After deadline, or zero if none is pending.
+func (c *CloudflareProvider) rateLimitRemaining() time.Duration {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return time.Until(c.rateLimitUntil)
+}
+
+// rateLimitTransport records the Retry-After deadline of every HTTP 429 response, and reports 429
+// and 5xx responses as cloudflare-go's *cf.RatelimitError and *cf.ServiceError.
+//
+// cloudflare-go retries those responses itself and, once its retries run out, returns an untyped
+// error for them. Failing the round trip with a typed error instead means the SDK returns that
+// error, so wrapError can classify it with errors.As.
+type rateLimitTransport struct {
+	base     http.RoundTripper // Transport that performs the request
+	provider *CloudflareProvider
+}
+
+// RoundTrip performs the request and records any rate limit reported in the response.
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			t.provider.setRateLimited(wait)
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// responseError closes resp's body and returns the cloudflare-go error for its 429 or 5xx status,
+// carrying the API's error messages if the body holds any.
+func responseError(resp *http.Response) error {
+	defer resp.Body.Close()
+	apiErr := &cf.Error{StatusCode: resp.StatusCode, RayID: resp.Header.Get("cf-ray")}
+	var body cf.Response
+	if data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10)); err == nil && json.Unmarshal(data, &body) == nil {
+		apiErr.Errors = body.Errors
+	}
+	if len(apiErr.Errors) == 0 {
+		apiErr.Errors = []cf.ResponseInfo{{Message: fmt.Sprintf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))}}
+	}
+	for _, e := range apiErr.Errors {
+		apiErr.ErrorCodes = append(apiErr.ErrorCodes, e.Code)
+		apiErr.ErrorMessages = append(apiErr.ErrorMessages, e.Message)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		apiErr.Type = cf.ErrorTypeRateLimit
+		err := cf.NewRatelimitError(apiErr)
+		return &err
+	}
+	apiErr.Type = cf.ErrorTypeService
+	err := cf.NewServiceError(apiErr)
+	return &err
+}
+
+// newHTTPClient returns an HTTP client whose transport records Cloudflare rate limits on c.
+func (c *CloudflareProvider) newHTTPClient() *http.Client {
+	return &http.Client{Transport: &rateLimitTransport{
+		base:     http.DefaultTransport.(*http.Transport).Clone(),
+		provider: c,
+	}}
+}
+
+// setRateLimited blocks API calls for wait and logs the rate limit.
+func (c *CloudflareProvider) setRateLimited(wait time.Duration) {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	until := time.Now().Add(wait)
+	if until.After(c.rateLimitUntil) {
+		c.rateLimitUntil = until
+	}
+	c.log().Warn().Msgf("cloudflare: API rate limit exceeded, pausing requests for %s", wait)
+}
+
+// checkRateLimit returns a RateLimited ProviderError for op if a Retry-After deadline has not passed.
+func (c *CloudflareProvider) checkRateLimit(op string) error {
+	c.rateMu.Lock()
+	until := c.rateLimitUntil
+	c.rateMu.Unlock()
+	if !time.Now().Before(until) {
+		return nil
+	}
+	return &providers.ProviderError{
+		Provider:    c.ProviderName(),
+		Op:          op,
+		Err:         fmt.Errorf("%w: retry after %s", providers.ErrRateLimited, until.Format(time.RFC3339)),
+		Temporary:   true,
+		RateLimited: true,
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date relative to now.
+//
+// Returns false if the header is missing, malformed, or already in the past.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs > 0 {
+			return time.Duration(secs) * time.Second, true
+		}
+		return 0, false
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := t.Sub(now); wait > 0 {
+			return wait, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,273 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+	cf "github.com/cloudflare/cloudflare-go"
+	"github.com/rs/zerolog"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"30", 30 * time.Second, true},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, false},
+		{"", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCloudflareProvider_RateLimitRetryAfter(t *testing.T) {
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"errors":[{"code":971,"message":"Please wait and consider throttling your request speed"}]}`))
+	})
+
+	if _, err := p.GetRecordIP(context.Background()); !errors.Is(err, providers.ErrRateLimited) {
+		t.Fatalf("expected rate limit error from the 429 response, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 API call, got %d", calls)
+	}
+
+	if _, err := p.GetRecordIP(context.Background()); !errors.Is(err, providers.ErrRateLimited) {
+		t.Errorf("expected GetRecordIP to fail fast while rate limited, got %v", err)
+	}
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); !errors.Is(err, providers.ErrRateLimited) {
+		t.Errorf("expected UpdateRecordIP to fail fast while rate limited, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no API calls while rate limited, got %d", calls-1)
+	}
+
+	p.rateMu.Lock()
+	p.rateLimitUntil = time.Now().Add(-time.Second)
+	p.rateMu.Unlock()
+	p.GetRecordIP(context.Background())
+	if calls != 2 {
+		t.Errorf("expected API calls to resume after Retry-After, got %d calls", calls)
+	}
+}
+
+func TestCloudflareProvider_RateLimitRetries(t *testing.T) {
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"success":false,"errors":[{"code":971,"message":"Please wait and consider throttling your request speed"}]}`))
+			return
+		}
+		w.Write([]byte(listResponse))
+	})
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("expected GetRecordIP to succeed after retrying, got %v", err)
+	}
+	if record.Content != "1.2.3.4" {
+		t.Errorf("expected IP 1.2.3.4, got %s", record.Content)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 API calls, got %d", calls)
+	}
+}
+
+func TestCloudflareProvider_GetRecordTTLRateLimited(t *testing.T) {
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"success":false,"errors":[{"code":971,"message":"Please wait and consider throttling your request speed"}]}`))
+			return
+		}
+		w.Write([]byte(listResponse))
+	})
+
+	if _, err := p.GetRecordTTL(context.Background()); err != nil {
+		t.Fatalf("expected GetRecordTTL to succeed after retrying, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 API calls, got %d", calls)
+	}
+
+	p.rateMu.Lock()
+	p.rateLimitUntil = time.Now().Add(time.Hour)
+	p.rateMu.Unlock()
+	if _, err := p.GetRecordTTL(context.Background()); !errors.Is(err, providers.ErrRateLimited) {
+		t.Errorf("expected GetRecordTTL to fail fast while rate limited, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected no API calls while rate limited, got %d", calls-2)
+	}
+}
+
+func TestCloudflareProvider_RateLimitRetryAfterShort(t *testing.T) {
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"success":false,"errors":[{"code":971,"message":"Please wait and consider throttling your request speed"}]}`))
+			return
+		}
+		w.Write([]byte(listResponse))
+	})
+
+	start := time.Now()
+	if _, err := p.GetRecordIP(context.Background()); err != nil {
+		t.Fatalf("expected GetRecordIP to succeed after Retry-After, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("expected the retry to wait for Retry-After, only waited %s", elapsed)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 API calls, got %d", calls)
+	}
+}
+
+func TestCloudflareProvider_RateLimitRetriesExhausted(t *testing.T) {
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"errors":[{"code":971,"message":"Please wait and consider throttling your request speed"}]}`))
+	})
+
+	err := p.UpdateRecordIP(context.Background(), "5.6.7.8")
+	var rlErr *RateLimitError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+	if rlErr.Attempts != maxRateLimitRetries+1 {
+		t.Errorf("expected %d attempts, got %d", maxRateLimitRetries+1, rlErr.Attempts)
+	}
+	var cfErr *cf.RatelimitError
+	if !errors.As(rlErr, &cfErr) || cfErr.Type() != cf.ErrorTypeRateLimit {
+		t.Errorf("expected the last attempt's *cf.RatelimitError, got %v", rlErr.Err)
+	}
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || !pe.RateLimited || !pe.Temporary {
+		t.Errorf("expected a temporary rate-limited ProviderError, got %v", err)
+	}
+	if calls != maxRateLimitRetries+1 {
+		t.Errorf("expected %d API calls, got %d", maxRateLimitRetries+1, calls)
+	}
+}
+
+// TestCloudflareProvider_RateLimitRetriesAnyMessage checks that a 429 is retried whatever its body
+// says, since rate limits are recognised by the error type rather than its text.
+func TestCloudflareProvider_RateLimitRetriesAnyMessage(t *testing.T) {
+	bodies := []string{
+		`{"success":false,"errors":[{"code":10000,"message":"Too many requests from this client"}]}`,
+		`Too Many Requests`,
+	}
+	calls := 0
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls <= len(bodies) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(bodies[calls-1]))
+			return
+		}
+		w.Write([]byte(listResponse))
+	})
+
+	if _, err := p.GetRecordIP(context.Background()); err != nil {
+		t.Fatalf("expected GetRecordIP to succeed after retrying, got %v", err)
+	}
+	if calls != len(bodies)+1 {
+		t.Errorf("expected %d API calls, got %d", len(bodies)+1, calls)
+	}
+}
+
+func TestCloudflareProvider_RetryRateLimitedWrapped(t *testing.T) {
+	p := &CloudflareProvider{Cfg: &CloudflareConfig{}, rateLimitBackoff: time.Millisecond}
+	limited := &providers.ProviderError{Provider: "cloudflare", Op: "update", Err: errors.New("429"), RateLimited: true}
+
+	calls := 0
+	err := p.retryRateLimited(context.Background(), "update", func() error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("update home.example.com: %w", limited)
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected a wrapped rate-limited error to be retried once, got %d calls (err %v)", calls, err)
+	}
+
+	calls = 0
+	multi := &providers.MultiError{Errors: []error{limited}, Total: 2}
+	err = p.retryRateLimited(context.Background(), "update", func() error {
+		calls++
+		return multi
+	})
+	if err != multi || calls != 1 {
+		t.Errorf("expected a MultiError not to be retried, got %d calls (err %v)", calls, err)
+	}
+}
+
+func TestCloudflareProvider_SetLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := zerolog.New(&buf).With().Str("provider", "cloudflare").Logger()
+	var p providers.DNSProvider = &CloudflareProvider{Cfg: &CloudflareConfig{}}
+	setter, ok := p.(providers.LoggerSetter)
+	if !ok {
+		t.Fatal("expected CloudflareProvider to implement providers.LoggerSetter")
+	}
+	setter.SetLogger(&l)
+
+	p.(*CloudflareProvider).setRateLimited(time.Second)
+	if out := buf.String(); !strings.Contains(out, `"provider":"cloudflare"`) || !strings.Contains(out, "rate limit exceeded") {
+		t.Errorf("expected the rate limit warning through the set logger, got %q", out)
+	}
+}
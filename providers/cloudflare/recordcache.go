@@ -0,0 +1,81 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	cf "github.com/cloudflare/cloudflare-go"
+)
+
+// recordKey identifies a configured record in the record cache.
+type recordKey struct {
+	zone string // Configured zone_id, or zone_name if zone_id is empty
+	name string
+	typ  string
+}
+
+// cachedRecord is a DNS record as last seen in or written to its zone.
+type cachedRecord struct {
+	zoneID string
+	record cf.DNSRecord
+}
+
+// keyFor returns the cache key for a configured record.
+func keyFor(rec CloudflareRecord) recordKey {
+	zone := rec.ZoneID
+	if zone == "" {
+		zone = rec.ZoneName
+	}
+	return recordKey{zone: zone, name: rec.RecordName, typ: rec.RecordType}
+}
+
+// cachedRecordFor returns the cached record for rec, if any.
+func (c *CloudflareProvider) cachedRecordFor(rec CloudflareRecord) (cachedRecord, bool) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	cached, ok := c.records[keyFor(rec)]
+	return cached, ok
+}
+
+// rememberRecord caches the single record matching rec so later updates can skip listing the zone.
+func (c *CloudflareProvider) rememberRecord(rec CloudflareRecord, zoneID string, record cf.DNSRecord) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	if c.records == nil {
+		c.records = make(map[recordKey]cachedRecord)
+	}
+	c.records[keyFor(rec)] = cachedRecord{zoneID: zoneID, record: record}
+}
+
+// refreshRecord updates the cached copy of record after dynago has written it, if it is cached.
+func (c *CloudflareProvider) refreshRecord(rec CloudflareRecord, record cf.DNSRecord) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	key := keyFor(rec)
+	if cached, ok := c.records[key]; ok && cached.record.ID == record.ID {
+		cached.record = record
+		c.records[key] = cached
+	}
+}
+
+// forgetRecord drops rec from the cache, e.g. after it was deleted.
+func (c *CloudflareProvider) forgetRecord(rec CloudflareRecord) {
+	c.recordMu.Lock()
+	defer c.recordMu.Unlock()
+	delete(c.records, keyFor(rec))
+}
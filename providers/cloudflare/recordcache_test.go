@@ -0,0 +1,91 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package cloudflare
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	cf "github.com/cloudflare/cloudflare-go"
+)
+
+func TestCloudflareProvider_UpdateRecordIP_CachedRecordID(t *testing.T) {
+	var lists, updates int
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			lists++
+			w.Write([]byte(listResponse))
+			return
+		}
+		updates++
+		if r.URL.Path != "/zones/zone/dns_records/rec1" {
+			t.Errorf("unexpected update path: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"success":true,"result":{"id":"rec1"}}`))
+	})
+
+	if _, err := p.GetRecordIP(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lists != 1 || updates != 2 {
+		t.Errorf("expected 1 listing and 2 direct updates, got %d listings and %d updates", lists, updates)
+	}
+	if cached, ok := p.cachedRecordFor(p.Cfg.ManagedRecords()[0]); !ok || cached.record.Content != "5.6.7.8" {
+		t.Errorf("expected the cached record to reflect the update, got %+v", cached)
+	}
+}
+
+func TestCloudflareProvider_UpdateRecordIP_CachedRecordDeleted(t *testing.T) {
+	var lists int
+	var paths []string
+	p := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			lists++
+			w.Write([]byte(`{"success":true,"result":[{"id":"rec2","name":"home.example.com","type":"A","content":"1.2.3.4"}],"result_info":{"page":1,"per_page":100,"count":1,"total_count":1,"total_pages":1}}`))
+			return
+		}
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/zones/zone/dns_records/rec1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"errors":[{"code":81044,"message":"Record does not exist."}]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true,"result":{"id":"rec2"}}`))
+	})
+	rec := p.Cfg.ManagedRecords()[0]
+	p.rememberRecord(rec, "zone", cf.DNSRecord{ID: "rec1", Name: "home.example.com", Type: "A", Content: "1.2.3.4"})
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("expected the update to recover from a deleted cached record, got %v", err)
+	}
+	if lists != 1 || len(paths) != 2 || paths[1] != "/zones/zone/dns_records/rec2" {
+		t.Errorf("expected a fresh listing and an update of rec2, got %d listings and updates %v", lists, paths)
+	}
+	if cached, ok := p.cachedRecordFor(rec); !ok || cached.record.ID != "rec2" {
+		t.Errorf("expected rec2 to replace the stale cache entry, got %+v", cached)
+	}
+}
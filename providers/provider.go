@@ -27,28 +27,196 @@
 package provider
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/aaronlmathis/dynago/internal/breaker"
 	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/rs/zerolog"
 )
 
 // DNSProvider defines the interface for DNS providers.
 //
 // Implementations must provide methods to get and update the DNS record IP.
 type DNSProvider interface {
-	// GetRecordIP returns the current IP address configured in the DNS record.
-	GetRecordIP() (string, error)
+	// GetRecordIP returns the DNS record as currently configured, including its IP address.
+	GetRecordIP(ctx context.Context) (*DNSRecord, error)
 	// UpdateRecordIP updates the DNS record to the given IP address.
-	UpdateRecordIP(ip string) error
+	UpdateRecordIP(ctx context.Context, ip string) error
+	// GetRecordTTL returns the current TTL (in seconds) configured on the DNS record.
+	GetRecordTTL(ctx context.Context) (int64, error)
+	// DeleteRecord removes the DNS record with the given name and type, one of those returned by
+	// ListManagedRecords. A provider may manage several records (record_names, dual_stack), so
+	// the caller names the one to delete rather than removing them all at once.
+	DeleteRecord(ctx context.Context, name, recordType string) error
+	// ListManagedRecords returns the DNS records this provider is configured to manage.
+	ListManagedRecords(ctx context.Context) ([]DNSRecord, error)
+	// SelfTest verifies credentials and connectivity to the provider's API.
+	//
+	// Implementations must only perform read-only calls and must never modify DNS records.
+	SelfTest(ctx context.Context) error
+	// HealthCheck verifies that the provider's credentials are valid and its configured zone is
+	// reachable. With run_on_start set, it is called for every provider before the update loop starts.
+	HealthCheck(ctx context.Context) error
 	// ProviderName returns the name of the provider (e.g., "cloudflare", "route53").
 	ProviderName() string
+	// Close releases the provider's API client and connections. A closed provider may be used
+	// again and reinitializes its client on demand.
+	Close() error
+	// ProviderConfig returns the provider's relevant config fields for display.
+	//
+	// Secrets such as API tokens and keys must be replaced using Redact.
+	ProviderConfig() map[string]string
 }
 
+// StaticTarget is implemented by providers whose record can point at a fixed target rather than
+// the current public IP, such as Route53 ALIAS records.
+type StaticTarget interface {
+	// StaticTarget returns the value the record should hold and true, or false if the record
+	// follows the public IP.
+	StaticTarget() (string, bool)
+}
+
+// ExpiringCredentials is implemented by providers that may be configured with temporary
+// credentials, so the service can warn before they expire.
+type ExpiringCredentials interface {
+	// CredentialsExpiry returns when the configured credentials expire and true, or false if they
+	// do not expire or their expiry is unknown.
+	CredentialsExpiry() (time.Time, bool)
+}
+
+// AddressFamilies is implemented by providers that may manage AAAA records, so the service can
+// compare them against the public IPv6 address instead of the IPv4 one.
+type AddressFamilies interface {
+	// RecordTypes returns the distinct types of the records the provider manages, e.g. ["AAAA"]
+	// or ["A", "AAAA"].
+	RecordTypes() []string
+	// ForRecordType returns a provider that reads and updates only the records of recordType.
+	// It shares the receiver's client and state.
+	ForRecordType(recordType string) DNSProvider
+}
+
+// LoggerSetter is implemented by providers that can log through a logger supplied by the service,
+// such as one from logger.WithProvider that tags every entry with the provider's name.
+type LoggerSetter interface {
+	// SetLogger makes the provider log through l; nil restores the provider's default logger.
+	SetLogger(l *zerolog.Logger)
+}
+
+// RecordNamer is implemented by providers that can name the DNS records they manage, so that
+// events, audit rows, and metrics identify the records that changed.
+type RecordNamer interface {
+	// RecordNames returns the names of the managed records, in configuration order.
+	RecordNames() []string
+}
+
+// Redacted replaces secret values in ProviderConfig output.
+const Redacted = "***"
+
+// Redact returns Redacted for a non-empty secret and "" otherwise, so unset secrets stay visible.
+// Log lines that should identify the credential in use mask it with logger.Redact instead.
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// DNSRecord describes a DNS record as currently held by a provider.
+//
+// Fields a provider does not track are left at their zero value.
+type DNSRecord struct {
+	ID       string `json:"id,omitempty"`      // Provider-assigned record ID (Cloudflare only)
+	Name     string `json:"name"`              // Fully qualified record name
+	Type     string `json:"type"`              // Record type (e.g., "A", "AAAA")
+	Content  string `json:"content"`           // Record content (the IP address for A/AAAA records)
+	TTL      int64  `json:"ttl"`               // TTL in seconds
+	Proxied  *bool  `json:"proxied,omitempty"` // Whether Cloudflare proxies the record (nil for other providers)
+	Provider string `json:"provider"`          // Name of the provider holding the record
+	// LastUpdated is when the provider last modified the record (zero if the provider does not report it).
+	LastUpdated time.Time `json:"last_updated,omitzero"`
+}
+
+// Sentinel errors matched (via errors.Is) by ProviderErrors with the corresponding flag set.
+var (
+	ErrUnauthorized = errors.New("unauthorized") // Credentials were rejected
+	ErrRateLimited  = errors.New("rate limited") // The provider API rate limit was exceeded
+	// ErrRecordNotFound means the record does not exist yet, or its zone does not exist.
+	ErrRecordNotFound = errors.New("record not found")
+)
+
+// ProviderError describes a failed provider operation with machine-readable classification.
+//
+// Providers set the flags by inspecting HTTP status codes and SDK error types so callers can
+// decide whether to retry (Temporary), alert (Unauthorized), or back off (RateLimited) using errors.As.
+type ProviderError struct {
+	Provider     string // Name of the provider (e.g., "cloudflare")
+	Op           string // Operation that failed (e.g., "update record")
+	Err          error  // Underlying error
+	Temporary    bool   // The failure is transient and the operation may succeed if retried
+	Unauthorized bool   // Credentials were rejected or lack the required permissions
+	RateLimited  bool   // The provider API rate limit was exceeded
+	NotFound     bool   // The zone or record does not exist
+}
+
+// Error returns the provider, operation, and underlying error message.
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Provider, e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrUnauthorized, ErrRateLimited, or ErrRecordNotFound and the
+// matching flag is set.
+func (e *ProviderError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Unauthorized
+	case ErrRateLimited:
+		return e.RateLimited
+	case ErrRecordNotFound:
+		return e.NotFound
+	}
+	return false
+}
+
+// MultiError collects the failures of an operation applied to several records.
+//
+// Total is the number of records attempted, so callers can tell how many succeeded.
+// Each entry in Errors identifies the record it belongs to in its message.
+type MultiError struct {
+	Errors []error // One error per failed record
+	Total  int     // Number of records attempted
+}
+
+// Error summarizes the failures, e.g. "2 of 3 records failed: ...".
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	total := e.Total
+	if total < len(e.Errors) {
+		total = len(e.Errors)
+	}
+	return fmt.Sprintf("%d of %d records failed: %s", len(e.Errors), total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual errors so errors.Is and errors.As inspect each of them.
+func (e *MultiError) Unwrap() []error { return e.Errors }
+
 // DNSProviderRegistry holds all enabled DNS providers.
 //
 // Providers is a slice of DNSProvider implementations that are enabled in the config.
+// Breakers holds one circuit breaker per provider, keyed by ProviderName.
 type DNSProviderRegistry struct {
 	Providers []DNSProvider
+	Breakers  map[string]*breaker.CircuitBreaker
 }
 
 // NewDNSProviderRegistry creates a registry of enabled DNS providers based on config.
@@ -56,10 +224,85 @@ type DNSProviderRegistry struct {
 // cfg: The loaded application configuration.
 // providers: One or more DNSProvider implementations to register.
 //
+// Each provider gets its own circuit breaker configured from circuit_breaker_threshold and
+// circuit_breaker_timeout; cfg may be nil to use the breaker defaults.
+//
 // Returns a DNSProviderRegistry with all enabled providers, or an error if none are enabled.
 func NewDNSProviderRegistry(cfg *config.Config, providers ...DNSProvider) (*DNSProviderRegistry, error) {
 	if len(providers) == 0 {
 		return nil, errors.New("no DNS providers enabled in config")
 	}
-	return &DNSProviderRegistry{Providers: providers}, nil
+	breakers := make(map[string]*breaker.CircuitBreaker, len(providers))
+	for _, p := range providers {
+		b := breaker.New(p.ProviderName())
+		if cfg != nil {
+			if cfg.CircuitBreakerThreshold > 0 {
+				b.FailureThreshold = cfg.CircuitBreakerThreshold
+			}
+			if cfg.CircuitBreakerTimeout > 0 {
+				b.RecoveryTimeout = cfg.CircuitBreakerTimeout
+			}
+		}
+		breakers[p.ProviderName()] = b
+	}
+	return &DNSProviderRegistry{Providers: providers, Breakers: breakers}, nil
+}
+
+// Breaker returns the circuit breaker for the given provider, creating one if it is missing.
+func (r *DNSProviderRegistry) Breaker(p DNSProvider) *breaker.CircuitBreaker {
+	name := p.ProviderName()
+	if r.Breakers == nil {
+		r.Breakers = make(map[string]*breaker.CircuitBreaker)
+	}
+	b, ok := r.Breakers[name]
+	if !ok {
+		b = breaker.New(name)
+		r.Breakers[name] = b
+	}
+	return b
+}
+
+// GetProvider returns the first provider whose ProviderName matches name, ignoring case.
+func (r *DNSProviderRegistry) GetProvider(name string) (DNSProvider, bool) {
+	for _, p := range r.Providers {
+		if strings.EqualFold(p.ProviderName(), name) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// UpdateFunc updates a single provider's record to ip. See DNSProviderRegistry.UpdateAllFunc.
+type UpdateFunc func(ctx context.Context, p DNSProvider, ip string) error
+
+// UpdateAll sets every provider's record to ip concurrently.
+//
+// Returns the result of each UpdateRecordIP call keyed by ProviderName; successful providers map to nil.
+func (r *DNSProviderRegistry) UpdateAll(ctx context.Context, ip string) map[string]error {
+	return r.UpdateAllFunc(ctx, ip, func(ctx context.Context, p DNSProvider, ip string) error {
+		return p.UpdateRecordIP(ctx, ip)
+	})
+}
+
+// UpdateAllFunc is like UpdateAll but calls update for each provider instead of UpdateRecordIP,
+// so callers can read, debounce, or guard the update. update runs in its own goroutine for every
+// provider, and a failing provider does not cancel the others.
+func (r *DNSProviderRegistry) UpdateAllFunc(ctx context.Context, ip string, update UpdateFunc) map[string]error {
+	results := make(map[string]error, len(r.Providers))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, p := range r.Providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := update(ctx, p, ip)
+			mu.Lock()
+			results[p.ProviderName()] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
 }
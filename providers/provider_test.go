@@ -20,7 +20,14 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/aaronlmathis/dynago/internal/config"
 )
 
 type mockProvider struct {
@@ -29,9 +36,30 @@ type mockProvider struct {
 	updateErr error
 }
 
-func (m *mockProvider) GetRecordIP() (string, error)   { return m.ip, nil }
-func (m *mockProvider) UpdateRecordIP(ip string) error { m.ip = ip; return m.updateErr }
-func (m *mockProvider) ProviderName() string           { return m.name }
+func (m *mockProvider) GetRecordIP(ctx context.Context) (*DNSRecord, error) {
+	return &DNSRecord{Content: m.ip, Provider: m.name}, nil
+}
+func (m *mockProvider) UpdateRecordIP(ctx context.Context, ip string) error {
+	m.ip = ip
+	return m.updateErr
+}
+func (m *mockProvider) ProviderName() string { return m.name }
+func (m *mockProvider) GetRecordTTL(ctx context.Context) (int64, error) {
+	return 300, nil
+}
+func (m *mockProvider) DeleteRecord(ctx context.Context, name, recordType string) error {
+	return nil
+}
+func (m *mockProvider) ListManagedRecords(ctx context.Context) ([]DNSRecord, error) {
+	return nil, nil
+}
+func (m *mockProvider) SelfTest(ctx context.Context) error { return nil }
+
+func (m *mockProvider) HealthCheck(ctx context.Context) error { return nil }
+
+func (m *mockProvider) ProviderConfig() map[string]string { return nil }
+
+func (m *mockProvider) Close() error { return nil }
 
 func TestDNSProviderRegistry_AddsProviders(t *testing.T) {
 	p1 := &mockProvider{name: "mock1", ip: "1.2.3.4"}
@@ -48,9 +76,156 @@ func TestDNSProviderRegistry_AddsProviders(t *testing.T) {
 	}
 }
 
+func TestDNSProviderRegistry_BreakersFromConfig(t *testing.T) {
+	cfg := &config.Config{CircuitBreakerThreshold: 2, CircuitBreakerTimeout: time.Minute}
+	p := &mockProvider{name: "mock"}
+	reg, err := NewDNSProviderRegistry(cfg, p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := reg.Breaker(p)
+	if b.FailureThreshold != 2 || b.RecoveryTimeout != time.Minute {
+		t.Errorf("breaker not configured from config: threshold %d, timeout %s", b.FailureThreshold, b.RecoveryTimeout)
+	}
+}
+
 func TestDNSProviderRegistry_Empty(t *testing.T) {
 	_, err := NewDNSProviderRegistry(nil)
 	if err == nil {
 		t.Errorf("expected error when no providers enabled")
 	}
 }
+
+func TestDNSProviderRegistry_GetProvider(t *testing.T) {
+	cf := &mockProvider{name: "cloudflare"}
+	r53 := &mockProvider{name: "route53"}
+	reg, err := NewDNSProviderRegistry(nil, cf, r53)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name string
+		want DNSProvider
+	}{
+		{"route53", r53},
+		{"CloudFlare", cf},
+		{"gandi", nil},
+	}
+	for _, tt := range tests {
+		got, ok := reg.GetProvider(tt.name)
+		if ok != (tt.want != nil) || (tt.want != nil && got != tt.want) {
+			t.Errorf("GetProvider(%q) = %v, %v; want %v", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestDNSProviderRegistry_UpdateAll(t *testing.T) {
+	ok := &mockProvider{name: "ok", ip: "1.2.3.4"}
+	failing := &mockProvider{name: "failing", ip: "1.2.3.4", updateErr: errors.New("boom")}
+	reg, err := NewDNSProviderRegistry(nil, ok, failing)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := reg.UpdateAll(context.Background(), "5.6.7.8")
+	if len(results) != 2 {
+		t.Fatalf("expected a result for each provider, got %v", results)
+	}
+	if results["ok"] != nil {
+		t.Errorf("expected ok to succeed, got %v", results["ok"])
+	}
+	if results["failing"] == nil || results["failing"].Error() != "boom" {
+		t.Errorf("expected failing to report its error, got %v", results["failing"])
+	}
+	if ok.ip != "5.6.7.8" || failing.ip != "5.6.7.8" {
+		t.Errorf("expected every provider to be updated, got %s and %s", ok.ip, failing.ip)
+	}
+}
+
+func TestDNSProviderRegistry_UpdateAllFunc(t *testing.T) {
+	a := &mockProvider{name: "a", ip: "1.2.3.4"}
+	b := &mockProvider{name: "b", ip: "1.2.3.4"}
+	reg, err := NewDNSProviderRegistry(nil, a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := reg.UpdateAllFunc(context.Background(), "5.6.7.8", func(ctx context.Context, p DNSProvider, ip string) error {
+		if p.ProviderName() == "b" {
+			return errors.New("skipped")
+		}
+		return p.UpdateRecordIP(ctx, ip)
+	})
+	if results["a"] != nil || results["b"] == nil {
+		t.Errorf("expected a to succeed and b to fail, got %v", results)
+	}
+	if a.ip != "5.6.7.8" || b.ip != "1.2.3.4" {
+		t.Errorf("expected only a to be updated, got %s and %s", a.ip, b.ip)
+	}
+}
+
+func TestDNSRecord_JSON(t *testing.T) {
+	data, err := json.Marshal(DNSRecord{Name: "home.example.com", Type: "A", Content: "1.2.3.4", TTL: 300, Provider: "mock"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got["content"] != "1.2.3.4" {
+		t.Errorf("expected the record content under \"content\", got %s", data)
+	}
+	for _, key := range []string{"ip", "value", "id", "proxied", "last_updated"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("unexpected key %q in %s", key, data)
+		}
+	}
+}
+
+func TestMultiError(t *testing.T) {
+	notFound := &ProviderError{Provider: "mock", Op: "update record", Err: errors.New("b.example.com: missing"), NotFound: true}
+	multi := &MultiError{Errors: []error{errors.New("a.example.com: boom"), notFound}, Total: 3}
+
+	want := "2 of 3 records failed: a.example.com: boom; mock update record: b.example.com: missing"
+	if got := multi.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	var pe *ProviderError
+	if !errors.As(error(multi), &pe) || !pe.NotFound {
+		t.Errorf("expected errors.As to find the wrapped ProviderError")
+	}
+	if !errors.Is(error(multi), notFound) {
+		t.Errorf("expected errors.Is to match a sub-error")
+	}
+}
+
+func TestProviderError_IsUnauthorized(t *testing.T) {
+	denied := &ProviderError{Provider: "mock", Op: "update record", Err: errors.New("403"), Unauthorized: true}
+	if !errors.Is(fmt.Errorf("wrapped: %w", denied), ErrUnauthorized) {
+		t.Errorf("expected an Unauthorized ProviderError to match ErrUnauthorized")
+	}
+	other := &ProviderError{Provider: "mock", Op: "update record", Err: errors.New("500"), Temporary: true}
+	if errors.Is(other, ErrUnauthorized) {
+		t.Errorf("expected a non-Unauthorized ProviderError not to match ErrUnauthorized")
+	}
+}
+
+func TestProviderError_IsRecordNotFound(t *testing.T) {
+	missing := &ProviderError{Provider: "mock", Op: "get record", Err: errors.New("no such record"), NotFound: true}
+	if !errors.Is(fmt.Errorf("wrapped: %w", missing), ErrRecordNotFound) {
+		t.Errorf("expected a NotFound ProviderError to match ErrRecordNotFound")
+	}
+	if errors.Is(&ProviderError{Provider: "mock", Op: "get record", Err: errors.New("500")}, ErrRecordNotFound) {
+		t.Errorf("expected a ProviderError without NotFound not to match ErrRecordNotFound")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := Redact("secret"); got != Redacted {
+		t.Errorf("Redact(secret) = %q, want %q", got, Redacted)
+	}
+	if got := Redact(""); got != "" {
+		t.Errorf("Redact(\"\") = %q, want empty", got)
+	}
+}
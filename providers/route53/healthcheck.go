@@ -0,0 +1,160 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package route53
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// Health check protocols supported in health_check_protocol.
+const (
+	HealthCheckHTTP  = "HTTP"
+	HealthCheckHTTPS = "HTTPS"
+)
+
+// healthCheckProtocol returns the configured health check protocol, defaulting to HTTP.
+func (cfg *Route53Config) healthCheckProtocol() string {
+	if cfg.HealthCheckProtocol == "" {
+		return HealthCheckHTTP
+	}
+	return cfg.HealthCheckProtocol
+}
+
+// healthCheckConfig returns the health check that probes name with the configured protocol and path.
+func (cfg *Route53Config) healthCheckConfig(name string) *r53types.HealthCheckConfig {
+	port := int32(80)
+	if cfg.healthCheckProtocol() == HealthCheckHTTPS {
+		port = 443
+	}
+	hc := &r53types.HealthCheckConfig{
+		Type:                     r53types.HealthCheckType(cfg.healthCheckProtocol()),
+		FullyQualifiedDomainName: aws.String(name),
+		Port:                     aws.Int32(port),
+	}
+	if cfg.HealthCheckPath != "" {
+		hc.ResourcePath = aws.String(cfg.HealthCheckPath)
+	}
+	return hc
+}
+
+// healthCheckReference returns the CallerReference used to create the health check for name.
+//
+// It is derived from the zone, record name, protocol, and path, so creating the same health check
+// again returns the existing one instead of a duplicate, while a changed protocol or path creates a
+// new one.
+func (cfg *Route53Config) healthCheckReference(zoneID, name string) string {
+	sum := sha256.Sum256([]byte(zoneID + "|" + name + "|" + cfg.healthCheckProtocol() + "|" + cfg.HealthCheckPath))
+	return "dynago-" + hex.EncodeToString(sum[:16])
+}
+
+// healthCheckID returns the ID of the health check for name, creating it on first use.
+//
+// IDs are cached for the lifetime of the provider and, if health_check_state_file is set, saved
+// there so a restart reuses them without calling CreateHealthCheck. Without the file, Route53
+// treats a repeated CallerReference as the same request, so the check created by an earlier run is
+// still found again after a restart.
+func (r *Route53Provider) healthCheckID(ctx context.Context, client Route53API, zoneID, name string) (string, error) {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	r.loadHealthChecks()
+	ref := r.Cfg.healthCheckReference(zoneID, name)
+	if id, ok := r.healthChecks[ref]; ok {
+		return id, nil
+	}
+	resp, err := client.CreateHealthCheck(ctx, &route53.CreateHealthCheckInput{
+		CallerReference:   aws.String(ref),
+		HealthCheckConfig: r.Cfg.healthCheckConfig(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create health check for %s: %w", name, err)
+	}
+	id := aws.ToString(resp.HealthCheck.Id)
+	r.log().Info().Msgf("route53: using health check arn:aws:route53:::healthcheck/%s for %s", id, name)
+	r.healthChecks[ref] = id
+	if err := r.saveHealthChecks(); err != nil {
+		r.log().Warn().Msgf("route53: could not save health check IDs: %v", err)
+	}
+	return id, nil
+}
+
+// loadHealthChecks reads the health check IDs saved in health_check_state_file, once. A missing
+// file is not an error; an unreadable one is logged, and its checks are found again through their
+// CallerReference. The caller must hold healthMu.
+func (r *Route53Provider) loadHealthChecks() {
+	if r.healthChecks != nil {
+		return
+	}
+	r.healthChecks = make(map[string]string)
+	if r.Cfg.HealthCheckStateFile == "" {
+		return
+	}
+	data, err := os.ReadFile(r.Cfg.HealthCheckStateFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	var saved map[string]string
+	if err == nil {
+		err = json.Unmarshal(data, &saved)
+	}
+	if err != nil {
+		r.log().Warn().Msgf("route53: could not load health check IDs from %s: %v", r.Cfg.HealthCheckStateFile, err)
+		return
+	}
+	for ref, id := range saved {
+		r.healthChecks[ref] = id
+	}
+}
+
+// saveHealthChecks writes the cached health check IDs, keyed by CallerReference, to
+// health_check_state_file, if set. The file is replaced atomically. The caller must hold healthMu.
+func (r *Route53Provider) saveHealthChecks() error {
+	path := r.Cfg.HealthCheckStateFile
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(r.healthChecks, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,165 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package route53
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// newHealthCheckProvider returns a weighted provider that creates health checks.
+func newHealthCheckProvider(client *mockRoute53Client) *Route53Provider {
+	p := newTestProvider(client)
+	p.Cfg.RoutingPolicy = RoutingPolicyWeighted
+	p.Cfg.SetIdentifier = "home"
+	p.Cfg.Weight = aws.Int64(10)
+	p.Cfg.CreateHealthCheck = true
+	p.Cfg.HealthCheckProtocol = HealthCheckHTTPS
+	p.Cfg.HealthCheckPath = "/health"
+	return p
+}
+
+func TestRoute53Provider_HealthCheck_CreatedOnce(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newHealthCheckProvider(client)
+
+	for _, ip := range []string{"1.2.3.4", "5.6.7.8"} {
+		if err := p.UpdateRecordIP(context.Background(), ip); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(client.healthChecks) != 1 {
+		t.Fatalf("expected 1 CreateHealthCheck call, got %d", len(client.healthChecks))
+	}
+	hc := client.healthChecks[0].HealthCheckConfig
+	if hc.Type != r53types.HealthCheckTypeHttps || aws.ToInt32(hc.Port) != 443 ||
+		aws.ToString(hc.FullyQualifiedDomainName) != "home.example.com" || aws.ToString(hc.ResourcePath) != "/health" {
+		t.Errorf("unexpected health check config: %+v", hc)
+	}
+	want := "hc-" + aws.ToString(client.healthChecks[0].CallerReference)
+	for i, change := range client.changes {
+		if got := aws.ToString(change.ChangeBatch.Changes[0].ResourceRecordSet.HealthCheckId); got != want {
+			t.Errorf("update %d: expected health check ID %s, got %q", i, want, got)
+		}
+	}
+}
+
+func TestRoute53Provider_HealthCheck_StateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "health-checks.json")
+	client := &mockRoute53Client{}
+	p := newHealthCheckProvider(client)
+	p.Cfg.HealthCheckStateFile = stateFile
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "hc-" + aws.ToString(client.healthChecks[0].CallerReference)
+	data, err := os.ReadFile(stateFile)
+	if err != nil || !strings.Contains(string(data), want) {
+		t.Fatalf("expected %s to be saved to the state file, got %q, %v", want, data, err)
+	}
+
+	// A restarted provider reuses the saved ID without creating the health check again.
+	restarted := &mockRoute53Client{}
+	p = newHealthCheckProvider(restarted)
+	p.Cfg.HealthCheckStateFile = stateFile
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(restarted.healthChecks) != 0 {
+		t.Errorf("expected no CreateHealthCheck call after a restart, got %d", len(restarted.healthChecks))
+	}
+	if got := aws.ToString(restarted.changes[0].ChangeBatch.Changes[0].ResourceRecordSet.HealthCheckId); got != want {
+		t.Errorf("expected the saved health check ID %s, got %q", want, got)
+	}
+}
+
+func TestRoute53Provider_HealthCheck_StateFileUnreadable(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "health-checks.json")
+	if err := os.WriteFile(stateFile, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client := &mockRoute53Client{}
+	p := newHealthCheckProvider(client)
+	p.Cfg.HealthCheckStateFile = stateFile
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+		t.Fatalf("expected an unreadable state file not to block the update, got %v", err)
+	}
+	if len(client.healthChecks) != 1 {
+		t.Errorf("expected the health check to be created, got %d calls", len(client.healthChecks))
+	}
+	if data, _ := os.ReadFile(stateFile); !strings.Contains(string(data), "hc-") {
+		t.Errorf("expected the state file to be rewritten, got %q", data)
+	}
+}
+
+func TestRoute53Provider_HealthCheck_StableReference(t *testing.T) {
+	cfg := newHealthCheckProvider(&mockRoute53Client{}).Cfg
+	ref := cfg.healthCheckReference("zone", "home.example.com")
+	if ref != cfg.healthCheckReference("zone", "home.example.com") {
+		t.Errorf("expected the caller reference to be stable across calls")
+	}
+	if ref == cfg.healthCheckReference("zone", "vpn.example.com") || ref == cfg.healthCheckReference("other", "home.example.com") {
+		t.Errorf("expected different records to get different caller references")
+	}
+	if len(ref) > 64 {
+		t.Errorf("caller reference %q exceeds Route53's 64 character limit", ref)
+	}
+	cfg.HealthCheckProtocol = HealthCheckHTTP
+	if ref == cfg.healthCheckReference("zone", "home.example.com") {
+		t.Errorf("expected a changed protocol to get a new caller reference")
+	}
+}
+
+func TestRoute53Provider_HealthCheck_CreateFails(t *testing.T) {
+	client := &mockRoute53Client{healthErr: errors.New("too many health checks")}
+	p := newHealthCheckProvider(client)
+
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err == nil {
+		t.Fatal("expected error when the health check cannot be created")
+	}
+	if len(client.changes) != 0 {
+		t.Errorf("expected no record change without a health check, got %d", len(client.changes))
+	}
+}
+
+func TestRoute53Provider_HealthCheck_CreateFailsForOneRecord(t *testing.T) {
+	client := &mockRoute53Client{healthErr: errors.New("too many health checks"), healthErrFor: "b.example.com"}
+	p := newHealthCheckProvider(client)
+	p.Cfg.RecordName, p.Cfg.RecordNames = "", []string{"a.example.com", "b.example.com"}
+
+	err := p.UpdateRecordIP(context.Background(), "1.2.3.4")
+	var multi *providers.MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 1 || multi.Total != 2 ||
+		!strings.Contains(multi.Errors[0].Error(), "b.example.com") {
+		t.Fatalf("expected a MultiError for b.example.com only, got %v", err)
+	}
+	if len(client.changes) != 1 || len(client.changes[0].ChangeBatch.Changes) != 1 ||
+		aws.ToString(client.changes[0].ChangeBatch.Changes[0].ResourceRecordSet.Name) != "a.example.com" {
+		t.Errorf("expected a.example.com to be updated on its own, got %+v", client.changes)
+	}
+}
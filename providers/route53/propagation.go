@@ -0,0 +1,117 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package route53
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// DefaultPropagationTimeout is how long UpdateRecordIP waits for a change to reach INSYNC when
+// neither propagation_timeout nor insync_timeout is set.
+const DefaultPropagationTimeout = 60 * time.Second
+
+// propagationPollInterval is the delay between GetChange calls while waiting for propagation.
+const propagationPollInterval = 5 * time.Second
+
+// ErrChangePollTimeout is matched (via errors.Is) by a *PropagationTimeoutError.
+var ErrChangePollTimeout = errors.New("route53: change did not reach INSYNC in time")
+
+// PropagationTimeoutError is returned when a change has not reached INSYNC within the propagation timeout.
+//
+// The change itself was accepted by Route53 and will still be applied.
+type PropagationTimeoutError struct {
+	ChangeID string        // ID of the pending change
+	Timeout  time.Duration // How long dynago waited
+}
+
+func (e *PropagationTimeoutError) Error() string {
+	return fmt.Sprintf("change %s not in sync after %s", e.ChangeID, e.Timeout)
+}
+
+// Unwrap returns ErrChangePollTimeout.
+func (e *PropagationTimeoutError) Unwrap() error { return ErrChangePollTimeout }
+
+// waitForInSync reports whether UpdateRecordIP waits for changes to reach INSYNC, set with either
+// wait_for_propagation or its alias wait_for_insync.
+func (cfg *Route53Config) waitForInSync() bool {
+	return cfg.WaitForPropagation || cfg.WaitForInSync
+}
+
+// propagationTimeout returns the configured propagation_timeout, or its alias insync_timeout, or
+// the default.
+func (r *Route53Provider) propagationTimeout() time.Duration {
+	switch {
+	case r.Cfg.PropagationTimeout > 0:
+		return r.Cfg.PropagationTimeout
+	case r.Cfg.InSyncTimeout > 0:
+		return r.Cfg.InSyncTimeout
+	}
+	return DefaultPropagationTimeout
+}
+
+// waitForChange polls GetChange until the change reaches INSYNC.
+//
+// The propagation timeout is shortened to end just before ctx's deadline, if any, so a provider_timeout
+// shorter than propagation_timeout still ends the wait with a *PropagationTimeoutError.
+// Returns a *PropagationTimeoutError if the propagation timeout passes first, or ctx's error if
+// ctx is cancelled.
+func (r *Route53Provider) waitForChange(ctx context.Context, client Route53API, info *r53types.ChangeInfo) error {
+	if info == nil || info.Id == nil || info.Status == r53types.ChangeStatusInsync {
+		return nil
+	}
+	changeID := *info.Id
+	timeout := r.propagationTimeout()
+	if dl, ok := ctx.Deadline(); ok {
+		remaining := time.Until(dl)
+		timeout = min(timeout, remaining-min(time.Second, remaining/10))
+	}
+	interval := r.pollInterval
+	if interval <= 0 {
+		interval = propagationPollInterval
+	}
+	start := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			return &PropagationTimeoutError{ChangeID: changeID, Timeout: timeout}
+		case <-ticker.C:
+		}
+		resp, err := client.GetChange(ctx, &route53.GetChangeInput{Id: aws.String(changeID)})
+		if err != nil {
+			return err
+		}
+		if resp.ChangeInfo != nil && resp.ChangeInfo.Status == r53types.ChangeStatusInsync {
+			r.log().Debug().Msgf("route53: change %s is in sync after %s", changeID, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+	}
+}
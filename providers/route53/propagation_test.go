@@ -0,0 +1,162 @@
+// dynago - Dynamic DNS updater for Cloudflare, Route 53, and more.
+// Copyright (C) 2025  Aaron Mathis <aaron.mathis@gmail.com>
+//
+// This file is part of dynago.
+//
+// dynago is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// dynago is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with dynago.  If not, see <https://www.gnu.org/licenses/>.
+
+package route53
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	providers "github.com/aaronlmathis/dynago/providers"
+)
+
+// newPropagationProvider returns a provider that waits for propagation, polling every millisecond.
+func newPropagationProvider(client *mockRoute53Client, timeout time.Duration) *Route53Provider {
+	p := newTestProvider(client)
+	p.Cfg.WaitForPropagation = true
+	p.Cfg.PropagationTimeout = timeout
+	p.pollInterval = time.Millisecond
+	return p
+}
+
+func TestRoute53Provider_UpdateRecordIP_NoWait(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newTestProvider(client)
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.getChanges != 0 {
+		t.Errorf("expected no GetChange calls without wait_for_propagation, got %d", client.getChanges)
+	}
+}
+
+func TestRoute53Provider_UpdateRecordIP_WaitsForInsync(t *testing.T) {
+	client := &mockRoute53Client{pendingPolls: 2}
+	p := newPropagationProvider(client, time.Second)
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.getChanges != 3 {
+		t.Errorf("expected 3 GetChange calls, got %d", client.getChanges)
+	}
+}
+
+func TestRoute53Provider_UpdateRecordIP_PropagationTimeout(t *testing.T) {
+	client := &mockRoute53Client{pendingPolls: -1}
+	p := newPropagationProvider(client, 20*time.Millisecond)
+	err := p.UpdateRecordIP(context.Background(), "1.2.3.4")
+	var te *PropagationTimeoutError
+	if !errors.As(err, &te) {
+		t.Fatalf("expected *PropagationTimeoutError, got %T: %v", err, err)
+	}
+	if !errors.Is(err, ErrChangePollTimeout) {
+		t.Errorf("expected the error to match ErrChangePollTimeout, got %v", err)
+	}
+	if te.ChangeID != "/change/C1" || te.Timeout != 20*time.Millisecond {
+		t.Errorf("unexpected timeout error: %+v", te)
+	}
+	if client.getChanges == 0 {
+		t.Errorf("expected GetChange to be polled before timing out")
+	}
+}
+
+func TestRoute53Provider_UpdateRecordIP_GetChangeError(t *testing.T) {
+	client := &mockRoute53Client{getChangeErr: errors.New("boom")}
+	p := newPropagationProvider(client, time.Second)
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err == nil {
+		t.Errorf("expected GetChange error to be returned")
+	}
+}
+
+// TestRoute53Provider_UpdateRecordIP_ContextDeadline checks that the wait ends before a context
+// deadline shorter than the propagation timeout, with an error that is not retried.
+func TestRoute53Provider_UpdateRecordIP_ContextDeadline(t *testing.T) {
+	client := &mockRoute53Client{pendingPolls: -1}
+	p := newPropagationProvider(client, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := p.UpdateRecordIP(ctx, "1.2.3.4")
+	var te *PropagationTimeoutError
+	if !errors.As(err, &te) || te.Timeout >= 50*time.Millisecond {
+		t.Fatalf("expected *PropagationTimeoutError before the deadline, got %T: %v", err, err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected the wait to end before the context deadline")
+	}
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || pe.Temporary {
+		t.Errorf("expected a non-temporary provider error, got %#v", err)
+	}
+}
+
+func TestRoute53Provider_UpdateRecordIP_ContextCancelled(t *testing.T) {
+	client := &mockRoute53Client{pendingPolls: -1}
+	p := newPropagationProvider(client, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := p.UpdateRecordIP(ctx, "1.2.3.4")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation error, got %v", err)
+	}
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || pe.Temporary {
+		t.Errorf("expected a non-temporary provider error, got %#v", err)
+	}
+}
+
+func TestRoute53Provider_PropagationTimeoutDefault(t *testing.T) {
+	p := newTestProvider(&mockRoute53Client{})
+	if got := p.propagationTimeout(); got != DefaultPropagationTimeout {
+		t.Errorf("expected default %s, got %s", DefaultPropagationTimeout, got)
+	}
+	if DefaultPropagationTimeout != 60*time.Second {
+		t.Errorf("expected a 60s default, got %s", DefaultPropagationTimeout)
+	}
+	p.Cfg.InSyncTimeout = 2 * time.Minute
+	if got := p.propagationTimeout(); got != 2*time.Minute {
+		t.Errorf("expected insync_timeout, got %s", got)
+	}
+	p.Cfg.PropagationTimeout = time.Minute
+	if got := p.propagationTimeout(); got != time.Minute {
+		t.Errorf("expected configured timeout, got %s", got)
+	}
+}
+
+// TestRoute53Provider_UpdateRecordIP_WaitForInSyncAlias checks that wait_for_insync and
+// insync_timeout, read from the config map, turn on the wait like wait_for_propagation.
+func TestRoute53Provider_UpdateRecordIP_WaitForInSyncAlias(t *testing.T) {
+	p, err := New(map[string]any{"enabled": false, "wait_for_insync": true, "insync_timeout": "20ms"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !p.Cfg.WaitForInSync || p.Cfg.InSyncTimeout != 20*time.Millisecond {
+		t.Fatalf("expected the aliases to be read, got %+v", p.Cfg)
+	}
+	client := &mockRoute53Client{pendingPolls: -1}
+	test := newTestProvider(client)
+	test.Cfg.WaitForInSync, test.Cfg.InSyncTimeout = true, p.Cfg.InSyncTimeout
+	test.pollInterval = time.Millisecond
+	if err := test.UpdateRecordIP(context.Background(), "1.2.3.4"); !errors.Is(err, ErrChangePollTimeout) {
+		t.Errorf("expected ErrChangePollTimeout, got %v", err)
+	}
+	if client.getChanges == 0 {
+		t.Errorf("expected GetChange to be polled with wait_for_insync")
+	}
+}
@@ -25,37 +25,279 @@ package route53
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aaronlmathis/dynago/internal/config"
+	"github.com/aaronlmathis/dynago/internal/logger"
+	providers "github.com/aaronlmathis/dynago/providers"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/rs/zerolog"
+)
+
+// Routing policies supported in routing_policy.
+const (
+	RoutingPolicyWeighted    = "weighted"
+	RoutingPolicyGeolocation = "geolocation"
+)
+
+// DefaultRoleSessionName is the STS session name used when role_session_name is not set.
+const DefaultRoleSessionName = "dynago"
+
+// DefaultTTL is the TTL in seconds UpdateRecordIP sets when ttl is not configured.
+const DefaultTTL = 300
+
+// Errors returned when the configured record or zone_name does not exist.
+var (
+	errRecordNotFound = errors.New("record not found")
+	errZoneNotFound   = errors.New("hosted zone not found")
 )
 
 // Route53Config holds AWS Route53-specific configuration.
 type Route53Config struct {
-	Enabled         bool   `yaml:"enabled"`
-	AccessKeyID     string `yaml:"access_key_id"`
-	SecretAccessKey string `yaml:"secret_access_key"`
-	HostedZoneID    string `yaml:"hosted_zone_id"`
-	RecordName      string `yaml:"record_name"`
-	RecordType      string `yaml:"record_type"`
-	Region          string `yaml:"region"`
+	Enabled         bool     `yaml:"enabled"`
+	AccessKeyID     string   `yaml:"access_key_id"`
+	SecretAccessKey string   `yaml:"secret_access_key"`
+	SessionToken    string   `yaml:"session_token"` // Set with temporary access keys, e.g. from sts get-session-token
+	HostedZoneID    string   `yaml:"hosted_zone_id"`
+	ZoneName        string   `yaml:"zone_name"`    // Looked up to find the hosted zone ID when hosted_zone_id is empty
+	ZonePrivate     bool     `yaml:"zone_private"` // Look up the private zone named zone_name instead of the public one
+	RecordName      string   `yaml:"record_name"`
+	RecordNames     []string `yaml:"record_names"` // Several records in the hosted zone, updated in one change batch
+	RecordType      string   `yaml:"record_type"`
+	Region          string   `yaml:"region"`
+	TTL             int64    `yaml:"ttl"`               // TTL in seconds set by UpdateRecordIP (default 300)
+	AssumeRoleARN   string   `yaml:"assume_role_arn"`   // Role to assume via STS; static credentials are used directly when empty
+	ExternalID      string   `yaml:"external_id"`       // Optional external ID passed when assuming the role
+	RoleSessionName string   `yaml:"role_session_name"` // Session name used when assuming the role (default "dynago")
+	// WaitForPropagation makes UpdateRecordIP poll the change until Route53 reports it INSYNC,
+	// giving up after PropagationTimeout (default 60s).
+	WaitForPropagation bool          `yaml:"wait_for_propagation"`
+	PropagationTimeout time.Duration `yaml:"propagation_timeout"`
+	// WaitForInSync and InSyncTimeout are aliases for WaitForPropagation and PropagationTimeout.
+	WaitForInSync bool          `yaml:"wait_for_insync"`
+	InSyncTimeout time.Duration `yaml:"insync_timeout"`
+	// CredentialsExpiry is when the temporary credentials given by SessionToken expire. The service
+	// warns at startup if they expire before the next update.
+	CredentialsExpiry time.Time `yaml:"credentials_expiry"`
+	// UseInstanceProfile takes credentials from the EC2 instance profile via IMDSv2 instead of
+	// access_key_id and secret_access_key, e.g. when running in the VPC of a private zone.
+	UseInstanceProfile bool `yaml:"use_instance_profile"`
+	// AliasTarget makes the records ALIAS records pointing at this AWS resource (e.g. an ELB or
+	// CloudFront DNS name in the hosted zone AliasHostedZoneID) instead of the public IP.
+	AliasTarget          string `yaml:"alias_target"`
+	AliasHostedZoneID    string `yaml:"alias_hosted_zone_id"`
+	EvaluateTargetHealth bool   `yaml:"evaluate_target_health"`
+	// RoutingPolicy "weighted" manages only the record set with SetIdentifier among records sharing
+	// the name, giving it Weight. "geolocation" manages only the record set for GeoContinentCode or
+	// GeoCountryCode, created with SetIdentifier. Empty manages a simple record.
+	RoutingPolicy    string `yaml:"routing_policy"`
+	Weight           *int64 `yaml:"weight"`             // Required with weighted routing; 0 sends no traffic
+	SetIdentifier    string `yaml:"set_identifier"`     // Required with either routing policy
+	GeoContinentCode string `yaml:"geo_continent_code"` // e.g. "EU"
+	GeoCountryCode   string `yaml:"geo_country_code"`   // e.g. "DE", or "*" for the default location
+	// CreateHealthCheck creates a Route53 health check probing each record over
+	// HealthCheckProtocol ("HTTP" or "HTTPS", default "HTTP") at HealthCheckPath, and associates
+	// it with the record. Requires a routing_policy.
+	CreateHealthCheck   bool   `yaml:"create_health_check"`
+	HealthCheckPath     string `yaml:"health_check_path"`
+	HealthCheckProtocol string `yaml:"health_check_protocol"`
+	// HealthCheckStateFile is a JSON file the created health check IDs are saved to, so they are
+	// reused after a restart. Without it they are only kept in memory.
+	HealthCheckStateFile string `yaml:"health_check_state_file"`
+}
+
+// Route53API is the subset of the AWS Route53 client used by the provider.
+//
+// It allows tests to substitute a mock client for *route53.Client.
+type Route53API interface {
+	ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error)
+	ChangeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error)
+	ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error)
+	ListHostedZonesByName(ctx context.Context, params *route53.ListHostedZonesByNameInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesByNameOutput, error)
+	GetHostedZone(ctx context.Context, params *route53.GetHostedZoneInput, optFns ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error)
+	GetChange(ctx context.Context, params *route53.GetChangeInput, optFns ...func(*route53.Options)) (*route53.GetChangeOutput, error)
+	CreateHealthCheck(ctx context.Context, params *route53.CreateHealthCheckInput, optFns ...func(*route53.Options)) (*route53.CreateHealthCheckOutput, error)
 }
 
 // Route53Provider implements the DNSProvider interface for AWS Route53.
 //
 // It uses the AWS SDK to query and update DNS records in a specified hosted zone.
 type Route53Provider struct {
-	Cfg    *Route53Config  // Provider-specific configuration
-	Client *route53.Client // Cached AWS Route53 client
+	Cfg    *Route53Config // Provider-specific configuration
+	Client Route53API     // Cached AWS Route53 client
+
+	pollInterval time.Duration                // GetChange polling interval; propagationPollInterval when zero
+	stsClient    stscreds.AssumeRoleAPIClient // Client used to assume the role; created from the AWS config when nil
+
+	zoneMu sync.Mutex // Serializes zone_name lookups
+	zoneID string     // Hosted zone ID resolved from zone_name
+
+	healthMu     sync.Mutex        // Serializes health check creation
+	healthChecks map[string]string // CallerReference -> health check ID; nil until loaded
+
+	logger *zerolog.Logger // Set by SetLogger; nil logs through logger.WithProvider
+}
+
+// SetLogger makes the provider log through l, such as a logger from logger.WithProvider; nil
+// restores the default. It must not be called while the provider is in use.
+func (r *Route53Provider) SetLogger(l *zerolog.Logger) { r.logger = l }
+
+// log returns the logger set with SetLogger, or by default a package-level logger tagged with the
+// provider's name.
+func (r *Route53Provider) log() *zerolog.Logger {
+	if r.logger != nil {
+		return r.logger
+	}
+	l := logger.WithProvider(r.ProviderName())
+	return &l
+}
+
+// ValidateConfig checks that the hosted zone is identified by exactly one of hosted_zone_id or
+// zone_name, that exactly one of record_name or record_names and a record_type are set, and that static credentials
+// are not combined with use_instance_profile. ALIAS records must be A or AAAA records and name
+// the target's hosted zone, records with a routing policy need a set_identifier, weighted records
+// need an explicit weight of 0-255, and geolocation records need exactly one of geo_continent_code or
+// geo_country_code. Health checks are only associated with records that use a routing policy.
+// propagation_timeout and its alias insync_timeout must agree when both are set.
+func (cfg *Route53Config) ValidateConfig() error {
+	switch {
+	case cfg.CreateHealthCheck && cfg.RoutingPolicy == "":
+		return errors.New("route53: create_health_check requires a routing_policy")
+	case cfg.HealthCheckProtocol != "" && cfg.HealthCheckProtocol != HealthCheckHTTP && cfg.HealthCheckProtocol != HealthCheckHTTPS:
+		return fmt.Errorf("route53: unsupported health_check_protocol %q (supported: %s, %s)", cfg.HealthCheckProtocol, HealthCheckHTTP, HealthCheckHTTPS)
+	case cfg.RoutingPolicy != "" && cfg.RoutingPolicy != RoutingPolicyWeighted && cfg.RoutingPolicy != RoutingPolicyGeolocation:
+		return fmt.Errorf("route53: unsupported routing_policy %q (supported: %s, %s)", cfg.RoutingPolicy, RoutingPolicyWeighted, RoutingPolicyGeolocation)
+	case cfg.geolocation() && (cfg.GeoContinentCode == "") == (cfg.GeoCountryCode == ""):
+		return errors.New("route53: set exactly one of geo_continent_code or geo_country_code with routing_policy geolocation")
+	case cfg.RoutingPolicy != "" && cfg.SetIdentifier == "":
+		return fmt.Errorf("route53: set_identifier is required with routing_policy %s", cfg.RoutingPolicy)
+	case cfg.RoutingPolicy == RoutingPolicyWeighted && cfg.Weight == nil:
+		return errors.New("route53: weight is required with routing_policy weighted")
+	case cfg.RoutingPolicy == RoutingPolicyWeighted && (*cfg.Weight < 0 || *cfg.Weight > 255):
+		return fmt.Errorf("route53: weight must be between 0 and 255, got %d", *cfg.Weight)
+	case cfg.AliasTarget != "" && cfg.RecordType != "A" && cfg.RecordType != "AAAA":
+		return fmt.Errorf("route53: alias_target requires record_type A or AAAA, got %q", cfg.RecordType)
+	case cfg.AliasTarget != "" && cfg.AliasHostedZoneID == "":
+		return errors.New("route53: alias_hosted_zone_id is required with alias_target")
+	case cfg.SessionToken != "" && cfg.AccessKeyID == "":
+		return errors.New("route53: session_token requires access_key_id and secret_access_key")
+	case (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == ""):
+		// Without keys, credentials come from the instance profile or the default AWS chain.
+		return errors.New("route53: access_key_id and secret_access_key must be set together")
+	case cfg.UseInstanceProfile && cfg.AccessKeyID != "":
+		return errors.New("route53: set either access_key_id or use_instance_profile, not both")
+	case cfg.RecordName != "" && len(cfg.RecordNames) > 0:
+		return errors.New("route53: set either record_name or record_names, not both")
+	case cfg.RecordName == "" && len(cfg.RecordNames) == 0:
+		return errors.New("route53: one of record_name or record_names is required")
+	case cfg.RecordType == "":
+		return errors.New("route53: record_type is required")
+	case cfg.HostedZoneID == "" && cfg.ZoneName == "":
+		return errors.New("route53: one of hosted_zone_id or zone_name is required")
+	case cfg.HostedZoneID != "" && cfg.ZoneName != "":
+		return errors.New("route53: set only one of hosted_zone_id or zone_name")
+	case cfg.PropagationTimeout > 0 && cfg.InSyncTimeout > 0 && cfg.PropagationTimeout != cfg.InSyncTimeout:
+		return errors.New("route53: propagation_timeout and insync_timeout disagree; set only one")
+	}
+	return nil
+}
+
+// ManagedRecordNames returns the names of the records this config manages: record_names if set,
+// otherwise record_name.
+func (cfg *Route53Config) ManagedRecordNames() []string {
+	if len(cfg.RecordNames) > 0 {
+		return cfg.RecordNames
+	}
+	if cfg.RecordName == "" {
+		return nil
+	}
+	return []string{cfg.RecordName}
+}
+
+// normalizeAlias returns an alias DNS name in the form used for comparison: lowercase without
+// the trailing dot.
+func normalizeAlias(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// weighted reports whether the records use weighted routing.
+func (cfg *Route53Config) weighted() bool {
+	return cfg.RoutingPolicy == RoutingPolicyWeighted
+}
+
+// geolocation reports whether the records use geolocation routing.
+func (cfg *Route53Config) geolocation() bool {
+	return cfg.RoutingPolicy == RoutingPolicyGeolocation
+}
+
+// matches reports whether record is the configured record set with the given name and type.
+// With weighted routing, its set identifier must also match; with geolocation routing, its location.
+func (cfg *Route53Config) matches(record r53types.ResourceRecordSet, name, recordType string) bool {
+	if !strings.EqualFold(*record.Name, name+".") || string(record.Type) != recordType {
+		return false
+	}
+	switch {
+	case cfg.weighted():
+		return aws.ToString(record.SetIdentifier) == cfg.SetIdentifier
+	case cfg.geolocation():
+		geo := record.GeoLocation
+		return geo != nil && aws.ToString(geo.ContinentCode) == cfg.GeoContinentCode &&
+			aws.ToString(geo.CountryCode) == cfg.GeoCountryCode && geo.SubdivisionCode == nil
+	}
+	return true
+}
+
+// CredentialsExpiry returns credentials_expiry if temporary credentials with an expiry are configured.
+func (r *Route53Provider) CredentialsExpiry() (time.Time, bool) {
+	if r.Cfg.SessionToken == "" || r.Cfg.CredentialsExpiry.IsZero() {
+		return time.Time{}, false
+	}
+	return r.Cfg.CredentialsExpiry, true
+}
+
+// StaticTarget returns the configured alias target when the records are ALIAS records.
+func (r *Route53Provider) StaticTarget() (string, bool) {
+	if r.Cfg.AliasTarget == "" {
+		return "", false
+	}
+	return normalizeAlias(r.Cfg.AliasTarget), true
+}
+
+// recordTTL returns the configured TTL or DefaultTTL.
+func (cfg *Route53Config) recordTTL() int64 {
+	if cfg.TTL > 0 {
+		return cfg.TTL
+	}
+	return DefaultTTL
+}
+
+func init() {
+	config.RegisterProviderValidator("route53", func(section map[string]any) error {
+		var cfg Route53Config
+		if err := config.ConfigFromMap(section, &cfg); err != nil {
+			return fmt.Errorf("route53: %w", err)
+		}
+		return cfg.ValidateConfig()
+	})
 }
 
 // New creates a new Route53Provider from a generic config map.
 //
+// The config is validated only if the provider is enabled.
+//
 // Usage: route53provider.New(configMap)
 func New(raw any) (*Route53Provider, error) {
 	var cfg Route53Config
@@ -63,86 +305,574 @@ func New(raw any) (*Route53Provider, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.Enabled {
+		if err := cfg.ValidateConfig(); err != nil {
+			return nil, err
+		}
+	}
 	return &Route53Provider{Cfg: &cfg}, nil
 }
 
-// getClient initializes and returns the AWS Route53 client, using static credentials from config.
-func (r *Route53Provider) getClient(ctx context.Context) (*route53.Client, error) {
+// getClient initializes and returns the AWS Route53 client, using static credentials from config,
+// including session_token for temporary credentials.
+//
+// With use_instance_profile, credentials come from the EC2 instance profile instead.
+//
+// When assume_role_arn is set, the role is assumed via STS and its temporary credentials are used
+// instead. The role is assumed with the instance profile or static credentials if configured, or
+// the default AWS credential chain otherwise.
+func (r *Route53Provider) getClient(ctx context.Context) (Route53API, error) {
 	if r.Client != nil {
 		return r.Client, nil
 	}
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithRegion(r.Cfg.Region),
-		awsconfig.WithCredentialsProvider(
-			credentials.NewStaticCredentialsProvider(r.Cfg.AccessKeyID, r.Cfg.SecretAccessKey, ""),
-		),
-	)
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(r.Cfg.Region)}
+	switch {
+	case r.Cfg.UseInstanceProfile:
+		opts = append(opts, awsconfig.WithCredentialsProvider(aws.NewCredentialsCache(ec2rolecreds.New())))
+	case r.Cfg.AssumeRoleARN == "" || r.Cfg.AccessKeyID != "":
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(r.Cfg.AccessKeyID, r.Cfg.SecretAccessKey, r.Cfg.SessionToken),
+		))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, err
 	}
+	if r.Cfg.AssumeRoleARN != "" {
+		stsClient := r.stsClient
+		if stsClient == nil {
+			stsClient = sts.NewFromConfig(awsCfg)
+		}
+		awsCfg.Credentials = r.assumeRoleCredentials(stsClient)
+	}
 	r.Client = route53.NewFromConfig(awsCfg)
 	return r.Client, nil
 }
 
+// assumeRoleCredentials returns credentials for the configured role, assumed with stsClient.
+//
+// The credentials are cached and the role is assumed again shortly before they expire.
+func (r *Route53Provider) assumeRoleCredentials(stsClient stscreds.AssumeRoleAPIClient) *aws.CredentialsCache {
+	sessionName := r.Cfg.RoleSessionName
+	if sessionName == "" {
+		sessionName = DefaultRoleSessionName
+	}
+	roleProvider := stscreds.NewAssumeRoleProvider(stsClient, r.Cfg.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = sessionName
+		if r.Cfg.ExternalID != "" {
+			o.ExternalID = aws.String(r.Cfg.ExternalID)
+		}
+	})
+	return aws.NewCredentialsCache(roleProvider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = time.Minute
+	})
+}
+
+// zoneLookupMaxItems bounds the zones returned by a zone_name lookup. Public and private zones
+// with the same name are listed next to each other, so a few are enough to see both.
+const zoneLookupMaxItems = 10
+
+// hostedZoneID returns the configured hosted zone ID, looking it up from zone_name on first use.
+//
+// If public and private zones share the name, the public one is used unless zone_private is set.
+// If several zones of that kind share it, a warning is logged and the first one listed is used.
+// A successful lookup is cached so it happens at most once; a failed lookup is retried on the
+// next call rather than leaving the provider permanently unusable.
+func (r *Route53Provider) hostedZoneID(ctx context.Context, client Route53API) (string, error) {
+	if r.Cfg.HostedZoneID != "" {
+		return r.Cfg.HostedZoneID, nil
+	}
+	r.zoneMu.Lock()
+	defer r.zoneMu.Unlock()
+	if r.zoneID != "" {
+		return r.zoneID, nil
+	}
+	r.log().Warn().Msgf("route53: resolving hosted zone ID for zone_name %q; set hosted_zone_id to skip this lookup", r.Cfg.ZoneName)
+	resp, err := client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{
+		DNSName:  aws.String(r.Cfg.ZoneName),
+		MaxItems: aws.Int32(zoneLookupMaxItems),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to look up hosted zone %q: %w", r.Cfg.ZoneName, err)
+	}
+	var found, matches []string
+	for _, zone := range resp.HostedZones {
+		private := zone.Config != nil && zone.Config.PrivateZone
+		if strings.EqualFold(strings.TrimSuffix(*zone.Name, "."), strings.TrimSuffix(r.Cfg.ZoneName, ".")) && private == r.Cfg.ZonePrivate {
+			matches = append(matches, strings.TrimPrefix(*zone.Id, "/hostedzone/"))
+			continue
+		}
+		if private {
+			found = append(found, *zone.Name+" (private)")
+		} else {
+			found = append(found, *zone.Name)
+		}
+	}
+	if len(matches) > 0 {
+		if len(matches) > 1 {
+			r.log().Warn().Msgf("route53: %d hosted zones named %s found (%s); using %s, set hosted_zone_id to choose another",
+				len(matches), r.Cfg.ZoneName, strings.Join(matches, ", "), matches[0])
+		}
+		r.zoneID = matches[0]
+		r.log().Info().Msgf("route53: resolved zone %s to hosted zone ID %s", r.Cfg.ZoneName, r.zoneID)
+		return r.zoneID, nil
+	}
+	if len(found) == 0 {
+		found = append(found, "none")
+	}
+	visibility := "public"
+	if r.Cfg.ZonePrivate {
+		visibility = "private"
+	}
+	return "", fmt.Errorf("%w: no %s zone named %q (found: %s)", errZoneNotFound, visibility, r.Cfg.ZoneName, strings.Join(found, ", "))
+}
+
+// Close releases the AWS client. The provider remains usable; the next call creates a new client.
+func (r *Route53Provider) Close() error {
+	r.Client = nil
+	return nil
+}
+
 // ProviderName returns the string "route53" for AWS Route53 providers.
 func (r *Route53Provider) ProviderName() string { return "route53" }
 
-// GetRecordIP fetches the current IP address for the Route53 DNS record.
+// RecordNames returns the names of the managed records (see providers.RecordNamer).
+func (r *Route53Provider) RecordNames() []string { return r.Cfg.ManagedRecordNames() }
+
+// ProviderConfig returns the hosted zone ID or name, record name, region, and any assumed role with AWS
+// credentials redacted.
+func (r *Route53Provider) ProviderConfig() map[string]string {
+	cfg := map[string]string{
+		"access_key_id":     providers.Redact(r.Cfg.AccessKeyID),
+		"secret_access_key": providers.Redact(r.Cfg.SecretAccessKey),
+		"hosted_zone_id":    r.Cfg.HostedZoneID,
+		"record_name":       strings.Join(r.Cfg.ManagedRecordNames(), ","),
+		"region":            r.Cfg.Region,
+	}
+	if r.Cfg.SessionToken != "" {
+		cfg["session_token"] = providers.Redact(r.Cfg.SessionToken)
+	}
+	if r.Cfg.ZoneName != "" {
+		cfg["zone_name"] = r.Cfg.ZoneName
+	}
+	if r.Cfg.AliasTarget != "" {
+		cfg["alias_target"] = r.Cfg.AliasTarget
+	}
+	if r.Cfg.AssumeRoleARN != "" {
+		cfg["assume_role_arn"] = r.Cfg.AssumeRoleARN
+		cfg["external_id"] = providers.Redact(r.Cfg.ExternalID)
+	}
+	return cfg
+}
+
+// findRecordSet looks up the resource record set with the given name and type in the hosted zone.
 //
-// Returns the IP address as a string, or an error if the record is not found or the API call fails.
-func (r *Route53Provider) GetRecordIP() (string, error) {
-	ctx := context.Background()
+// Returns the first matching record set (see eachRecordSet), or an error if the record is not found
+// or the API call fails.
+func (r *Route53Provider) findRecordSet(ctx context.Context, name, recordType string) (*r53types.ResourceRecordSet, error) {
 	client, err := r.getClient(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	input := &route53.ListResourceRecordSetsInput{
-		HostedZoneId:    aws.String(r.Cfg.HostedZoneID),
-		StartRecordName: aws.String(r.Cfg.RecordName),
-		StartRecordType: r53types.RRType(r.Cfg.RecordType),
-		MaxItems:        aws.Int32(1),
+	zoneID, err := r.hostedZoneID(ctx, client)
+	if err != nil {
+		return nil, err
 	}
-	resp, err := client.ListResourceRecordSets(ctx, input)
+	var found *r53types.ResourceRecordSet
+	err = r.eachRecordSet(ctx, client, zoneID, name, recordType, func(record r53types.ResourceRecordSet) bool {
+		found = &record
+		return false
+	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if found == nil {
+		return nil, errRecordNotFound
+	}
+	return found, nil
+}
+
+// eachRecordSet calls fn with each resource record set in the hosted zone that matches name and
+// recordType, until fn returns false.
+//
+// Listing starts at the record and follows IsTruncated through later pages. Record sets are listed
+// in order, so it stops as soon as the next page starts past the record.
+func (r *Route53Provider) eachRecordSet(ctx context.Context, client Route53API, zoneID, name, recordType string, fn func(r53types.ResourceRecordSet) bool) error {
+	input := &route53.ListResourceRecordSetsInput{
+		HostedZoneId:    aws.String(zoneID),
+		StartRecordName: aws.String(name),
+		StartRecordType: r53types.RRType(recordType),
 	}
-	for _, record := range resp.ResourceRecordSets {
-		if strings.EqualFold(*record.Name, r.Cfg.RecordName+".") && string(record.Type) == r.Cfg.RecordType {
-			if len(record.ResourceRecords) > 0 {
-				return *record.ResourceRecords[0].Value, nil
+	for {
+		resp, err := client.ListResourceRecordSets(ctx, input)
+		if err != nil {
+			return err
+		}
+		for _, record := range resp.ResourceRecordSets {
+			if r.Cfg.matches(record, name, recordType) && !fn(record) {
+				return nil
 			}
 		}
+		if !resp.IsTruncated || !strings.EqualFold(aws.ToString(resp.NextRecordName), name+".") ||
+			string(resp.NextRecordType) != recordType {
+			return nil
+		}
+		input.StartRecordName = resp.NextRecordName
+		input.StartRecordType = resp.NextRecordType
+		input.StartRecordIdentifier = resp.NextRecordIdentifier
 	}
-	return "", errors.New("record not found")
 }
 
-// UpdateRecordIP updates the Route53 DNS record to the given IP address.
+// GetRecordIP fetches the current IP address of the first configured Route53 DNS record.
 //
-// ip: The new IP address to set in the DNS record.
+// When several records are configured, the others are also read. If any of them holds a different
+// IP, the returned record's Content is empty so it never matches the current IP and every record is
+// updated. A record that cannot be read is only logged.
 //
-// Returns an error if the update fails.
-func (r *Route53Provider) UpdateRecordIP(ip string) error {
-	ctx := context.Background()
+// Returns the record with its first value and TTL, or an error if the record is not found or the API call fails.
+func (r *Route53Provider) GetRecordIP(ctx context.Context) (*providers.DNSRecord, error) {
+	names := r.Cfg.ManagedRecordNames()
+	if len(names) == 0 {
+		return nil, r.wrapError("get record", errors.New("no records configured"))
+	}
+	first, err := r.readRecord(ctx, names[0])
+	if err != nil {
+		return nil, r.wrapError("get record", err)
+	}
+	for _, name := range names[1:] {
+		other, err := r.readRecord(ctx, name)
+		if err != nil {
+			r.log().Warn().Msgf("route53: could not read %s %s: %v", r.Cfg.RecordType, name, err)
+			continue
+		}
+		if other.Content != first.Content {
+			r.log().Warn().Msgf("route53: %s holds %s but %s holds %s; records disagree, updating all of them",
+				other.Name, other.Content, first.Name, first.Content)
+			first.Content = ""
+		}
+	}
+	return first, nil
+}
+
+// readRecord returns the first value and TTL of the configured-type record with the given name.
+// For ALIAS records, the alias DNS name is returned as the value.
+func (r *Route53Provider) readRecord(ctx context.Context, name string) (*providers.DNSRecord, error) {
+	record, err := r.findRecordSet(ctx, name, r.Cfg.RecordType)
+	if err != nil {
+		return nil, err
+	}
+	if record.AliasTarget != nil {
+		return r.aliasRecord(record), nil
+	}
+	if len(record.ResourceRecords) == 0 {
+		return nil, errRecordNotFound
+	}
+	var ttl int64
+	if record.TTL != nil {
+		ttl = *record.TTL
+	}
+	return &providers.DNSRecord{
+		Name:     strings.TrimSuffix(*record.Name, "."),
+		Type:     string(record.Type),
+		Content:  *record.ResourceRecords[0].Value,
+		TTL:      ttl,
+		Provider: r.ProviderName(),
+	}, nil
+}
+
+// GetRecordTTL fetches the current TTL (in seconds) of the first configured Route53 DNS record.
+//
+// Returns an error if the record is not found, the API call fails, or the record set has no TTL (e.g. alias records).
+func (r *Route53Provider) GetRecordTTL(ctx context.Context) (int64, error) {
+	names := r.Cfg.ManagedRecordNames()
+	if len(names) == 0 {
+		return 0, r.wrapError("get record TTL", errors.New("no records configured"))
+	}
+	record, err := r.findRecordSet(ctx, names[0], r.Cfg.RecordType)
+	if err != nil {
+		return 0, r.wrapError("get record TTL", err)
+	}
+	if record.TTL == nil {
+		return 0, r.wrapError("get record TTL", errors.New("record has no TTL"))
+	}
+	return *record.TTL, nil
+}
+
+// UpdateRecordIP updates every configured Route53 DNS record to the given IP address.
+//
+// ip: The new IP address to set in the DNS records. The TTL is set from config.
+//
+// All records are upserted in a single change batch, which Route53 applies atomically.
+//
+// With alias_target, the records are upserted as ALIAS records to that target and ip is ignored.
+//
+// With create_health_check, each record's health check is created on first use and associated
+// with the record. A record whose health check cannot be created is left out of the batch.
+//
+// When wait_for_propagation (or wait_for_insync) is set, it then waits for the change to reach INSYNC.
+//
+// Returns an error if the update or health check creation fails, or a *PropagationTimeoutError (matching
+// ErrChangePollTimeout) if the change does not propagate in time. Errors while waiting for propagation
+// are never Temporary, since the change was already accepted.
+// When several records are configured, failures are reported per record in a *providers.MultiError.
+func (r *Route53Provider) UpdateRecordIP(ctx context.Context, ip string) error {
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return r.wrapError("update record", err)
+	}
+	zoneID, err := r.hostedZoneID(ctx, client)
+	if err != nil {
+		return r.wrapError("update record", err)
+	}
+	names := r.Cfg.ManagedRecordNames()
+	if len(names) == 0 {
+		return r.wrapError("update record", errors.New("no records configured"))
+	}
+	multi := &providers.MultiError{Total: len(names)}
+	changes := make([]r53types.Change, 0, len(names))
+	batched := make([]string, 0, len(names))
+	for _, name := range names {
+		set := r.resourceRecordSet(name, ip)
+		if r.Cfg.CreateHealthCheck {
+			id, err := r.healthCheckID(ctx, client, zoneID, name)
+			if err != nil {
+				multi.Errors = append(multi.Errors, r.wrapError("update record", fmt.Errorf("%s: %w", name, err)))
+				continue
+			}
+			set.HealthCheckId = aws.String(id)
+		}
+		changes = append(changes, r53types.Change{
+			Action:            r53types.ChangeActionUpsert,
+			ResourceRecordSet: set,
+		})
+		batched = append(batched, name)
+	}
+	if len(changes) > 0 {
+		accepted, err := r.submitChanges(ctx, client, zoneID, changes)
+		if err != nil {
+			// The batch is applied atomically, so the error applies to every record in it.
+			for _, name := range batched {
+				recErr := r.wrapError("update record", fmt.Errorf("%s: %w", name, err))
+				var pe *providers.ProviderError
+				if accepted && errors.As(recErr, &pe) {
+					pe.Temporary = false // Retrying would only submit the accepted change again
+				}
+				multi.Errors = append(multi.Errors, recErr)
+			}
+		}
+	}
+	switch {
+	case len(multi.Errors) == 0:
+		return nil
+	case multi.Total == 1:
+		return multi.Errors[0]
+	}
+	return multi
+}
+
+// submitChanges upserts changes in a single batch and, with wait_for_propagation, waits for the
+// batch to reach INSYNC. accepted reports whether Route53 accepted the batch, so that an error
+// while waiting can be told apart from a rejected change.
+func (r *Route53Provider) submitChanges(ctx context.Context, client Route53API, zoneID string, changes []r53types.Change) (accepted bool, err error) {
+	resp, err := client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+		ChangeBatch:  &r53types.ChangeBatch{Changes: changes},
+	})
+	if err != nil {
+		return false, err
+	}
+	if !r.Cfg.waitForInSync() {
+		return true, nil
+	}
+	return true, r.waitForChange(ctx, client, resp.ChangeInfo)
+}
+
+// DeleteRecord removes the Route53 DNS record with the given name and type.
+//
+// Route53 requires a DELETE change to match the existing record set exactly, so the
+// current record set is looked up first. Returns an error if the record is not found or the API call fails.
+func (r *Route53Provider) DeleteRecord(ctx context.Context, name, recordType string) error {
 	client, err := r.getClient(ctx)
 	if err != nil {
-		return err
+		return r.wrapError("delete record", err)
+	}
+	zoneID, err := r.hostedZoneID(ctx, client)
+	if err != nil {
+		return r.wrapError("delete record", err)
+	}
+	record, err := r.findRecordSet(ctx, name, recordType)
+	if err != nil {
+		return r.wrapError("delete record", err)
 	}
 	input := &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: aws.String(r.Cfg.HostedZoneID),
+		HostedZoneId: aws.String(zoneID),
 		ChangeBatch: &r53types.ChangeBatch{
 			Changes: []r53types.Change{
 				{
-					Action: r53types.ChangeActionUpsert,
-					ResourceRecordSet: &r53types.ResourceRecordSet{
-						Name:            aws.String(r.Cfg.RecordName),
-						Type:            r53types.RRType(r.Cfg.RecordType),
-						TTL:             aws.Int64(300),
-						ResourceRecords: []r53types.ResourceRecord{{Value: aws.String(ip)}},
-					},
+					Action:            r53types.ChangeActionDelete,
+					ResourceRecordSet: record,
 				},
 			},
 		},
 	}
 	_, err = client.ChangeResourceRecordSets(ctx, input)
-	return err
+	return r.wrapError("delete record", err)
+}
+
+// aliasRecord returns an ALIAS record set as a DNSRecord whose IP holds the alias DNS name.
+func (r *Route53Provider) aliasRecord(record *r53types.ResourceRecordSet) *providers.DNSRecord {
+	return &providers.DNSRecord{
+		Name:     strings.TrimSuffix(*record.Name, "."),
+		Type:     string(record.Type),
+		Content:  normalizeAlias(aws.ToString(record.AliasTarget.DNSName)),
+		Provider: r.ProviderName(),
+	}
+}
+
+// resourceRecordSet returns the record set that UpdateRecordIP upserts for name: an ALIAS to
+// alias_target if configured, otherwise a record holding ip with the configured TTL. Weighted
+// records also carry their set identifier and weight, and geolocation records their set
+// identifier and location.
+func (r *Route53Provider) resourceRecordSet(name, ip string) *r53types.ResourceRecordSet {
+	set := &r53types.ResourceRecordSet{
+		Name: aws.String(name),
+		Type: r53types.RRType(r.Cfg.RecordType),
+	}
+	if r.Cfg.AliasTarget != "" {
+		set.AliasTarget = &r53types.AliasTarget{
+			DNSName:              aws.String(r.Cfg.AliasTarget),
+			HostedZoneId:         aws.String(r.Cfg.AliasHostedZoneID),
+			EvaluateTargetHealth: r.Cfg.EvaluateTargetHealth,
+		}
+	} else {
+		set.TTL = aws.Int64(r.Cfg.recordTTL())
+		set.ResourceRecords = []r53types.ResourceRecord{{Value: aws.String(ip)}}
+	}
+	if r.Cfg.weighted() {
+		set.SetIdentifier = aws.String(r.Cfg.SetIdentifier)
+		set.Weight = aws.Int64(*r.Cfg.Weight)
+	}
+	if r.Cfg.geolocation() {
+		set.SetIdentifier = aws.String(r.Cfg.SetIdentifier)
+		set.GeoLocation = &r53types.GeoLocation{}
+		if r.Cfg.GeoContinentCode != "" {
+			set.GeoLocation.ContinentCode = aws.String(r.Cfg.GeoContinentCode)
+		} else {
+			set.GeoLocation.CountryCode = aws.String(r.Cfg.GeoCountryCode)
+		}
+	}
+	return set
+}
+
+// ListManagedRecords returns the configured Route53 DNS records as currently held in the hosted zone.
+//
+// Each record is listed page by page like findRecordSet. Configured records that do not exist yet
+// are omitted.
+func (r *Route53Provider) ListManagedRecords(ctx context.Context) ([]providers.DNSRecord, error) {
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return nil, r.wrapError("list records", err)
+	}
+	zoneID, err := r.hostedZoneID(ctx, client)
+	if err != nil {
+		return nil, r.wrapError("list records", err)
+	}
+	managed := []providers.DNSRecord{}
+	for _, name := range r.Cfg.ManagedRecordNames() {
+		err := r.eachRecordSet(ctx, client, zoneID, name, r.Cfg.RecordType, func(record r53types.ResourceRecordSet) bool {
+			if record.AliasTarget != nil {
+				managed = append(managed, *r.aliasRecord(&record))
+				return true
+			}
+			var ttl int64
+			if record.TTL != nil {
+				ttl = *record.TTL
+			}
+			for _, rr := range record.ResourceRecords {
+				managed = append(managed, providers.DNSRecord{
+					Name:     strings.TrimSuffix(*record.Name, "."),
+					Type:     string(record.Type),
+					Content:  *rr.Value,
+					TTL:      ttl,
+					Provider: r.ProviderName(),
+				})
+			}
+			return true
+		})
+		if err != nil {
+			return nil, r.wrapError("list records", err)
+		}
+	}
+	return managed, nil
+}
+
+// SelfTest verifies credentials and network access by listing a single hosted zone.
+//
+// Only read-only API calls are made; DNS records are never modified.
+func (r *Route53Provider) SelfTest(ctx context.Context) error {
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return r.wrapError("self test", err)
+	}
+	_, err = client.ListHostedZones(ctx, &route53.ListHostedZonesInput{MaxItems: aws.Int32(1)})
+	return r.wrapError("self test", err)
+}
+
+// HealthCheck verifies credentials and that the configured hosted zone exists.
+func (r *Route53Provider) HealthCheck(ctx context.Context) error {
+	client, err := r.getClient(ctx)
+	if err != nil {
+		return r.wrapError("health check", err)
+	}
+	zoneID, err := r.hostedZoneID(ctx, client)
+	if err != nil {
+		return r.wrapError("health check", err)
+	}
+	_, err = client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(zoneID)})
+	return r.wrapError("health check", err)
+}
+
+// wrapError wraps err in a providers.ProviderError classified from the AWS API error code
+// and HTTP status. Returns nil if err is nil.
+func (r *Route53Provider) wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	pe := &providers.ProviderError{Provider: r.ProviderName(), Op: op, Err: err}
+	if errors.Is(err, errRecordNotFound) || errors.Is(err, errZoneNotFound) {
+		pe.NotFound = true
+		return pe
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDenied", "AccessDeniedException", "InvalidClientTokenId", "UnrecognizedClientException",
+			"SignatureDoesNotMatch", "ExpiredToken", "ExpiredTokenException":
+			pe.Unauthorized = true
+		case "Throttling", "ThrottlingException", "TooManyRequestsException":
+			pe.RateLimited = true
+			pe.Temporary = true
+		case "PriorRequestNotComplete", "ServiceUnavailable", "InternalFailure":
+			pe.Temporary = true
+		case "NoSuchHostedZone", "NoSuchChange":
+			pe.NotFound = true
+		}
+	}
+	var statusErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &statusErr) {
+		switch code := statusErr.HTTPStatusCode(); {
+		case code == http.StatusUnauthorized, code == http.StatusForbidden:
+			pe.Unauthorized = true
+		case code == http.StatusTooManyRequests:
+			pe.RateLimited = true
+			pe.Temporary = true
+		case code >= http.StatusInternalServerError:
+			pe.Temporary = true
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		pe.Temporary = true
+	}
+	return pe
 }
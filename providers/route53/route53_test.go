@@ -20,9 +20,150 @@
 package route53
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	r53types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/aws/smithy-go"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aaronlmathis/dynago/internal/config"
+	providers "github.com/aaronlmathis/dynago/providers"
 )
 
+// mockRoute53Client is a Route53API implementation that serves canned record sets and records changes.
+type mockRoute53Client struct {
+	listErr      error
+	recordSets   []r53types.ResourceRecordSet
+	pages        []*route53.ListResourceRecordSetsOutput // Served in order instead of recordSets when set
+	listInputs   []*route53.ListResourceRecordSetsInput
+	changes      []*route53.ChangeResourceRecordSetsInput
+	changeErr    error
+	hostedZones  []r53types.HostedZone
+	listZonesErr error
+	getZoneErr   error
+	pendingPolls int // GetChange calls that report PENDING before INSYNC; negative never syncs
+	getChanges   int // Number of GetChange calls made
+	getChangeErr error
+	zoneLookups  int // Number of ListHostedZonesByName calls made
+	healthChecks []*route53.CreateHealthCheckInput
+	healthErr    error
+	healthErrFor string // Only health checks for this domain name fail with healthErr when set
+}
+
+func (m *mockRoute53Client) ListResourceRecordSets(ctx context.Context, params *route53.ListResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ListResourceRecordSetsOutput, error) {
+	input := *params // The provider reuses its input across pages
+	m.listInputs = append(m.listInputs, &input)
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+	if len(m.pages) > 0 {
+		return m.pages[len(m.listInputs)-1], nil
+	}
+	return &route53.ListResourceRecordSetsOutput{ResourceRecordSets: m.recordSets}, nil
+}
+
+func (m *mockRoute53Client) ChangeResourceRecordSets(ctx context.Context, params *route53.ChangeResourceRecordSetsInput, optFns ...func(*route53.Options)) (*route53.ChangeResourceRecordSetsOutput, error) {
+	m.changes = append(m.changes, params)
+	if m.changeErr != nil {
+		return nil, m.changeErr
+	}
+	return &route53.ChangeResourceRecordSetsOutput{
+		ChangeInfo: &r53types.ChangeInfo{Id: aws.String("/change/C1"), Status: r53types.ChangeStatusPending},
+	}, nil
+}
+
+func (m *mockRoute53Client) GetChange(ctx context.Context, params *route53.GetChangeInput, optFns ...func(*route53.Options)) (*route53.GetChangeOutput, error) {
+	m.getChanges++
+	if m.getChangeErr != nil {
+		return nil, m.getChangeErr
+	}
+	status := r53types.ChangeStatusInsync
+	if m.pendingPolls < 0 || m.getChanges <= m.pendingPolls {
+		status = r53types.ChangeStatusPending
+	}
+	return &route53.GetChangeOutput{ChangeInfo: &r53types.ChangeInfo{Id: params.Id, Status: status}}, nil
+}
+
+func (m *mockRoute53Client) GetHostedZone(ctx context.Context, params *route53.GetHostedZoneInput, optFns ...func(*route53.Options)) (*route53.GetHostedZoneOutput, error) {
+	if m.getZoneErr != nil {
+		return nil, m.getZoneErr
+	}
+	return &route53.GetHostedZoneOutput{HostedZone: &r53types.HostedZone{Id: params.Id}}, nil
+}
+
+func (m *mockRoute53Client) ListHostedZones(ctx context.Context, params *route53.ListHostedZonesInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesOutput, error) {
+	if m.listZonesErr != nil {
+		return nil, m.listZonesErr
+	}
+	return &route53.ListHostedZonesOutput{HostedZones: m.hostedZones}, nil
+}
+
+func (m *mockRoute53Client) ListHostedZonesByName(ctx context.Context, params *route53.ListHostedZonesByNameInput, optFns ...func(*route53.Options)) (*route53.ListHostedZonesByNameOutput, error) {
+	m.zoneLookups++
+	if m.listZonesErr != nil {
+		return nil, m.listZonesErr
+	}
+	return &route53.ListHostedZonesByNameOutput{HostedZones: m.hostedZones}, nil
+}
+
+func (m *mockRoute53Client) CreateHealthCheck(ctx context.Context, params *route53.CreateHealthCheckInput, optFns ...func(*route53.Options)) (*route53.CreateHealthCheckOutput, error) {
+	m.healthChecks = append(m.healthChecks, params)
+	if m.healthErr != nil && (m.healthErrFor == "" || aws.ToString(params.HealthCheckConfig.FullyQualifiedDomainName) == m.healthErrFor) {
+		return nil, m.healthErr
+	}
+	id := "hc-" + aws.ToString(params.CallerReference) // Same reference, same health check, as in Route53
+	return &route53.CreateHealthCheckOutput{HealthCheck: &r53types.HealthCheck{Id: aws.String(id)}}, nil
+}
+
+// mockSTSClient is an AssumeRole client that returns canned credentials valid for expiresIn.
+type mockSTSClient struct {
+	expiresIn time.Duration
+	inputs    []*sts.AssumeRoleInput
+}
+
+func (m *mockSTSClient) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	m.inputs = append(m.inputs, params)
+	return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+		AccessKeyId:     aws.String(fmt.Sprintf("ASIA%d", len(m.inputs))),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("session"),
+		Expiration:      aws.Time(time.Now().Add(m.expiresIn)),
+	}}, nil
+}
+
+// newTestProvider returns a Route53Provider backed by the given mock client.
+func newTestProvider(client *mockRoute53Client) *Route53Provider {
+	return &Route53Provider{
+		Cfg:    &Route53Config{Enabled: true, HostedZoneID: "zone", RecordName: "home.example.com", RecordType: "A"},
+		Client: client,
+	}
+}
+
+// aRecord returns an A record set for name pointing at ip.
+func aRecord(name, ip string) r53types.ResourceRecordSet {
+	return r53types.ResourceRecordSet{
+		Name:            aws.String(name + "."),
+		Type:            r53types.RRTypeA,
+		TTL:             aws.Int64(300),
+		ResourceRecords: []r53types.ResourceRecord{{Value: aws.String(ip)}},
+	}
+}
+
 func TestRoute53Provider_New_Unmarshal(t *testing.T) {
 	cfgMap := map[string]any{
 		"enabled":           true,
@@ -42,9 +183,843 @@ func TestRoute53Provider_New_Unmarshal(t *testing.T) {
 	}
 }
 
+func TestRoute53Provider_New_AssumeRole(t *testing.T) {
+	cfgMap := map[string]any{
+		"enabled":         true,
+		"hosted_zone_id":  "zone",
+		"record_name":     "name",
+		"record_type":     "A",
+		"region":          "us-east-1",
+		"assume_role_arn": "arn:aws:iam::123456789012:role/dynago",
+		"external_id":     "ext-123",
+	}
+	p, err := New(cfgMap)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Cfg.AssumeRoleARN != "arn:aws:iam::123456789012:role/dynago" || p.Cfg.ExternalID != "ext-123" {
+		t.Errorf("assume role config not unmarshaled correctly: %+v", p.Cfg)
+	}
+	if p.Cfg.AccessKeyID != "" {
+		t.Errorf("expected no static credentials, got %q", p.Cfg.AccessKeyID)
+	}
+}
+
+func TestRoute53Provider_GetClient_Credentials(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Route53Config
+		want aws.CredentialsProvider // Provider type expected in the client's credentials
+	}{
+		{"static", Route53Config{AccessKeyID: "id", SecretAccessKey: "secret", Region: "us-east-1"}, credentials.StaticCredentialsProvider{}},
+		{"assume role", Route53Config{AccessKeyID: "id", SecretAccessKey: "secret", Region: "us-east-1", AssumeRoleARN: "arn:aws:iam::123456789012:role/dynago", ExternalID: "ext"}, (*stscreds.AssumeRoleProvider)(nil)},
+		{"instance profile", Route53Config{UseInstanceProfile: true, Region: "us-east-1"}, (*ec2rolecreds.Provider)(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Route53Provider{Cfg: &tt.cfg}
+			client, err := p.getClient(context.Background())
+			if err != nil {
+				t.Fatalf("getClient failed: %v", err)
+			}
+			creds := client.(*route53.Client).Options().Credentials
+			if !aws.IsCredentialsProvider(creds, tt.want) {
+				t.Errorf("expected %T credentials, got %T", tt.want, creds)
+			}
+		})
+	}
+}
+
+func TestRoute53Provider_SessionToken(t *testing.T) {
+	p, err := New(map[string]any{
+		"enabled":            true,
+		"access_key_id":      "ASIAEXAMPLE",
+		"secret_access_key":  "secret",
+		"session_token":      "session",
+		"credentials_expiry": "2030-01-02T03:04:05Z",
+		"hosted_zone_id":     "zone",
+		"record_name":        "name",
+		"record_type":        "A",
+		"region":             "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expiry, ok := p.CredentialsExpiry()
+	if !ok || !expiry.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("expected credentials expiry 2030-01-02T03:04:05Z, got %s (%v)", expiry, ok)
+	}
+	client, err := p.getClient(context.Background())
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	creds, err := client.(*route53.Client).Options().Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if creds.AccessKeyID != "ASIAEXAMPLE" || creds.SessionToken != "session" {
+		t.Errorf("expected the session token to be passed to the credentials, got %+v", creds)
+	}
+	if p.ProviderConfig()["session_token"] != "***" {
+		t.Errorf("expected session_token to be redacted, got %q", p.ProviderConfig()["session_token"])
+	}
+
+	p.Cfg.SessionToken = ""
+	if _, ok := p.CredentialsExpiry(); ok {
+		t.Error("expected no credentials expiry without a session token")
+	}
+}
+
+func TestRoute53Config_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Route53Config
+		wantErr bool
+	}{
+		{"hosted zone id", Route53Config{HostedZoneID: "zone", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"zone name", Route53Config{ZoneName: "example.com", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"neither", Route53Config{RecordName: "home.example.com", RecordType: "A"}, true},
+		{"both", Route53Config{HostedZoneID: "zone", ZoneName: "example.com", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"instance profile", Route53Config{HostedZoneID: "zone", UseInstanceProfile: true, RecordName: "home.example.com", RecordType: "A"}, false},
+		{"session token", Route53Config{HostedZoneID: "zone", AccessKeyID: "id", SecretAccessKey: "secret", SessionToken: "session", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"session token without keys", Route53Config{HostedZoneID: "zone", SessionToken: "session", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"insync_timeout", Route53Config{HostedZoneID: "zone", InSyncTimeout: time.Minute, RecordName: "home.example.com", RecordType: "A"}, false},
+		{"insync_timeout disagrees", Route53Config{HostedZoneID: "zone", PropagationTimeout: time.Minute, InSyncTimeout: 2 * time.Minute, RecordName: "home.example.com", RecordType: "A"}, true},
+		{"instance profile and static", Route53Config{HostedZoneID: "zone", UseInstanceProfile: true, AccessKeyID: "id", SecretAccessKey: "secret", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"access key without secret", Route53Config{HostedZoneID: "zone", AccessKeyID: "id", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"alias", Route53Config{HostedZoneID: "zone", RecordType: "AAAA", AliasTarget: "d111.cloudfront.net", AliasHostedZoneID: "Z2FDTNDATAQYW2", RecordName: "home.example.com"}, false},
+		{"alias CNAME", Route53Config{HostedZoneID: "zone", RecordType: "CNAME", AliasTarget: "d111.cloudfront.net", AliasHostedZoneID: "Z2FDTNDATAQYW2", RecordName: "home.example.com"}, true},
+		{"alias without zone", Route53Config{HostedZoneID: "zone", RecordType: "A", AliasTarget: "d111.cloudfront.net", RecordName: "home.example.com"}, true},
+		{"weighted", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", Weight: aws.Int64(10), RecordName: "home.example.com", RecordType: "A"}, false},
+		{"weight zero", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", Weight: aws.Int64(0), RecordName: "home.example.com", RecordType: "A"}, false},
+		{"weighted without set identifier", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", Weight: aws.Int64(10), RecordName: "home.example.com", RecordType: "A"}, true},
+		{"weighted without weight", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"weight out of range", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", Weight: aws.Int64(256), RecordName: "home.example.com", RecordType: "A"}, true},
+		{"geolocation continent", Route53Config{HostedZoneID: "zone", RoutingPolicy: "geolocation", SetIdentifier: "europe", GeoContinentCode: "EU", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"geolocation country", Route53Config{HostedZoneID: "zone", RoutingPolicy: "geolocation", SetIdentifier: "germany", GeoCountryCode: "DE", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"geolocation without set identifier", Route53Config{HostedZoneID: "zone", RoutingPolicy: "geolocation", GeoCountryCode: "DE", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"geolocation without location", Route53Config{HostedZoneID: "zone", RoutingPolicy: "geolocation", SetIdentifier: "germany", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"geolocation with both locations", Route53Config{HostedZoneID: "zone", RoutingPolicy: "geolocation", SetIdentifier: "germany", GeoContinentCode: "EU", GeoCountryCode: "DE", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"health check", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", Weight: aws.Int64(10), CreateHealthCheck: true, HealthCheckProtocol: "HTTPS", RecordName: "home.example.com", RecordType: "A"}, false},
+		{"health check without routing policy", Route53Config{HostedZoneID: "zone", CreateHealthCheck: true, RecordName: "home.example.com", RecordType: "A"}, true},
+		{"unknown health check protocol", Route53Config{HostedZoneID: "zone", RoutingPolicy: "weighted", SetIdentifier: "home", Weight: aws.Int64(10), CreateHealthCheck: true, HealthCheckProtocol: "TCP", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"unknown routing policy", Route53Config{HostedZoneID: "zone", RoutingPolicy: "latency", RecordName: "home.example.com", RecordType: "A"}, true},
+		{"record names", Route53Config{HostedZoneID: "zone", RecordNames: []string{"a.example.com", "b.example.com"}, RecordType: "A"}, false},
+		{"record_name and record_names", Route53Config{HostedZoneID: "zone", RecordName: "a.example.com", RecordNames: []string{"b.example.com"}, RecordType: "A"}, true},
+		{"no record name", Route53Config{HostedZoneID: "zone", RecordType: "A"}, true},
+		{"no record type", Route53Config{HostedZoneID: "zone", RecordName: "home.example.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.ValidateConfig(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := New(map[string]any{"enabled": true, "record_name": "home.example.com"}); err == nil {
+		t.Errorf("expected New to reject an enabled provider without a hosted zone")
+	}
+	if _, err := New(map[string]any{"enabled": false}); err != nil {
+		t.Errorf("expected New to skip validation for a disabled provider, got %v", err)
+	}
+}
+
+func TestRoute53Config_ValidatedByConfigPackage(t *testing.T) {
+	cfg := &config.Config{Interval: time.Hour, IPSource: "https://api.ipify.org", Providers: map[string]any{
+		"route53": map[string]any{"enabled": true, "hosted_zone_id": "zone", "record_name": "home.example.com", "record_type": "A", "access_key_id": "id"},
+	}}
+	if err := config.ValidateConfig(cfg); err == nil || !strings.Contains(err.Error(), "route53: access_key_id and secret_access_key must be set together") {
+		t.Errorf("expected config.ValidateConfig to apply Route53Config.ValidateConfig, got %v", err)
+	}
+}
+
+func TestRoute53Provider_ZoneNameLookupOnce(t *testing.T) {
+	client := &mockRoute53Client{
+		hostedZones: []r53types.HostedZone{{Id: aws.String("/hostedzone/Z123"), Name: aws.String("example.com.")}},
+		recordSets:  []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")},
+	}
+	p := newTestProvider(client)
+	p.Cfg.HostedZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+	for i := 0; i < 3; i++ {
+		if _, err := p.GetRecordIP(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.zoneLookups != 1 {
+		t.Errorf("expected 1 zone lookup, got %d", client.zoneLookups)
+	}
+	if got := *client.changes[0].HostedZoneId; got != "Z123" {
+		t.Errorf("expected update in looked-up zone Z123, got %q", got)
+	}
+}
+
+func TestRoute53Provider_SetLogger(t *testing.T) {
+	client := &mockRoute53Client{
+		hostedZones: []r53types.HostedZone{{Id: aws.String("/hostedzone/Z123"), Name: aws.String("example.com.")}},
+		recordSets:  []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")},
+	}
+	p := newTestProvider(client)
+	p.Cfg.HostedZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+	var buf bytes.Buffer
+	l := zerolog.New(&buf).With().Str("provider", "route53").Logger()
+	p.SetLogger(&l)
+
+	if _, err := p.GetRecordIP(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, `"provider":"route53"`) || !strings.Contains(out, "resolved zone example.com to hosted zone ID Z123") {
+		t.Errorf("expected the zone lookup to be logged through the set logger, got %q", out)
+	}
+}
+
+func TestRoute53Provider_ZoneNamePublicOrPrivate(t *testing.T) {
+	zones := []r53types.HostedZone{
+		{Id: aws.String("/hostedzone/ZPRIVATE"), Name: aws.String("example.com."), Config: &r53types.HostedZoneConfig{PrivateZone: true}},
+		{Id: aws.String("/hostedzone/ZPUBLIC"), Name: aws.String("example.com."), Config: &r53types.HostedZoneConfig{}},
+	}
+	tests := []struct {
+		name    string
+		private bool
+		want    string
+	}{
+		{"public by default", false, "ZPUBLIC"},
+		{"private", true, "ZPRIVATE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(&mockRoute53Client{hostedZones: zones})
+			p.Cfg.HostedZoneID = ""
+			p.Cfg.ZoneName = "example.com."
+			p.Cfg.ZonePrivate = tt.private
+			got, err := p.hostedZoneID(context.Background(), p.Client)
+			if err != nil || got != tt.want {
+				t.Errorf("hostedZoneID() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+	p := newTestProvider(&mockRoute53Client{hostedZones: zones[:1]})
+	p.Cfg.HostedZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+	if _, err := p.hostedZoneID(context.Background(), p.Client); err == nil || !strings.Contains(err.Error(), "example.com. (private)") {
+		t.Errorf("expected error listing the private zone when looking for a public one, got %v", err)
+	}
+}
+
+func TestRoute53Provider_ZoneNameSeveralMatches(t *testing.T) {
+	p := newTestProvider(&mockRoute53Client{hostedZones: []r53types.HostedZone{
+		{Id: aws.String("/hostedzone/ZFIRST"), Name: aws.String("example.com.")},
+		{Id: aws.String("/hostedzone/ZSECOND"), Name: aws.String("example.com.")},
+	}})
+	p.Cfg.HostedZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+	var buf bytes.Buffer
+	l := zerolog.New(&buf)
+	p.SetLogger(&l)
+
+	got, err := p.hostedZoneID(context.Background(), p.Client)
+	if err != nil || got != "ZFIRST" {
+		t.Errorf("hostedZoneID() = %q, %v; want ZFIRST", got, err)
+	}
+	if out := buf.String(); !strings.Contains(out, "ZFIRST, ZSECOND") {
+		t.Errorf("expected a warning listing both zones, got %q", out)
+	}
+}
+
+func TestRoute53Provider_ZoneNameLookupErrors(t *testing.T) {
+	client := &mockRoute53Client{
+		hostedZones: []r53types.HostedZone{{Id: aws.String("/hostedzone/Z999"), Name: aws.String("other.com.")}},
+	}
+	p := newTestProvider(client)
+	p.Cfg.HostedZoneID = ""
+	p.Cfg.ZoneName = "example.com"
+	_, err := p.GetRecordIP(context.Background())
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || !pe.NotFound || !strings.Contains(err.Error(), "other.com.") {
+		t.Errorf("expected NotFound error listing the returned zones, got %v", err)
+	}
+	client.hostedZones = []r53types.HostedZone{{Id: aws.String("/hostedzone/Z123"), Name: aws.String("example.com.")}}
+	if _, err := p.GetRecordIP(context.Background()); err == nil || client.zoneLookups != 2 {
+		t.Errorf("expected failed lookup to be retried, got %d lookups (err %v)", client.zoneLookups, err)
+	}
+}
+
+func TestRoute53Provider_TTLFromYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want int64
+	}{
+		{"configured", "enabled: true\nhosted_zone_id: zone\nrecord_name: home.example.com\nrecord_type: A\nttl: 60\n", 60},
+		{"default", "enabled: true\nhosted_zone_id: zone\nrecord_name: home.example.com\nrecord_type: A\n", DefaultTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw map[string]any
+			if err := yaml.Unmarshal([]byte(tt.yaml), &raw); err != nil {
+				t.Fatalf("failed to parse YAML: %v", err)
+			}
+			p, err := New(raw)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			client := &mockRoute53Client{}
+			p.Client = client
+			if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := *client.changes[0].ChangeBatch.Changes[0].ResourceRecordSet.TTL; got != tt.want {
+				t.Errorf("expected TTL %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRoute53Provider_AssumeRoleCredentials(t *testing.T) {
+	stsClient := &mockSTSClient{expiresIn: time.Hour}
+	p := &Route53Provider{
+		Cfg:       &Route53Config{Region: "us-east-1", AssumeRoleARN: "arn:aws:iam::123456789012:role/dynago", ExternalID: "ext"},
+		stsClient: stsClient,
+	}
+	client, err := p.getClient(context.Background())
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	creds := client.(*route53.Client).Options().Credentials
+	for i := 0; i < 2; i++ {
+		got, err := creds.Retrieve(context.Background())
+		if err != nil {
+			t.Fatalf("Retrieve failed: %v", err)
+		}
+		if got.AccessKeyID != "ASIA1" || got.SessionToken != "session" {
+			t.Errorf("expected assumed-role credentials, got %+v", got)
+		}
+	}
+	if len(stsClient.inputs) != 1 {
+		t.Fatalf("expected the role to be assumed once while credentials are valid, got %d", len(stsClient.inputs))
+	}
+	input := stsClient.inputs[0]
+	if *input.RoleArn != p.Cfg.AssumeRoleARN || *input.RoleSessionName != DefaultRoleSessionName || *input.ExternalId != "ext" {
+		t.Errorf("unexpected AssumeRole input: %+v", input)
+	}
+}
+
+func TestRoute53Provider_AssumeRoleRefreshesBeforeExpiry(t *testing.T) {
+	stsClient := &mockSTSClient{expiresIn: 30 * time.Second}
+	p := &Route53Provider{Cfg: &Route53Config{AssumeRoleARN: "arn:aws:iam::123456789012:role/dynago", RoleSessionName: "home"}}
+	creds := p.assumeRoleCredentials(stsClient)
+	for i := 0; i < 2; i++ {
+		if _, err := creds.Retrieve(context.Background()); err != nil {
+			t.Fatalf("Retrieve failed: %v", err)
+		}
+	}
+	if len(stsClient.inputs) != 2 {
+		t.Errorf("expected credentials within the expiry window to be refreshed, got %d AssumeRole calls", len(stsClient.inputs))
+	}
+	if *stsClient.inputs[0].RoleSessionName != "home" {
+		t.Errorf("expected configured session name, got %q", *stsClient.inputs[0].RoleSessionName)
+	}
+}
+
+func TestRoute53Provider_MultipleRecords(t *testing.T) {
+	p, err := New(map[string]any{
+		"enabled":        true,
+		"hosted_zone_id": "zone",
+		"record_names":   []any{"vpn.example.com", "nas.example.com", "router.example.com"},
+		"record_type":    "A",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{
+		aRecord("vpn.example.com", "1.2.3.4"),
+		aRecord("nas.example.com", "1.2.3.4"),
+		aRecord("router.example.com", "5.6.7.8"),
+	}}
+	p.Client = client
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// router.example.com holds 5.6.7.8, so the IP is left empty to force an update of every record.
+	if record.Name != "vpn.example.com" || record.Content != "" {
+		t.Errorf("expected the first configured record with an empty IP, got %+v", record)
+	}
+
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Errorf("expected 3 managed records, got %d", len(records))
+	}
+
+	if err := p.UpdateRecordIP(context.Background(), "9.9.9.9"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.changes) != 1 {
+		t.Fatalf("expected a single change batch, got %d", len(client.changes))
+	}
+	changes := client.changes[0].ChangeBatch.Changes
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes in the batch, got %d", len(changes))
+	}
+	for i, name := range p.Cfg.RecordNames {
+		change := changes[i]
+		if change.Action != r53types.ChangeActionUpsert || *change.ResourceRecordSet.Name != name ||
+			*change.ResourceRecordSet.ResourceRecords[0].Value != "9.9.9.9" {
+			t.Errorf("unexpected change %d: %+v", i, change.ResourceRecordSet)
+		}
+	}
+}
+
+// TestRoute53Provider_DivergentRecordTriggersUpdate checks that a second record holding a different
+// IP makes GetRecordIP mismatch the current IP, and that the update then fixes both records.
+func TestRoute53Provider_MultipleRecordsBatchFails(t *testing.T) {
+	client := &mockRoute53Client{changeErr: errors.New("InvalidChangeBatch")}
+	p := newTestProvider(client)
+	p.Cfg.RecordName, p.Cfg.RecordNames = "", []string{"a.example.com", "b.example.com"}
+
+	err := p.UpdateRecordIP(context.Background(), "9.9.9.9")
+	var multi *providers.MultiError
+	if !errors.As(err, &multi) || multi.Total != 2 || len(multi.Errors) != 2 {
+		t.Fatalf("expected a MultiError with both records, got %v", err)
+	}
+	for i, name := range p.Cfg.RecordNames {
+		if !strings.Contains(multi.Errors[i].Error(), name) {
+			t.Errorf("expected error %d to name %s, got %v", i, name, multi.Errors[i])
+		}
+	}
+
+	p.Cfg.RecordNames = []string{"a.example.com"}
+	err = p.UpdateRecordIP(context.Background(), "9.9.9.9")
+	if errors.As(err, &multi) || err == nil {
+		t.Errorf("expected a plain error for a single record, got %v", err)
+	}
+}
+
+func TestRoute53Provider_DivergentRecordTriggersUpdate(t *testing.T) {
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{
+		aRecord("vpn.example.com", "1.2.3.4"),
+		aRecord("nas.example.com", "1.2.3.4"),
+	}}
+	p := newTestProvider(client)
+	p.Cfg.RecordName = ""
+	p.Cfg.RecordNames = []string{"vpn.example.com", "nas.example.com"}
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil || record.Content != "1.2.3.4" {
+		t.Fatalf("expected 1.2.3.4 while the records agree, got %+v (err %v)", record, err)
+	}
+
+	client.recordSets[1] = aRecord("nas.example.com", "9.9.9.9")
+	const currentIP = "1.2.3.4"
+	record, err = p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content == currentIP {
+		t.Fatalf("expected a divergent second record to mismatch the current IP, got %+v", record)
+	}
+	if err := p.UpdateRecordIP(context.Background(), currentIP); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	changes := client.changes[0].ChangeBatch.Changes
+	if len(changes) != 2 || *changes[1].ResourceRecordSet.Name != "nas.example.com" ||
+		*changes[1].ResourceRecordSet.ResourceRecords[0].Value != currentIP {
+		t.Errorf("expected both records to be set to %s, got %+v", currentIP, changes)
+	}
+}
+
+func TestRoute53Provider_AliasRecord(t *testing.T) {
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{{
+		Name: aws.String("home.example.com."),
+		Type: r53types.RRTypeA,
+		AliasTarget: &r53types.AliasTarget{
+			DNSName:      aws.String("Old-LB.us-east-1.elb.amazonaws.com."),
+			HostedZoneId: aws.String("Z35SXDOTRQ7X7K"),
+		},
+	}}}
+	p := newTestProvider(client)
+	p.Cfg.AliasTarget = "new-lb.us-east-1.elb.amazonaws.com."
+	p.Cfg.AliasHostedZoneID = "Z35SXDOTRQ7X7K"
+	p.Cfg.EvaluateTargetHealth = true
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "old-lb.us-east-1.elb.amazonaws.com" || record.TTL != 0 {
+		t.Errorf("expected normalized alias DNS name without TTL, got %+v", record)
+	}
+	if target, ok := p.StaticTarget(); !ok || target != "new-lb.us-east-1.elb.amazonaws.com" {
+		t.Errorf("StaticTarget() = %q, %v", target, ok)
+	}
+
+	if err := p.UpdateRecordIP(context.Background(), "1.2.3.4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	set := client.changes[0].ChangeBatch.Changes[0].ResourceRecordSet
+	if set.TTL != nil || len(set.ResourceRecords) != 0 {
+		t.Errorf("expected ALIAS record set without TTL or values, got %+v", set)
+	}
+	if set.AliasTarget == nil || *set.AliasTarget.DNSName != p.Cfg.AliasTarget ||
+		*set.AliasTarget.HostedZoneId != "Z35SXDOTRQ7X7K" || !set.AliasTarget.EvaluateTargetHealth {
+		t.Errorf("unexpected alias target: %+v", set.AliasTarget)
+	}
+}
+
+func TestRoute53Provider_WeightedRouting(t *testing.T) {
+	weighted := func(id, ip string) r53types.ResourceRecordSet {
+		record := aRecord("home.example.com", ip)
+		record.SetIdentifier = aws.String(id)
+		record.Weight = aws.Int64(50)
+		return record
+	}
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{
+		weighted("office", "9.9.9.9"),
+		weighted("home", "1.2.3.4"),
+	}}
+	p := newTestProvider(client)
+	p.Cfg.RoutingPolicy = RoutingPolicyWeighted
+	p.Cfg.SetIdentifier = "home"
+	p.Cfg.Weight = aws.Int64(20)
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "1.2.3.4" {
+		t.Errorf("expected the record with set identifier home, got %+v", record)
+	}
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil || len(records) != 1 || records[0].Content != "1.2.3.4" {
+		t.Errorf("expected only the home record to be managed, got %+v (err %v)", records, err)
+	}
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	set := client.changes[0].ChangeBatch.Changes[0].ResourceRecordSet
+	if aws.ToString(set.SetIdentifier) != "home" || aws.ToInt64(set.Weight) != 20 {
+		t.Errorf("expected set identifier home with weight 20, got %q/%d", aws.ToString(set.SetIdentifier), aws.ToInt64(set.Weight))
+	}
+
+	p.Cfg.SetIdentifier = "missing"
+	if _, err := p.GetRecordIP(context.Background()); err == nil {
+		t.Errorf("expected error when no record has the set identifier")
+	}
+}
+
+func TestRoute53Provider_GeolocationRouting(t *testing.T) {
+	located := func(id, continent, country, ip string) r53types.ResourceRecordSet {
+		record := aRecord("home.example.com", ip)
+		record.SetIdentifier = aws.String(id)
+		record.GeoLocation = &r53types.GeoLocation{}
+		if continent != "" {
+			record.GeoLocation.ContinentCode = aws.String(continent)
+		} else {
+			record.GeoLocation.CountryCode = aws.String(country)
+		}
+		return record
+	}
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{
+		located("europe", "EU", "", "9.9.9.9"),
+		located("default", "", "*", "8.8.8.8"),
+		located("germany", "", "DE", "1.2.3.4"),
+	}}
+	p := newTestProvider(client)
+	p.Cfg.RoutingPolicy = RoutingPolicyGeolocation
+	p.Cfg.GeoCountryCode = "DE"
+	p.Cfg.SetIdentifier = "germany"
+
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "1.2.3.4" {
+		t.Errorf("expected the record for DE, got %+v", record)
+	}
+
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	set := client.changes[0].ChangeBatch.Changes[0].ResourceRecordSet
+	if aws.ToString(set.SetIdentifier) != "germany" || set.GeoLocation == nil ||
+		aws.ToString(set.GeoLocation.CountryCode) != "DE" || set.GeoLocation.ContinentCode != nil {
+		t.Errorf("expected set identifier germany located in DE, got %q/%+v", aws.ToString(set.SetIdentifier), set.GeoLocation)
+	}
+
+	p.Cfg.GeoCountryCode, p.Cfg.GeoContinentCode, p.Cfg.SetIdentifier = "", "EU", "europe"
+	record, err = p.GetRecordIP(context.Background())
+	if err != nil || record.Content != "9.9.9.9" {
+		t.Errorf("expected the record for continent EU, got %+v (err %v)", record, err)
+	}
+
+	p.Cfg.GeoContinentCode = "AS"
+	if _, err := p.GetRecordIP(context.Background()); err == nil {
+		t.Errorf("expected error when no record has the location")
+	}
+}
+
 func TestRoute53Provider_ProviderName(t *testing.T) {
 	p := &Route53Provider{Cfg: &Route53Config{}}
 	if p.ProviderName() != "route53" {
 		t.Errorf("expected provider name 'route53'")
 	}
 }
+
+func TestRoute53Provider_RecordNames(t *testing.T) {
+	var p providers.RecordNamer = &Route53Provider{Cfg: &Route53Config{RecordNames: []string{"a.example.com", "b.example.com"}}}
+	if got := p.RecordNames(); len(got) != 2 || got[0] != "a.example.com" || got[1] != "b.example.com" {
+		t.Errorf("RecordNames() = %v, want every name in record_names", got)
+	}
+}
+
+func TestRoute53Provider_CloseReinitializesClient(t *testing.T) {
+	p := &Route53Provider{Cfg: &Route53Config{AccessKeyID: "id", SecretAccessKey: "secret", Region: "us-east-1"}}
+	first, err := p.getClient(context.Background())
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if p.Client != nil {
+		t.Errorf("expected Close to release the client")
+	}
+	second, err := p.getClient(context.Background())
+	if err != nil {
+		t.Fatalf("getClient after Close failed: %v", err)
+	}
+	if second == nil || second == first {
+		t.Errorf("expected getClient to create a new client after Close")
+	}
+}
+
+func TestRoute53Provider_GetRecordIP(t *testing.T) {
+	p := newTestProvider(&mockRoute53Client{recordSets: []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")}})
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "1.2.3.4" || record.TTL != 300 || record.Name != "home.example.com" || record.Proxied != nil {
+		t.Errorf("unexpected record: %+v", record)
+	}
+}
+
+func TestRoute53Provider_GetRecordIP_Paginated(t *testing.T) {
+	client := &mockRoute53Client{pages: []*route53.ListResourceRecordSetsOutput{
+		{
+			ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("a.example.com", "9.9.9.9")},
+			IsTruncated:        true,
+			NextRecordName:     aws.String("home.example.com."),
+			NextRecordType:     r53types.RRTypeA,
+		},
+		{ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")}},
+	}}
+	p := newTestProvider(client)
+	record, err := p.GetRecordIP(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Content != "1.2.3.4" {
+		t.Errorf("expected record from the second page, got %+v", record)
+	}
+	if len(client.listInputs) != 2 {
+		t.Fatalf("expected 2 list calls, got %d", len(client.listInputs))
+	}
+	if first := client.listInputs[0]; first.MaxItems != nil || *first.StartRecordName != "home.example.com" {
+		t.Errorf("expected first page to start at the record without a MaxItems limit, got %+v", first)
+	}
+	if second := client.listInputs[1]; *second.StartRecordName != "home.example.com." || second.StartRecordType != r53types.RRTypeA {
+		t.Errorf("expected second page to start at NextRecordName/NextRecordType, got %+v", second)
+	}
+}
+
+func TestRoute53Provider_GetRecordIP_StopsPastRecord(t *testing.T) {
+	client := &mockRoute53Client{pages: []*route53.ListResourceRecordSetsOutput{
+		{
+			ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("mail.example.com", "9.9.9.9")},
+			IsTruncated:        true,
+			NextRecordName:     aws.String("www.example.com."),
+			NextRecordType:     r53types.RRTypeA,
+		},
+		{ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")}},
+	}}
+	p := newTestProvider(client)
+	if _, err := p.GetRecordIP(context.Background()); err == nil {
+		t.Error("expected the record not to be found")
+	}
+	if len(client.listInputs) != 1 {
+		t.Errorf("expected listing to stop after the first page, got %d list calls", len(client.listInputs))
+	}
+}
+
+func TestRoute53Provider_DeleteRecord(t *testing.T) {
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")}}
+	p := newTestProvider(client)
+	if err := p.DeleteRecord(context.Background(), "home.example.com", "A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.changes) != 1 {
+		t.Fatalf("expected 1 change batch, got %d", len(client.changes))
+	}
+	change := client.changes[0].ChangeBatch.Changes[0]
+	if change.Action != r53types.ChangeActionDelete {
+		t.Errorf("expected DELETE action, got %s", change.Action)
+	}
+	if *change.ResourceRecordSet.ResourceRecords[0].Value != "1.2.3.4" {
+		t.Errorf("expected delete to match existing record value")
+	}
+}
+
+func TestRoute53Provider_DeleteRecord_NotFound(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newTestProvider(client)
+	if err := p.DeleteRecord(context.Background(), "missing.example.com", "A"); err == nil {
+		t.Errorf("expected error when record does not exist")
+	}
+	if len(client.changes) != 0 {
+		t.Errorf("expected no change batch for missing record, got %d", len(client.changes))
+	}
+}
+
+func TestRoute53Provider_ListManagedRecords(t *testing.T) {
+	client := &mockRoute53Client{recordSets: []r53types.ResourceRecordSet{
+		aRecord("home.example.com", "1.2.3.4"),
+		aRecord("other.example.com", "5.6.7.8"),
+	}}
+	p := newTestProvider(client)
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 managed record, got %d", len(records))
+	}
+	got := records[0]
+	if got.Name != "home.example.com" || got.Content != "1.2.3.4" || got.TTL != 300 || got.Provider != "route53" {
+		t.Errorf("unexpected record: %+v", got)
+	}
+}
+
+func TestRoute53Provider_ListManagedRecords_Paginated(t *testing.T) {
+	client := &mockRoute53Client{pages: []*route53.ListResourceRecordSetsOutput{
+		{
+			ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("a.example.com", "9.9.9.9")},
+			IsTruncated:        true,
+			NextRecordName:     aws.String("home.example.com."),
+			NextRecordType:     r53types.RRTypeA,
+		},
+		{ResourceRecordSets: []r53types.ResourceRecordSet{aRecord("home.example.com", "1.2.3.4")}},
+	}}
+	p := newTestProvider(client)
+	records, err := p.ListManagedRecords(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].Content != "1.2.3.4" {
+		t.Errorf("expected the record from the second page, got %+v", records)
+	}
+	if len(client.listInputs) != 2 {
+		t.Fatalf("expected 2 list calls, got %d", len(client.listInputs))
+	}
+	if second := client.listInputs[1]; *second.StartRecordName != "home.example.com." || second.StartRecordType != r53types.RRTypeA {
+		t.Errorf("expected second page to start at NextRecordName/NextRecordType, got %+v", second)
+	}
+}
+
+func TestRoute53Provider_SelfTest(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newTestProvider(client)
+	if err := p.SelfTest(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	client.listZonesErr = errors.New("access denied")
+	if err := p.SelfTest(context.Background()); err == nil {
+		t.Errorf("expected error when ListHostedZones fails")
+	}
+	if len(client.changes) != 0 {
+		t.Errorf("SelfTest must not modify DNS records")
+	}
+}
+
+func TestRoute53Provider_HealthCheck(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newTestProvider(client)
+	if err := p.HealthCheck(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	client.getZoneErr = &smithy.GenericAPIError{Code: "NoSuchHostedZone", Message: "zone not found"}
+	err := p.HealthCheck(context.Background())
+	var pe *providers.ProviderError
+	if !errors.As(err, &pe) || pe.Op != "health check" {
+		t.Errorf("expected health check ProviderError, got %v", err)
+	}
+}
+
+func TestRoute53Provider_ProviderErrorFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		check func(*providers.ProviderError) bool
+	}{
+		{"access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, func(pe *providers.ProviderError) bool { return pe.Unauthorized }},
+		{"throttled", &smithy.GenericAPIError{Code: "Throttling"}, func(pe *providers.ProviderError) bool { return pe.RateLimited && pe.Temporary }},
+		{"prior request", &smithy.GenericAPIError{Code: "PriorRequestNotComplete"}, func(pe *providers.ProviderError) bool { return pe.Temporary && !pe.RateLimited }},
+		{"no zone", &smithy.GenericAPIError{Code: "NoSuchHostedZone"}, func(pe *providers.ProviderError) bool { return pe.NotFound }},
+		{"missing record", nil, func(pe *providers.ProviderError) bool { return pe.NotFound }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(&mockRoute53Client{listErr: tt.err})
+			_, err := p.GetRecordIP(context.Background())
+			var pe *providers.ProviderError
+			if !errors.As(err, &pe) {
+				t.Fatalf("expected *ProviderError, got %T: %v", err, err)
+			}
+			if pe.Provider != "route53" || !tt.check(pe) {
+				t.Errorf("unexpected classification: %+v", pe)
+			}
+		})
+	}
+}
+
+func TestRoute53Provider_MissingRecordIsUpserted(t *testing.T) {
+	client := &mockRoute53Client{}
+	p := newTestProvider(client)
+
+	if _, err := p.GetRecordIP(context.Background()); !errors.Is(err, providers.ErrRecordNotFound) {
+		t.Fatalf("expected ErrRecordNotFound for an empty listing, got %v", err)
+	}
+	if err := p.UpdateRecordIP(context.Background(), "5.6.7.8"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(client.changes) != 1 || client.changes[0].ChangeBatch.Changes[0].Action != r53types.ChangeActionUpsert {
+		t.Errorf("expected the missing record to be created with an UPSERT, got %+v", client.changes)
+	}
+}
+
+func TestRoute53Provider_ProviderConfig(t *testing.T) {
+	p := newTestProvider(&mockRoute53Client{})
+	p.Cfg.AccessKeyID = "AKIA"
+	p.Cfg.SecretAccessKey = "secret"
+	p.Cfg.Region = "us-east-1"
+	got := p.ProviderConfig()
+	want := map[string]string{
+		"access_key_id":     providers.Redacted,
+		"secret_access_key": providers.Redacted,
+		"hosted_zone_id":    "zone",
+		"record_name":       "home.example.com",
+		"region":            "us-east-1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProviderConfig() = %v, want %v", got, want)
+	}
+}